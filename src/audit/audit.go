@@ -0,0 +1,355 @@
+// Package audit writes the audit trail of the use cases to a dedicated JSON Lines file,
+// apart from the application log. Each entry carries the hash of the previous one, so that
+// Verify detects an entry modified, inserted or removed anywhere but at the end of the chain.
+package audit
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"live-semantic/src/domain/uc"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// rotatedLayout is the timestamp of the rotated files, sortable as a string.
+const rotatedLayout = "20060102T150405.000000000Z"
+
+// Entry is a line of the audit log.
+type Entry struct {
+	// Seq numbers the entries from 1, across the rotated files.
+	Seq uint64 `json:"seq"`
+	uc.AuditRecord
+	// Anchor marks the first entry of a file continuing the chain of a previous file, so that
+	// the chain can be verified once the oldest files are removed.
+	Anchor *Anchor `json:"anchor,omitempty"`
+	// PrevHash is the hash of the previous entry, empty for the first entry of the chain.
+	PrevHash string `json:"prev_hash"`
+	// Hash is the SHA-256 of the JSON form of the entry without its hash.
+	Hash string `json:"hash"`
+}
+
+// Anchor is the last entry of the previous file.
+type Anchor struct {
+	Seq  uint64 `json:"seq"`
+	Hash string `json:"hash"`
+}
+
+// computeHash returns the hash of the entry, chained through its PrevHash.
+func (e Entry) computeHash() (string, error) {
+	e.Hash = ""
+	data, err := json.Marshal(e)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// Log appends the audit entries to the active file and rotates it into files named after
+// the active one, e.g. audit-20261019T101500.000000000Z.jsonl for audit.jsonl.
+// The processes sharing the file are serialized by a file lock where the platform has one.
+type Log struct {
+	path    string
+	maxSize int64
+	maxAge  time.Duration
+	onError func(error)
+	now     func() time.Time
+	mu      sync.Mutex
+}
+
+// Option configures the audit log.
+type Option func(*Log)
+
+// WithMaxSize rotates the active file once it reaches size bytes, 0 disables the size rotation.
+func WithMaxSize(size int64) Option {
+	return func(l *Log) {
+		l.maxSize = size
+	}
+}
+
+// WithMaxAge rotates the active file once its first entry is older than age, 0 disables the time rotation.
+func WithMaxAge(age time.Duration) Option {
+	return func(l *Log) {
+		l.maxAge = age
+	}
+}
+
+// WithErrorHandler reports the entries that could not be written, Record having no error to return.
+func WithErrorHandler(handler func(error)) Option {
+	return func(l *Log) {
+		l.onError = handler
+	}
+}
+
+// WithClock replaces the clock deciding the time rotation and naming the rotated files.
+func WithClock(now func() time.Time) Option {
+	return func(l *Log) {
+		l.now = now
+	}
+}
+
+// Open prepares the audit log of the active file path, creating its directory.
+func Open(path string, options ...Option) (*Log, error) {
+	if path == "" {
+		return nil, errors.New("audit: empty path")
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return nil, fmt.Errorf("audit: %w", err)
+	}
+
+	l := &Log{path: path, now: time.Now}
+	for _, option := range options {
+		option(l)
+	}
+	return l, nil
+}
+
+// Path returns the active file.
+func (l *Log) Path() string {
+	return l.path
+}
+
+// Record implements uc.Auditor, a failed write is reported to the error handler.
+func (l *Log) Record(ctx context.Context, record uc.AuditRecord) {
+	if _, err := l.Append(record); err != nil && l.onError != nil {
+		l.onError(fmt.Errorf("%s: %w", record.Action, err))
+	}
+}
+
+// Append chains record to the last entry and writes it, rotating the active file beforehand when due.
+// It refuses to extend a chain whose last entry cannot be read, Verify tells where it breaks.
+func (l *Log) Append(record uc.AuditRecord) (Entry, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	file, err := l.openActive()
+	if err != nil {
+		return Entry{}, err
+	}
+	defer func() { closeLocked(file) }()
+
+	last, err := l.last(file)
+	if err != nil {
+		return Entry{}, err
+	}
+
+	if due, err := l.rotationDue(file); err != nil {
+		return Entry{}, err
+	} else if due {
+		if err := os.Rename(l.path, l.rotatedPath()); err != nil {
+			return Entry{}, fmt.Errorf("audit: rotate: %w", err)
+		}
+		// The lock of the rotated file is kept until the new file is locked,
+		// the processes waiting for it find out about the rotation once they get it
+		next, err := l.openActive()
+		if err != nil {
+			return Entry{}, err
+		}
+		closeLocked(file)
+		file = next
+	}
+
+	entry := Entry{Seq: last.Seq + 1, AuditRecord: record, PrevHash: last.Hash}
+	if info, err := file.Stat(); err != nil {
+		return Entry{}, fmt.Errorf("audit: %w", err)
+	} else if info.Size() == 0 && last.Seq > 0 {
+		entry.Anchor = &Anchor{Seq: last.Seq, Hash: last.Hash}
+	}
+	if entry.Time.IsZero() {
+		entry.Time = l.now().UTC()
+	}
+	if entry.Hash, err = entry.computeHash(); err != nil {
+		return Entry{}, fmt.Errorf("audit: %w", err)
+	}
+
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return Entry{}, fmt.Errorf("audit: %w", err)
+	}
+	if _, err := file.Write(append(line, '\n')); err != nil {
+		return Entry{}, fmt.Errorf("audit: write: %w", err)
+	}
+	if err := file.Sync(); err != nil {
+		return Entry{}, fmt.Errorf("audit: sync: %w", err)
+	}
+	return entry, nil
+}
+
+// openActive opens and locks the active file, again if another process rotated it meanwhile.
+func (l *Log) openActive() (*os.File, error) {
+	for {
+		file, err := os.OpenFile(l.path, os.O_RDWR|os.O_CREATE|os.O_APPEND, 0o600)
+		if err != nil {
+			return nil, fmt.Errorf("audit: %w", err)
+		}
+		if err := lockFile(file); err != nil {
+			file.Close()
+			return nil, fmt.Errorf("audit: lock: %w", err)
+		}
+
+		opened, err := file.Stat()
+		if err != nil {
+			closeLocked(file)
+			return nil, fmt.Errorf("audit: %w", err)
+		}
+		current, err := os.Stat(l.path)
+		if err == nil && os.SameFile(opened, current) {
+			return file, nil
+		}
+		closeLocked(file)
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("audit: %w", err)
+		}
+	}
+}
+
+// closeLocked releases the lock of file and closes it.
+func closeLocked(file *os.File) {
+	_ = unlockFile(file)
+	_ = file.Close()
+}
+
+// last returns the last entry of the chain: the last line of the active file or,
+// when it is still empty, of the most recent rotated file. The zero entry starts a new chain.
+func (l *Log) last(file *os.File) (Entry, error) {
+	info, err := file.Stat()
+	if err != nil {
+		return Entry{}, fmt.Errorf("audit: %w", err)
+	}
+	if info.Size() > 0 {
+		return lastEntry(file, info.Size(), l.path)
+	}
+
+	rotated, err := rotatedFiles(l.path)
+	if err != nil || len(rotated) == 0 {
+		return Entry{}, err
+	}
+	previous, err := os.Open(rotated[len(rotated)-1])
+	if err != nil {
+		return Entry{}, fmt.Errorf("audit: %w", err)
+	}
+	defer previous.Close()
+	info, err = previous.Stat()
+	if err != nil || info.Size() == 0 {
+		return Entry{}, err
+	}
+	return lastEntry(previous, info.Size(), previous.Name())
+}
+
+// rotationDue tells whether the active file reached its maximum size or age.
+func (l *Log) rotationDue(file *os.File) (bool, error) {
+	info, err := file.Stat()
+	if err != nil {
+		return false, fmt.Errorf("audit: %w", err)
+	}
+	if info.Size() == 0 {
+		return false, nil
+	}
+	if l.maxSize > 0 && info.Size() >= l.maxSize {
+		return true, nil
+	}
+	if l.maxAge <= 0 {
+		return false, nil
+	}
+
+	line, err := bufio.NewReader(io.NewSectionReader(file, 0, info.Size())).ReadBytes('\n')
+	if err != nil && !errors.Is(err, io.EOF) {
+		return false, fmt.Errorf("audit: %w", err)
+	}
+	var first Entry
+	if err := json.Unmarshal(line, &first); err != nil {
+		return false, fmt.Errorf("audit: %s: first entry: %w", l.path, err)
+	}
+	return l.now().Sub(first.Time) >= l.maxAge, nil
+}
+
+// rotatedPath returns the name of the file the active file is rotated into.
+func (l *Log) rotatedPath() string {
+	ext := filepath.Ext(l.path)
+	return strings.TrimSuffix(l.path, ext) + "-" + l.now().UTC().Format(rotatedLayout) + ext
+}
+
+// Files returns the rotated files of the active file path, oldest first, followed by the active file when it exists.
+func Files(path string) ([]string, error) {
+	files, err := rotatedFiles(path)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := os.Stat(path); err == nil {
+		files = append(files, path)
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("audit: %w", err)
+	}
+	return files, nil
+}
+
+// rotatedFiles returns the rotated files of the active file path, oldest first.
+func rotatedFiles(path string) ([]string, error) {
+	ext := filepath.Ext(path)
+	prefix := strings.TrimSuffix(path, ext) + "-"
+	matches, err := filepath.Glob(globEscape(prefix) + "*" + globEscape(ext))
+	if err != nil {
+		return nil, fmt.Errorf("audit: %w", err)
+	}
+
+	files := matches[:0]
+	for _, match := range matches {
+		stamp := strings.TrimSuffix(strings.TrimPrefix(match, prefix), ext)
+		if _, err := time.Parse(rotatedLayout, stamp); err == nil {
+			files = append(files, match)
+		}
+	}
+	slices.Sort(files)
+	return files, nil
+}
+
+// globEscape escapes the glob metacharacters of a literal path.
+func globEscape(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		if strings.ContainsRune(`*?[\`, r) {
+			b.WriteByte('\\')
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// lastEntry decodes the last line of a file of size bytes.
+func lastEntry(file io.ReaderAt, size int64, name string) (Entry, error) {
+	chunk := int64(4096)
+	for {
+		offset := max(0, size-chunk)
+		buf := make([]byte, size-offset)
+		if _, err := file.ReadAt(buf, offset); err != nil && !errors.Is(err, io.EOF) {
+			return Entry{}, fmt.Errorf("audit: %w", err)
+		}
+
+		if !bytes.HasSuffix(buf, []byte("\n")) {
+			return Entry{}, fmt.Errorf("%w: %s: last entry is truncated", ErrTampered, name)
+		}
+		body := buf[:len(buf)-1]
+		start := bytes.LastIndexByte(body, '\n')
+		if start < 0 && offset > 0 {
+			chunk *= 2
+			continue
+		}
+
+		var entry Entry
+		if err := json.Unmarshal(body[start+1:], &entry); err != nil {
+			return Entry{}, fmt.Errorf("%w: %s: last entry: %v", ErrTampered, name, err)
+		}
+		return entry, nil
+	}
+}
@@ -0,0 +1,255 @@
+package audit_test
+
+import (
+	"bytes"
+	"live-semantic/src/audit"
+	"live-semantic/src/domain/uc"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// clock is a clock advanced by the tests
+type clock struct {
+	now time.Time
+}
+
+func (c *clock) Now() time.Time {
+	return c.now
+}
+
+func record(action string) uc.AuditRecord {
+	return uc.AuditRecord{
+		Time:      time.Date(2026, 10, 19, 10, 0, 0, 0, time.UTC),
+		Principal: "alice",
+		Source:    "web",
+		RequestID: "req-1",
+		Action:    action,
+		Outcome:   uc.OutcomeSuccess,
+	}
+}
+
+func TestLog_Append(t *testing.T) {
+	t.Run("should chain each entry to the previous one", func(t *testing.T) {
+		// Given
+		path := filepath.Join(t.TempDir(), "audit.jsonl")
+		log, err := audit.Open(path)
+		if !assert.NoError(t, err) {
+			return
+		}
+
+		// When
+		first, err := log.Append(record(uc.ActionTaskCreate))
+		if !assert.NoError(t, err) {
+			return
+		}
+		second, err := log.Append(record(uc.ActionTaskCreate))
+		if !assert.NoError(t, err) {
+			return
+		}
+
+		// Then
+		assert.Equal(t, uint64(1), first.Seq)
+		assert.Empty(t, first.PrevHash)
+		assert.Equal(t, uint64(2), second.Seq)
+		assert.Equal(t, first.Hash, second.PrevHash)
+
+		report, err := audit.Verify(path)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, report.Entries)
+		assert.Equal(t, second.Hash, report.LastHash)
+	})
+
+	t.Run("should continue the chain across the rotated files", func(t *testing.T) {
+		// Given
+		dir := t.TempDir()
+		path := filepath.Join(dir, "audit.jsonl")
+		now := &clock{now: time.Date(2026, 10, 19, 10, 0, 0, 0, time.UTC)}
+		log, err := audit.Open(path, audit.WithMaxAge(time.Hour), audit.WithClock(now.Now))
+		if !assert.NoError(t, err) {
+			return
+		}
+
+		// When
+		_, err = log.Append(record("apikey.create"))
+		if !assert.NoError(t, err) {
+			return
+		}
+		now.now = now.now.Add(2 * time.Hour)
+		_, err = log.Append(record("apikey.revoke"))
+		if !assert.NoError(t, err) {
+			return
+		}
+
+		// Then
+		files, err := audit.Files(path)
+		if !assert.NoError(t, err) {
+			return
+		}
+		assert.Equal(t, []string{filepath.Join(dir, "audit-20261019T120000.000000000Z.jsonl"), path}, files)
+
+		report, err := audit.Verify(path)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, report.Entries)
+		assert.Equal(t, uint64(2), report.LastSeq)
+	})
+
+	t.Run("should rotate the active file once it reaches its maximum size", func(t *testing.T) {
+		// Given
+		path := filepath.Join(t.TempDir(), "audit.jsonl")
+		log, err := audit.Open(path, audit.WithMaxSize(1))
+		if !assert.NoError(t, err) {
+			return
+		}
+
+		// When
+		for i := 0; i < 3; i++ {
+			_, err := log.Append(record(uc.ActionTaskCreate))
+			if !assert.NoError(t, err) {
+				return
+			}
+			time.Sleep(time.Millisecond)
+		}
+
+		// Then
+		files, err := audit.Files(path)
+		if !assert.NoError(t, err) {
+			return
+		}
+		assert.Len(t, files, 3)
+		report, err := audit.Verify(path)
+		assert.NoError(t, err)
+		assert.Equal(t, 3, report.Entries)
+	})
+}
+
+func TestVerify(t *testing.T) {
+	// appendEntries writes three entries and returns the lines of the active file
+	appendEntries := func(t *testing.T, path string) [][]byte {
+		log, err := audit.Open(path)
+		if !assert.NoError(t, err) {
+			return nil
+		}
+		for _, action := range []string{"apikey.create", uc.ActionTaskCreate, "apikey.revoke"} {
+			_, err := log.Append(record(action))
+			if !assert.NoError(t, err) {
+				return nil
+			}
+		}
+		data, err := os.ReadFile(path)
+		if !assert.NoError(t, err) {
+			return nil
+		}
+		return bytes.SplitAfter(data, []byte("\n"))[:3]
+	}
+
+	t.Run("should detect a modified entry", func(t *testing.T) {
+		// Given
+		path := filepath.Join(t.TempDir(), "audit.jsonl")
+		lines := appendEntries(t, path)
+		lines[1] = bytes.Replace(lines[1], []byte(`"principal":"alice"`), []byte(`"principal":"bob"`), 1)
+		if !assert.NoError(t, os.WriteFile(path, bytes.Join(lines, nil), 0o600)) {
+			return
+		}
+
+		// When
+		_, err := audit.Verify(path)
+
+		// Then
+		assert.ErrorIs(t, err, audit.ErrTampered)
+		assert.ErrorContains(t, err, "audit.jsonl:2: entry 2 does not match its hash")
+	})
+
+	t.Run("should detect a removed entry", func(t *testing.T) {
+		// Given
+		path := filepath.Join(t.TempDir(), "audit.jsonl")
+		lines := appendEntries(t, path)
+		if !assert.NoError(t, os.WriteFile(path, bytes.Join([][]byte{lines[0], lines[2]}, nil), 0o600)) {
+			return
+		}
+
+		// When
+		_, err := audit.Verify(path)
+
+		// Then
+		assert.ErrorIs(t, err, audit.ErrTampered)
+		assert.ErrorContains(t, err, "entry 3 follows entry 1")
+	})
+
+	t.Run("should detect removed leading entries", func(t *testing.T) {
+		// Given
+		path := filepath.Join(t.TempDir(), "audit.jsonl")
+		lines := appendEntries(t, path)
+		if !assert.NoError(t, os.WriteFile(path, lines[2], 0o600)) {
+			return
+		}
+
+		// When
+		_, err := audit.Verify(path)
+
+		// Then
+		assert.ErrorIs(t, err, audit.ErrTampered)
+		assert.ErrorContains(t, err, "audit.jsonl:1: entry 3 does not start a chain")
+	})
+
+	t.Run("should verify the chain from the anchor of the oldest remaining file", func(t *testing.T) {
+		// Given
+		dir := t.TempDir()
+		path := filepath.Join(dir, "audit.jsonl")
+		now := &clock{now: time.Date(2026, 10, 19, 10, 0, 0, 0, time.UTC)}
+		log, err := audit.Open(path, audit.WithMaxAge(time.Hour), audit.WithClock(now.Now))
+		if !assert.NoError(t, err) {
+			return
+		}
+		for _, action := range []string{"apikey.create", "apikey.revoke"} {
+			if _, err := log.Append(record(action)); !assert.NoError(t, err) {
+				return
+			}
+		}
+		now.now = now.now.Add(2 * time.Hour)
+		third, err := log.Append(record(uc.ActionTaskCreate))
+		if !assert.NoError(t, err) {
+			return
+		}
+		files, err := audit.Files(path)
+		if !assert.NoError(t, err) || !assert.Len(t, files, 2) {
+			return
+		}
+		if !assert.NoError(t, os.Remove(files[0])) {
+			return
+		}
+
+		// When
+		report, err := audit.Verify(path)
+
+		// Then
+		assert.NoError(t, err)
+		assert.Equal(t, &audit.Anchor{Seq: 2, Hash: third.PrevHash}, third.Anchor)
+		assert.Equal(t, uint64(3), report.FirstSeq)
+		assert.Equal(t, 1, report.Entries)
+	})
+
+	t.Run("should refuse to extend a truncated log", func(t *testing.T) {
+		// Given
+		path := filepath.Join(t.TempDir(), "audit.jsonl")
+		lines := appendEntries(t, path)
+		if !assert.NoError(t, os.WriteFile(path, bytes.Join([][]byte{lines[0], lines[1][:20]}, nil), 0o600)) {
+			return
+		}
+		log, err := audit.Open(path)
+		if !assert.NoError(t, err) {
+			return
+		}
+
+		// When
+		_, appendErr := log.Append(record(uc.ActionTaskCreate))
+		_, verifyErr := audit.Verify(path)
+
+		// Then
+		assert.ErrorIs(t, appendErr, audit.ErrTampered)
+		assert.ErrorIs(t, verifyErr, audit.ErrTampered)
+	})
+}
@@ -0,0 +1,15 @@
+//go:build !unix
+
+package audit
+
+import "os"
+
+// lockFile is a no-op without flock, a single process must then write the audit log.
+func lockFile(file *os.File) error {
+	return nil
+}
+
+// unlockFile is a no-op without flock.
+func unlockFile(file *os.File) error {
+	return nil
+}
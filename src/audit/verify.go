@@ -0,0 +1,105 @@
+package audit
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+)
+
+// ErrTampered is returned when the chain of the audit log is broken.
+var ErrTampered = errors.New("audit log tampered")
+
+// Report summarizes a verified audit log.
+type Report struct {
+	// Files are the verified files, oldest first.
+	Files []string `json:"files"`
+	// Entries is the number of verified entries.
+	Entries int `json:"entries"`
+	// FirstSeq is above 1 when the oldest rotated files were removed, the chain is then
+	// only verified from the anchor of the first remaining file.
+	FirstSeq uint64 `json:"first_seq"`
+	LastSeq  uint64 `json:"last_seq"`
+	// LastHash anchors the chain: an entry removed at the end is only detected
+	// by comparing it with a hash noted beforehand.
+	LastHash string `json:"last_hash"`
+}
+
+// Verify checks the chain of the audit log of the active file path, across its rotated files,
+// and stops at the first broken link with an error wrapping ErrTampered. The chain starts with
+// entry 1, or with the anchored first entry of a file when the oldest files were removed.
+func Verify(path string) (Report, error) {
+	files, err := Files(path)
+	if err != nil {
+		return Report{}, err
+	}
+	if len(files) == 0 {
+		return Report{}, fmt.Errorf("audit: no audit log at %s", path)
+	}
+
+	report := Report{Files: files}
+	var previous *Entry
+	for _, name := range files {
+		if err := verifyFile(name, &report, &previous); err != nil {
+			return report, err
+		}
+	}
+	return report, nil
+}
+
+// verifyFile checks the entries of a file against each other and against the previous entry.
+func verifyFile(name string, report *Report, previous **Entry) error {
+	file, err := os.Open(name)
+	if err != nil {
+		return fmt.Errorf("audit: %w", err)
+	}
+	defer file.Close()
+
+	reader := bufio.NewReader(file)
+	for line := 1; ; line++ {
+		data, err := reader.ReadBytes('\n')
+		if len(data) == 0 && err != nil {
+			break
+		}
+		if data[len(data)-1] != '\n' {
+			return fmt.Errorf("%w: %s:%d: truncated entry", ErrTampered, name, line)
+		}
+
+		var entry Entry
+		if err := json.Unmarshal(data, &entry); err != nil {
+			return fmt.Errorf("%w: %s:%d: %v", ErrTampered, name, line, err)
+		}
+		hash, err := entry.computeHash()
+		if err != nil {
+			return fmt.Errorf("audit: %w", err)
+		}
+		if hash != entry.Hash {
+			return fmt.Errorf("%w: %s:%d: entry %d does not match its hash", ErrTampered, name, line, entry.Seq)
+		}
+
+		if entry.Anchor != nil && (line != 1 || entry.Anchor.Seq+1 != entry.Seq || entry.Anchor.Hash != entry.PrevHash) {
+			return fmt.Errorf("%w: %s:%d: entry %d has a misplaced anchor", ErrTampered, name, line, entry.Seq)
+		}
+		if *previous == nil {
+			// Without anchor, a chain starting after entry 1 lost its first entries
+			if entry.Seq == 0 || (entry.Seq == 1 && entry.PrevHash != "") || (entry.Seq > 1 && entry.Anchor == nil) {
+				return fmt.Errorf("%w: %s:%d: entry %d does not start a chain", ErrTampered, name, line, entry.Seq)
+			}
+			report.FirstSeq = entry.Seq
+		} else {
+			if entry.Seq != (*previous).Seq+1 {
+				return fmt.Errorf("%w: %s:%d: entry %d follows entry %d", ErrTampered, name, line, entry.Seq, (*previous).Seq)
+			}
+			if entry.PrevHash != (*previous).Hash {
+				return fmt.Errorf("%w: %s:%d: entry %d is not chained to entry %d", ErrTampered, name, line, entry.Seq, (*previous).Seq)
+			}
+		}
+
+		*previous = &entry
+		report.Entries++
+		report.LastSeq = entry.Seq
+		report.LastHash = entry.Hash
+	}
+	return nil
+}
@@ -0,0 +1,121 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// KeyPrefix starts every API key, the key ID follows up to the next underscore.
+const KeyPrefix = "lsk_"
+
+// Key is an API key as stored: only the SHA-256 hash of the secret is kept.
+type Key struct {
+	ID        string     `json:"id"`
+	Name      string     `json:"name"`
+	Hash      string     `json:"hash"`
+	Scopes    []string   `json:"scopes"`
+	Roles     []string   `json:"roles,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+	RevokedAt *time.Time `json:"revoked_at,omitempty"`
+}
+
+// Revoked reports whether the key was revoked.
+func (k Key) Revoked() bool {
+	return k.RevokedAt != nil
+}
+
+// NewKey generates an API key and returns it with the secret to hand out once.
+func NewKey(name string, scopes, roles []string) (Key, string, error) {
+	if name == "" || strings.ContainsAny(name, ":,") {
+		return Key{}, "", fmt.Errorf("invalid key name %q: must be non-empty without ':' or ','", name)
+	}
+	if err := ValidateGrants(scopes, roles); err != nil {
+		return Key{}, "", err
+	}
+
+	id, err := randomHex(4)
+	if err != nil {
+		return Key{}, "", err
+	}
+	secret, err := randomHex(24)
+	if err != nil {
+		return Key{}, "", err
+	}
+
+	plain := KeyPrefix + id + "_" + secret
+	return Key{
+		ID:        id,
+		Name:      name,
+		Hash:      HashKey(plain),
+		Scopes:    scopes,
+		Roles:     roles,
+		CreatedAt: time.Now().UTC(),
+	}, plain, nil
+}
+
+// HashKey returns the hex SHA-256 hash stored for an API key.
+func HashKey(plain string) string {
+	sum := sha256.Sum256([]byte(plain))
+	return hex.EncodeToString(sum[:])
+}
+
+// Grants returns the scopes and the roles of the key, roles written role:NAME.
+func (k Key) Grants() []string {
+	grants := append([]string(nil), k.Scopes...)
+	for _, role := range k.Roles {
+		grants = append(grants, rolePrefix+role)
+	}
+	return grants
+}
+
+// ConfigEntry returns the auth.api_keys entry declaring the key: NAME:HASH:GRANT,GRANT.
+func (k Key) ConfigEntry() string {
+	return k.Name + ":" + k.Hash + ":" + strings.Join(k.Grants(), ",")
+}
+
+// ParseConfigEntry reads a NAME:HASH:GRANT,GRANT entry of auth.api_keys,
+// where each grant is a scope or a role written role:NAME.
+func ParseConfigEntry(entry string) (Key, error) {
+	parts := strings.SplitN(entry, ":", 3)
+	if len(parts) != 3 || parts[0] == "" {
+		return Key{}, fmt.Errorf("%q must be NAME:SHA256:GRANT,GRANT", redactEntry(entry))
+	}
+
+	name, hash := parts[0], parts[1]
+	if decoded, err := hex.DecodeString(hash); err != nil || len(decoded) != sha256.Size {
+		return Key{}, fmt.Errorf("%q needs the hex SHA-256 hash of the key", redactEntry(entry))
+	}
+
+	var scopes, roles []string
+	for _, grant := range strings.Split(parts[2], ",") {
+		if role, ok := strings.CutPrefix(grant, rolePrefix); ok {
+			roles = append(roles, role)
+		} else {
+			scopes = append(scopes, grant)
+		}
+	}
+	if err := ValidateGrants(scopes, roles); err != nil {
+		return Key{}, fmt.Errorf("%q: %v", redactEntry(entry), err)
+	}
+
+	// Configuration keys are identified by the start of their hash
+	return Key{ID: hash[:8], Name: name, Hash: hash, Scopes: scopes, Roles: roles}, nil
+}
+
+// redactEntry keeps the name of an entry for error messages.
+func redactEntry(entry string) string {
+	name, _, _ := strings.Cut(entry, ":")
+	return name + ":..."
+}
+
+func randomHex(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("generating key: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
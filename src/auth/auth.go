@@ -0,0 +1,125 @@
+// Package auth authenticates the callers of the network transports with API keys or
+// HMAC-signed bearer tokens, and carries their principal and scopes in the request context.
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+)
+
+// Scopes granted to API keys and tokens.
+const (
+	ScopeTasksRead    = "tasks:read"
+	ScopeTasksWrite   = "tasks:write"
+	ScopeFiltersAdmin = "filters:admin"
+	ScopeAlertsAck    = "alerts:ack"
+	ScopeSystemAdmin  = "system:admin"
+)
+
+// Scopes lists the known scopes, also offered by the shell completion.
+var Scopes = []string{ScopeTasksRead, ScopeTasksWrite, ScopeFiltersAdmin, ScopeAlertsAck, ScopeSystemAdmin}
+
+// Roles granted to API keys and tokens, each one bundles a set of scopes.
+const (
+	RoleViewer   = "viewer"
+	RoleAnalyst  = "analyst"
+	RoleOperator = "operator"
+)
+
+// Roles lists the known roles, from the least to the most privileged.
+var Roles = []string{RoleViewer, RoleAnalyst, RoleOperator}
+
+// rolePrefix marks a role among the grants of an auth.api_keys entry.
+const rolePrefix = "role:"
+
+// roleScopes maps each role to the scopes it grants.
+var roleScopes = map[string][]string{
+	RoleViewer:   {ScopeTasksRead},
+	RoleAnalyst:  {ScopeTasksRead, ScopeTasksWrite},
+	RoleOperator: {ScopeTasksRead, ScopeTasksWrite, ScopeFiltersAdmin, ScopeAlertsAck, ScopeSystemAdmin},
+}
+
+// RoleScopes returns the scopes granted by a role.
+func RoleScopes(role string) []string {
+	return slices.Clone(roleScopes[role])
+}
+
+var (
+	// ErrUnauthenticated is returned for missing, unknown, revoked or expired credentials.
+	ErrUnauthenticated = errors.New("unauthenticated")
+	// ErrForbidden is returned when the principal lacks a scope.
+	ErrForbidden = errors.New("forbidden")
+)
+
+// Authentication methods of a principal.
+const (
+	MethodAPIKey = "apikey"
+	MethodToken  = "token"
+)
+
+// Principal is an authenticated caller.
+type Principal struct {
+	// Subject is the name of the API key or the subject of the token.
+	Subject string   `json:"subject"`
+	Method  string   `json:"method"`
+	Scopes  []string `json:"scopes"`
+	Roles   []string `json:"roles,omitempty"`
+}
+
+// Has reports whether the principal was granted the scope, directly or through a role.
+func (p Principal) Has(scope string) bool {
+	if slices.Contains(p.Scopes, scope) {
+		return true
+	}
+	for _, role := range p.Roles {
+		if slices.Contains(roleScopes[role], scope) {
+			return true
+		}
+	}
+	return false
+}
+
+type principalKey struct{}
+
+// WithPrincipal returns a copy of ctx carrying the principal.
+func WithPrincipal(ctx context.Context, principal Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, principal)
+}
+
+// PrincipalFrom returns the principal carried by ctx.
+func PrincipalFrom(ctx context.Context) (Principal, bool) {
+	principal, ok := ctx.Value(principalKey{}).(Principal)
+	return principal, ok
+}
+
+// Check verifies that ctx carries a principal granted the scope.
+func Check(ctx context.Context, scope string) error {
+	principal, ok := PrincipalFrom(ctx)
+	if !ok {
+		return fmt.Errorf("%w: missing credentials", ErrUnauthenticated)
+	}
+	if !principal.Has(scope) {
+		return fmt.Errorf("%w: missing scope %s", ErrForbidden, scope)
+	}
+	return nil
+}
+
+// ValidateGrants rejects unknown scopes and roles, and a credential granted neither.
+func ValidateGrants(scopes, roles []string) error {
+	if len(scopes) == 0 && len(roles) == 0 {
+		return fmt.Errorf("at least one scope or role is required (scopes: %v, roles: %v)", Scopes, Roles)
+	}
+	for _, scope := range scopes {
+		if !slices.Contains(Scopes, scope) {
+			return fmt.Errorf("unknown scope %q (available: %v)", scope, Scopes)
+		}
+	}
+	for _, role := range roles {
+		if !slices.Contains(Roles, role) {
+			return fmt.Errorf("unknown role %q (available: %v)", role, Roles)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,139 @@
+package auth_test
+
+import (
+	"context"
+	"errors"
+	"live-semantic/src/auth"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var secret = []byte("0123456789abcdef0123456789abcdef")
+
+func TestAuthenticator_Authenticate(t *testing.T) {
+	t.Run("should accept a stored key until it is revoked", func(t *testing.T) {
+		// Given
+		store := auth.NewFileStore(filepath.Join(t.TempDir(), "apikeys.json"))
+		key, plain, err := auth.NewKey("ci", []string{auth.ScopeTasksRead}, nil)
+		assert.NoError(t, err)
+		assert.NoError(t, store.Add(key))
+		authenticator := auth.New(auth.WithStore(store))
+
+		// When
+		principal, err := authenticator.Authenticate(plain)
+		_, revokeErr := store.Revoke(key.ID)
+		_, revokedErr := authenticator.Authenticate(plain)
+
+		// Then
+		assert.NoError(t, err)
+		assert.Equal(t, auth.Principal{Subject: "ci", Method: auth.MethodAPIKey, Scopes: []string{auth.ScopeTasksRead}}, principal)
+		assert.NoError(t, revokeErr)
+		assert.ErrorIs(t, revokedErr, auth.ErrUnauthenticated)
+	})
+
+	t.Run("should accept a key declared in the configuration", func(t *testing.T) {
+		// Given
+		key, plain, err := auth.NewKey("ops", []string{auth.ScopeTasksWrite, auth.ScopeAlertsAck}, nil)
+		assert.NoError(t, err)
+		declared, err := auth.ParseConfigEntry(key.ConfigEntry())
+		assert.NoError(t, err)
+
+		// When
+		principal, err := auth.New(auth.WithKeys(declared)).Authenticate(plain)
+
+		// Then
+		assert.NoError(t, err)
+		assert.True(t, principal.Has(auth.ScopeAlertsAck))
+		assert.False(t, principal.Has(auth.ScopeTasksRead))
+	})
+
+	t.Run("should grant the scopes of the roles of a declared key", func(t *testing.T) {
+		// Given
+		key, plain, err := auth.NewKey("console", nil, []string{auth.RoleAnalyst})
+		assert.NoError(t, err)
+		declared, err := auth.ParseConfigEntry(key.ConfigEntry())
+		assert.NoError(t, err)
+
+		// When
+		principal, err := auth.New(auth.WithKeys(declared)).Authenticate(plain)
+
+		// Then
+		assert.NoError(t, err)
+		assert.Equal(t, []string{auth.RoleAnalyst}, principal.Roles)
+		assert.True(t, principal.Has(auth.ScopeTasksWrite))
+		assert.False(t, principal.Has(auth.ScopeFiltersAdmin))
+	})
+
+	t.Run("should verify the signature and the expiry of a token", func(t *testing.T) {
+		// Given
+		authenticator := auth.New(auth.WithTokenSecret(secret))
+		token, err := auth.IssueToken(secret, "bot", []string{auth.ScopeTasksRead}, nil, time.Hour)
+		assert.NoError(t, err)
+		forged, err := auth.IssueToken([]byte("another secret of at least 32 bytes!"), "bot", []string{auth.ScopeTasksRead}, nil, time.Hour)
+		assert.NoError(t, err)
+		expired, err := auth.IssueToken(secret, "bot", []string{auth.ScopeTasksRead}, nil, time.Nanosecond)
+		assert.NoError(t, err)
+		time.Sleep(time.Second)
+
+		// When
+		principal, err := authenticator.Authenticate(token)
+		_, forgedErr := authenticator.Authenticate(forged)
+		_, expiredErr := authenticator.Authenticate(expired)
+
+		// Then
+		assert.NoError(t, err)
+		assert.Equal(t, "bot", principal.Subject)
+		assert.Equal(t, auth.MethodToken, principal.Method)
+		assert.ErrorIs(t, forgedErr, auth.ErrUnauthenticated)
+		assert.ErrorIs(t, expiredErr, auth.ErrUnauthenticated)
+	})
+
+	t.Run("should reject missing and unknown credentials", func(t *testing.T) {
+		// Given
+		authenticator := auth.New()
+
+		// When
+		_, missingErr := authenticator.Authenticate("")
+		_, unknownErr := authenticator.Authenticate("lsk_00000000_unknown")
+
+		// Then
+		assert.ErrorIs(t, missingErr, auth.ErrUnauthenticated)
+		assert.ErrorIs(t, unknownErr, auth.ErrUnauthenticated)
+	})
+}
+
+func TestNewKey(t *testing.T) {
+	t.Run("should reject unknown scopes and roles and invalid names", func(t *testing.T) {
+		// When
+		_, _, scopeErr := auth.NewKey("ci", []string{"tasks:delete"}, nil)
+		_, _, emptyErr := auth.NewKey("ci", nil, nil)
+		_, _, nameErr := auth.NewKey("c:i", []string{auth.ScopeTasksRead}, nil)
+		_, _, roleErr := auth.NewKey("ci", nil, []string{"admin"})
+
+		// Then
+		assert.Error(t, scopeErr)
+		assert.Error(t, emptyErr)
+		assert.Error(t, nameErr)
+		assert.Error(t, roleErr)
+	})
+}
+
+func TestCheck(t *testing.T) {
+	t.Run("should require a principal granted the scope", func(t *testing.T) {
+		// Given
+		ctx := auth.WithPrincipal(context.Background(), auth.Principal{Subject: "ci", Scopes: []string{auth.ScopeTasksRead}})
+
+		// When
+		allowedErr := auth.Check(ctx, auth.ScopeTasksRead)
+		forbiddenErr := auth.Check(ctx, auth.ScopeFiltersAdmin)
+		anonymousErr := auth.Check(context.Background(), auth.ScopeTasksRead)
+
+		// Then
+		assert.NoError(t, allowedErr)
+		assert.True(t, errors.Is(forbiddenErr, auth.ErrForbidden))
+		assert.ErrorIs(t, anonymousErr, auth.ErrUnauthenticated)
+	})
+}
@@ -0,0 +1,89 @@
+package auth
+
+import (
+	"crypto/subtle"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Authenticator resolves the principal of an API key or a bearer token.
+// A nil *Authenticator means authentication is disabled.
+type Authenticator struct {
+	keys   []Key
+	store  *FileStore
+	secret []byte
+	now    func() time.Time
+}
+
+// Option configures an authenticator.
+type Option func(*Authenticator)
+
+// WithKeys accepts the keys declared in the configuration.
+func WithKeys(keys ...Key) Option {
+	return func(a *Authenticator) {
+		a.keys = append(a.keys, keys...)
+	}
+}
+
+// WithStore accepts the non-revoked keys of the store.
+func WithStore(store *FileStore) Option {
+	return func(a *Authenticator) {
+		a.store = store
+	}
+}
+
+// WithTokenSecret accepts the bearer tokens signed with the secret.
+func WithTokenSecret(secret []byte) Option {
+	return func(a *Authenticator) {
+		a.secret = secret
+	}
+}
+
+// New creates an authenticator.
+func New(options ...Option) *Authenticator {
+	a := &Authenticator{now: time.Now}
+	for _, option := range options {
+		option(a)
+	}
+	return a
+}
+
+// Authenticate returns the principal of an API key or a bearer token.
+// Every failure wraps ErrUnauthenticated.
+func (a *Authenticator) Authenticate(credential string) (Principal, error) {
+	switch {
+	case credential == "":
+		return Principal{}, fmt.Errorf("%w: missing credentials", ErrUnauthenticated)
+	case strings.HasPrefix(credential, KeyPrefix):
+		return a.authenticateKey(credential)
+	case strings.HasPrefix(credential, TokenPrefix) && len(a.secret) > 0:
+		return verifyToken(a.secret, credential, a.now())
+	default:
+		return Principal{}, fmt.Errorf("%w: invalid credentials", ErrUnauthenticated)
+	}
+}
+
+// authenticateKey compares the hash of the key with every known key.
+func (a *Authenticator) authenticateKey(credential string) (Principal, error) {
+	keys := a.keys
+	if a.store != nil {
+		stored, err := a.store.Keys()
+		if err != nil {
+			return Principal{}, err
+		}
+		keys = append(append([]Key(nil), keys...), stored...)
+	}
+
+	hash := []byte(HashKey(credential))
+	for _, key := range keys {
+		if subtle.ConstantTimeCompare(hash, []byte(key.Hash)) != 1 {
+			continue
+		}
+		if key.Revoked() {
+			return Principal{}, fmt.Errorf("%w: api key revoked", ErrUnauthenticated)
+		}
+		return Principal{Subject: key.Name, Method: MethodAPIKey, Scopes: key.Scopes, Roles: key.Roles}, nil
+	}
+	return Principal{}, fmt.Errorf("%w: invalid credentials", ErrUnauthenticated)
+}
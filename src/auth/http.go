@@ -0,0 +1,25 @@
+package auth
+
+import (
+	"net/http"
+	"strings"
+)
+
+// KeyHeader carries an API key when the Authorization header is not used.
+const KeyHeader = "X-API-Key"
+
+// tokenQuery carries the credential of the WebSocket and EventSource clients
+// that cannot set headers.
+const tokenQuery = "access_token"
+
+// Credential reads the credential of a request from the bearer Authorization header,
+// the X-API-Key header or the access_token query parameter.
+func Credential(r *http.Request) string {
+	if scheme, credential, ok := strings.Cut(r.Header.Get("Authorization"), " "); ok && strings.EqualFold(scheme, "Bearer") {
+		return strings.TrimSpace(credential)
+	}
+	if key := r.Header.Get(KeyHeader); key != "" {
+		return key
+	}
+	return r.URL.Query().Get(tokenQuery)
+}
@@ -0,0 +1,150 @@
+package auth
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// ErrKeyNotFound is returned when revoking an unknown key.
+var ErrKeyNotFound = errors.New("api key not found")
+
+// FileStore keeps the API keys in a JSON file readable by the owner only.
+// The file is read again when it changes, so keys created or revoked by another
+// process apply without a restart.
+type FileStore struct {
+	path    string
+	mu      sync.Mutex
+	keys    []Key
+	modTime time.Time
+}
+
+// storeFile is the layout of the key file.
+type storeFile struct {
+	Keys []Key `json:"keys"`
+}
+
+// NewFileStore returns a store backed by path, a missing file holds no key.
+func NewFileStore(path string) *FileStore {
+	return &FileStore{path: path}
+}
+
+// Path returns the path of the key file.
+func (s *FileStore) Path() string {
+	return s.path
+}
+
+// Keys returns every key, revoked ones included.
+func (s *FileStore) Keys() ([]Key, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.load(); err != nil {
+		return nil, err
+	}
+	return append([]Key(nil), s.keys...), nil
+}
+
+// Add stores a new key.
+func (s *FileStore) Add(key Key) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.load(); err != nil {
+		return err
+	}
+	return s.save(append(append([]Key(nil), s.keys...), key))
+}
+
+// Revoke marks the key with the ID as revoked, revoking it again is a no-op.
+func (s *FileStore) Revoke(id string) (Key, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.load(); err != nil {
+		return Key{}, err
+	}
+
+	keys := append([]Key(nil), s.keys...)
+	for i, key := range keys {
+		if key.ID != id {
+			continue
+		}
+		if !key.Revoked() {
+			now := time.Now().UTC()
+			keys[i].RevokedAt = &now
+			if err := s.save(keys); err != nil {
+				return Key{}, err
+			}
+		}
+		return keys[i], nil
+	}
+	return Key{}, fmt.Errorf("%w: %s", ErrKeyNotFound, id)
+}
+
+// load reads the file when it changed since the last read.
+func (s *FileStore) load() error {
+	info, err := os.Stat(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		s.keys, s.modTime = nil, time.Time{}
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("reading api keys: %w", err)
+	}
+	if info.ModTime().Equal(s.modTime) && s.keys != nil {
+		return nil
+	}
+
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		return fmt.Errorf("reading api keys: %w", err)
+	}
+	var file storeFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return fmt.Errorf("reading api keys from %s: %w", s.path, err)
+	}
+
+	s.keys = file.Keys
+	if s.keys == nil {
+		s.keys = []Key{}
+	}
+	s.modTime = info.ModTime()
+	return nil
+}
+
+// save replaces the file atomically.
+func (s *FileStore) save(keys []Key) error {
+	data, err := json.MarshalIndent(storeFile{Keys: keys}, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o700); err != nil {
+		return fmt.Errorf("writing api keys: %w", err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), ".apikeys-*")
+	if err != nil {
+		return fmt.Errorf("writing api keys: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(append(data, '\n')); err != nil {
+		tmp.Close()
+		return fmt.Errorf("writing api keys: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("writing api keys: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), s.path); err != nil {
+		return fmt.Errorf("writing api keys: %w", err)
+	}
+
+	// Read the file again on the next access
+	s.keys, s.modTime = keys, time.Time{}
+	return nil
+}
@@ -0,0 +1,286 @@
+// Package client is a typed Go client for the LiveSemantic REST and WebSocket APIs.
+//
+// Requests that the server did not process, because the connection was refused or the
+// server answered 429, 502, 503 or 504, are retried with backoff. Every method honours
+// the cancellation of its context.
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"live-semantic/src/domain/dto"
+	"live-semantic/src/tracing"
+	"live-semantic/src/transport"
+	"net"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultTimeout bounds a single request when no timeout or http.Client is given.
+const DefaultTimeout = 30 * time.Second
+
+// ErrUnreachable is returned when the server does not accept the connection.
+var ErrUnreachable = errors.New("server unreachable")
+
+// APIError is a request rejected by the server.
+type APIError struct {
+	StatusCode int
+	Message    string
+}
+
+// Error implements error.
+func (e *APIError) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("server returned %d %s", e.StatusCode, http.StatusText(e.StatusCode))
+	}
+	return e.Message
+}
+
+// Client calls the REST API of a running server.
+type Client struct {
+	baseURL    *url.URL
+	httpClient *http.Client
+	token      string
+	retry      RetryPolicy
+}
+
+// Option configures a client.
+type Option func(*Client)
+
+// WithToken sends the token as a bearer Authorization header.
+func WithToken(token string) Option {
+	return func(c *Client) {
+		c.token = token
+	}
+}
+
+// WithHTTPClient replaces the underlying http.Client.
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		c.httpClient = httpClient
+	}
+}
+
+// WithTimeout bounds a single request.
+func WithTimeout(timeout time.Duration) Option {
+	return func(c *Client) {
+		c.httpClient.Timeout = timeout
+	}
+}
+
+// WithRetry replaces the retry policy, DefaultRetryPolicy by default.
+func WithRetry(policy RetryPolicy) Option {
+	return func(c *Client) {
+		c.retry = policy
+	}
+}
+
+// New creates a client for the server at baseURL, e.g. http://host:8080.
+func New(baseURL string, options ...Option) (*Client, error) {
+	u, err := url.Parse(baseURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, fmt.Errorf("invalid server URL %q: expected http(s)://host:port", baseURL)
+	}
+	u.Path = strings.TrimSuffix(u.Path, "/")
+
+	c := &Client{
+		baseURL:    u,
+		httpClient: &http.Client{Timeout: DefaultTimeout},
+		retry:      DefaultRetryPolicy,
+	}
+	for _, option := range options {
+		option(c)
+	}
+	return c, nil
+}
+
+// BaseURL returns the server URL.
+func (c *Client) BaseURL() string {
+	return c.baseURL.String()
+}
+
+// CreateTask creates a task on the server.
+func (c *Client) CreateTask(ctx context.Context, req dto.TaskRequest) (*dto.TaskResponse, error) {
+	var resp transport.TransportResponse[dto.TaskResponse]
+	if err := c.do(ctx, http.MethodPost, "/api/v1/createTask", req, &resp); err != nil {
+		return nil, err
+	}
+	if resp.Data == nil {
+		return nil, fmt.Errorf("server returned no task")
+	}
+	return resp.Data, nil
+}
+
+// ListTasks lists the tasks of the server.
+func (c *Client) ListTasks(ctx context.Context, req dto.ListRequest) ([]dto.TaskResponse, error) {
+	return list[dto.TaskResponse](ctx, c, "/api/v1/tasks", req)
+}
+
+// ListFilters lists the predefined semantic filters.
+func (c *Client) ListFilters(ctx context.Context, req dto.ListRequest) ([]dto.FilterResponse, error) {
+	return list[dto.FilterResponse](ctx, c, "/api/v1/filters", req)
+}
+
+// ListSources lists the video sources of the server.
+func (c *Client) ListSources(ctx context.Context, req dto.ListRequest) ([]dto.SourceResponse, error) {
+	return list[dto.SourceResponse](ctx, c, "/api/v1/sources", req)
+}
+
+// list reads a collection with the prefix and limit of the request as query parameters.
+func list[T any](ctx context.Context, c *Client, path string, req dto.ListRequest) ([]T, error) {
+	query := url.Values{}
+	if req.Prefix != "" {
+		query.Set("prefix", req.Prefix)
+	}
+	if req.Limit > 0 {
+		query.Set("limit", strconv.Itoa(req.Limit))
+	}
+	if len(query) > 0 {
+		path += "?" + query.Encode()
+	}
+
+	var resp transport.TransportResponse[[]T]
+	if err := c.do(ctx, http.MethodGet, path, nil, &resp); err != nil {
+		return nil, err
+	}
+	if resp.Data == nil {
+		return []T{}, nil
+	}
+	return *resp.Data, nil
+}
+
+// do sends a JSON request and decodes the JSON response into out, retrying the
+// attempts the server did not process. Accepted error statuses are decoded like a success.
+// Error responses are returned as *APIError, connection failures wrap ErrUnreachable.
+func (c *Client) do(ctx context.Context, method, path string, body, out any, accepted ...int) error {
+	var payload []byte
+	if body != nil {
+		var err error
+		if payload, err = json.Marshal(body); err != nil {
+			return fmt.Errorf("encoding request: %w", err)
+		}
+	}
+
+	for attempt := 0; ; attempt++ {
+		wait, err := c.attempt(ctx, method, path, payload, out, accepted)
+		if wait < 0 || attempt+1 >= c.retry.MaxAttempts {
+			return err
+		}
+
+		if backoff := c.retry.Backoff(attempt); backoff > wait {
+			wait = backoff
+		}
+		if err := sleep(ctx, wait); err != nil {
+			return err
+		}
+	}
+}
+
+// attempt sends the request once.
+// It returns a negative wait when the outcome is final, otherwise the minimum delay
+// before the request can be sent again.
+func (c *Client) attempt(ctx context.Context, method, path string, payload []byte, out any, accepted []int) (time.Duration, error) {
+	var reader io.Reader
+	if payload != nil {
+		reader = bytes.NewReader(payload)
+	}
+
+	// The server continues the trace of the command from the traceparent header
+	route, _, _ := strings.Cut(path, "?")
+	ctx, span := tracing.Start(ctx, method+" "+route, tracing.WithKind(tracing.KindClient), tracing.WithAttributes(
+		tracing.Attr("http.request.method", method),
+		tracing.Attr("server.address", c.baseURL.Host),
+	))
+	defer span.End()
+
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL.String()+path, reader)
+	if err != nil {
+		span.SetError(err)
+		return -1, err
+	}
+	tracing.Inject(ctx, req.Header)
+	req.Header.Set("Accept", "application/json")
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		span.SetError(err)
+		if ctx.Err() != nil {
+			return -1, ctx.Err()
+		}
+		notSent := refused(err)
+		err = fmt.Errorf("%w at %s: %v", ErrUnreachable, c.baseURL, unwrapURLError(err))
+		if !notSent {
+			return -1, err
+		}
+		return 0, err
+	}
+	defer resp.Body.Close()
+	span.SetAttributes(tracing.Attr("http.response.status_code", resp.StatusCode))
+	if resp.StatusCode >= http.StatusBadRequest {
+		span.Fail(http.StatusText(resp.StatusCode))
+	}
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return -1, fmt.Errorf("reading response: %w", err)
+	}
+
+	if resp.StatusCode >= http.StatusBadRequest && !slices.Contains(accepted, resp.StatusCode) {
+		if retryable(resp.StatusCode) {
+			return retryAfter(resp.Header), decodeError(resp.StatusCode, data)
+		}
+		return -1, decodeError(resp.StatusCode, data)
+	}
+
+	if out == nil {
+		return -1, nil
+	}
+	if err := json.Unmarshal(data, out); err != nil {
+		return -1, fmt.Errorf("decoding response: %w", err)
+	}
+	return -1, nil
+}
+
+// refused reports a connection that failed before the request was sent.
+func refused(err error) bool {
+	var opErr *net.OpError
+	return errors.As(err, &opErr) && opErr.Op == "dial"
+}
+
+// decodeError reads the error message of a transport envelope or a plain gin error body.
+func decodeError(status int, data []byte) error {
+	var body struct {
+		Error   string `json:"error"`
+		Message string `json:"message"`
+	}
+	_ = json.Unmarshal(data, &body)
+
+	message := body.Error
+	if message == "" {
+		message = body.Message
+	}
+	return &APIError{StatusCode: status, Message: message}
+}
+
+// unwrapURLError drops the "Post \"url\":" prefix already carried by ErrUnreachable.
+func unwrapURLError(err error) error {
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		return urlErr.Err
+	}
+	return err
+}
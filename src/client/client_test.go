@@ -0,0 +1,324 @@
+package client_test
+
+import (
+	"context"
+	"io"
+	"live-semantic/src/client"
+	"live-semantic/src/domain/dto"
+	"live-semantic/src/domain/uc"
+	"live-semantic/src/health"
+	"live-semantic/src/logging"
+	"live-semantic/src/transport/api"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// newAPIServer serves a real api.Server and records the Authorization header of each request.
+func newAPIServer(t *testing.T, authorization *string) *httptest.Server {
+	t.Helper()
+
+	useCases, err := uc.NewUseCase(logging.Discard)
+	assert.NoError(t, err)
+	handler := api.NewServer(useCases, logging.Discard, 0).Handler()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if authorization != nil {
+			*authorization = r.Header.Get("Authorization")
+		}
+		handler.ServeHTTP(w, r)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestClient_CreateTask(t *testing.T) {
+	t.Run("should create the task with the bearer token", func(t *testing.T) {
+		// Given
+		var authorization string
+		server := newAPIServer(t, &authorization)
+		c, err := client.New(server.URL, client.WithToken("secret"))
+		assert.NoError(t, err)
+
+		// When
+		task, err := c.CreateTask(context.Background(), dto.TaskRequest{Title: "title", Description: "description"})
+
+		// Then
+		assert.NoError(t, err)
+		if assert.NotNil(t, task) {
+			assert.Equal(t, "12345", task.ID)
+		}
+		assert.Equal(t, "Bearer secret", authorization)
+	})
+
+	t.Run("should return the server error message", func(t *testing.T) {
+		// Given
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"success":false,"error":"title is required","source":"web"}`))
+		}))
+		defer server.Close()
+		c, _ := client.New(server.URL)
+
+		// When
+		_, err := c.CreateTask(context.Background(), dto.TaskRequest{})
+
+		// Then
+		var apiErr *client.APIError
+		if assert.ErrorAs(t, err, &apiErr) {
+			assert.Equal(t, http.StatusBadRequest, apiErr.StatusCode)
+			assert.Equal(t, "title is required", apiErr.Message)
+		}
+	})
+
+	t.Run("should report an unreachable server", func(t *testing.T) {
+		// Given
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		assert.NoError(t, err)
+		url := "http://" + listener.Addr().String()
+		listener.Close()
+		c, _ := client.New(url)
+
+		// When
+		_, err = c.CreateTask(context.Background(), dto.TaskRequest{})
+
+		// Then
+		assert.ErrorIs(t, err, client.ErrUnreachable)
+		assert.Contains(t, err.Error(), url)
+	})
+
+	t.Run("should reject an invalid server URL", func(t *testing.T) {
+		// When
+		_, err := client.New("localhost:8080")
+
+		// Then
+		assert.Error(t, err)
+	})
+}
+
+func TestUseCases_CreateTask(t *testing.T) {
+	t.Run("should return the remote task as a successful result", func(t *testing.T) {
+		// Given
+		server := newAPIServer(t, nil)
+		c, _ := client.New(server.URL)
+
+		// When
+		result, err := client.NewUseCases(c).CreateTask(context.Background(), dto.TaskRequest{Title: "title"})
+
+		// Then
+		assert.NoError(t, err)
+		assert.True(t, result.Success)
+		assert.Equal(t, "12345", result.Data.ID)
+	})
+
+	t.Run("should turn a rejected request into a failed result", func(t *testing.T) {
+		// Given
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte(`{"error":"invalid token"}`))
+		}))
+		defer server.Close()
+		c, _ := client.New(server.URL)
+
+		// When
+		result, err := client.NewUseCases(c).CreateTask(context.Background(), dto.TaskRequest{})
+
+		// Then
+		assert.NoError(t, err)
+		assert.False(t, result.Success)
+		assert.Equal(t, "invalid token", result.Error)
+	})
+}
+
+func TestClient_List(t *testing.T) {
+	t.Run("should list the tasks, filters and sources with the query", func(t *testing.T) {
+		// Given
+		server := newAPIServer(t, nil)
+		c, _ := client.New(server.URL)
+		_, err := c.CreateTask(context.Background(), dto.TaskRequest{Title: "title", Filters: []string{"fight"}})
+		assert.NoError(t, err)
+
+		// When
+		tasks, tasksErr := c.ListTasks(context.Background(), dto.ListRequest{Prefix: "123"})
+		filters, filtersErr := c.ListFilters(context.Background(), dto.ListRequest{Prefix: "person", Limit: 1})
+		sources, sourcesErr := c.ListSources(context.Background(), dto.ListRequest{})
+
+		// Then
+		assert.NoError(t, tasksErr)
+		if assert.Len(t, tasks, 1) {
+			assert.Equal(t, []string{"fight"}, tasks[0].Filters)
+		}
+		assert.NoError(t, filtersErr)
+		assert.Equal(t, []dto.FilterResponse{{Name: "person walking", Category: "security"}}, filters)
+		assert.NoError(t, sourcesErr)
+		assert.Empty(t, sources)
+	})
+
+	t.Run("should reject an invalid limit", func(t *testing.T) {
+		// Given
+		server := newAPIServer(t, nil)
+
+		// When
+		resp, err := http.Get(server.URL + "/api/v1/tasks?limit=x")
+
+		// Then
+		if assert.NoError(t, err) {
+			resp.Body.Close()
+			assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		}
+	})
+}
+
+func TestClient_Events(t *testing.T) {
+	t.Run("should stream the events and resume after the last one", func(t *testing.T) {
+		// Given
+		server := newAPIServer(t, nil)
+		c, _ := client.New(server.URL)
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		subscription, err := c.Events(ctx, dto.EventRequest{Types: []string{dto.EventTaskCreated}})
+		assert.NoError(t, err)
+
+		// When
+		_, _ = c.CreateTask(context.Background(), dto.TaskRequest{Title: "first"})
+		_, _ = c.CreateTask(context.Background(), dto.TaskRequest{Title: "second"})
+		first := <-subscription.Events
+		resumed, resumeErr := c.Events(ctx, dto.EventRequest{LastEventID: first.ID})
+
+		// Then
+		assert.Equal(t, dto.EventTaskCreated, first.Type)
+		assert.NoError(t, resumeErr)
+		select {
+		case event := <-resumed.Events:
+			assert.Equal(t, first.ID+1, event.ID)
+		case <-time.After(time.Second):
+			t.Fatal("no replayed event")
+		}
+	})
+
+	t.Run("should send heartbeat comments", func(t *testing.T) {
+		// Given
+		useCases, _ := uc.NewUseCase(logging.Discard)
+		server := httptest.NewServer(api.NewServer(useCases, logging.Discard, 0, api.WithHeartbeat(10*time.Millisecond)).Handler())
+		defer server.Close()
+
+		// When
+		resp, err := http.Get(server.URL + "/api/v1/events")
+		if !assert.NoError(t, err) {
+			return
+		}
+		defer resp.Body.Close()
+		buf := make([]byte, len(": heartbeat"))
+		_, err = io.ReadFull(resp.Body, buf)
+
+		// Then
+		assert.NoError(t, err)
+		assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+		assert.Equal(t, ": heartbeat", string(buf))
+	})
+
+	t.Run("should reject an unknown event type", func(t *testing.T) {
+		// Given
+		c, _ := client.New(newAPIServer(t, nil).URL)
+
+		// When
+		_, err := c.Events(context.Background(), dto.EventRequest{Types: []string{"unknown"}})
+
+		// Then
+		var apiErr *client.APIError
+		if assert.ErrorAs(t, err, &apiErr) {
+			assert.Equal(t, http.StatusBadRequest, apiErr.StatusCode)
+		}
+	})
+}
+
+var fastRetry = client.RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: 10 * time.Millisecond}
+
+func TestClient_Retry(t *testing.T) {
+	t.Run("should retry while the server is unavailable", func(t *testing.T) {
+		// Given
+		var attempts atomic.Int32
+		handler := newAPIServer(t, nil)
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if attempts.Add(1) < 3 {
+				w.Header().Set("Retry-After", "0")
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			handler.Config.Handler.ServeHTTP(w, r)
+		}))
+		defer server.Close()
+		c, _ := client.New(server.URL, client.WithRetry(fastRetry))
+
+		// When
+		task, err := c.CreateTask(context.Background(), dto.TaskRequest{Title: "title"})
+
+		// Then
+		assert.NoError(t, err)
+		assert.NotNil(t, task)
+		assert.Equal(t, int32(3), attempts.Load())
+	})
+
+	t.Run("should not retry a rejected request", func(t *testing.T) {
+		// Given
+		var attempts atomic.Int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			attempts.Add(1)
+			w.WriteHeader(http.StatusBadRequest)
+		}))
+		defer server.Close()
+		c, _ := client.New(server.URL, client.WithRetry(fastRetry))
+
+		// When
+		_, err := c.CreateTask(context.Background(), dto.TaskRequest{})
+
+		// Then
+		assert.Error(t, err)
+		assert.Equal(t, int32(1), attempts.Load())
+	})
+
+	t.Run("should stop when the context is cancelled", func(t *testing.T) {
+		// Given
+		release := make(chan struct{})
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			<-release
+		}))
+		defer server.Close()
+		defer close(release)
+		c, _ := client.New(server.URL, client.WithRetry(fastRetry))
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+
+		// When
+		_, err := c.CreateTask(ctx, dto.TaskRequest{})
+
+		// Then
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+}
+
+func TestClient_Health(t *testing.T) {
+	t.Run("should read the health, liveness and readiness", func(t *testing.T) {
+		// Given
+		server := newAPIServer(t, nil)
+		c, _ := client.New(server.URL)
+
+		// When
+		report, healthErr := c.Health(context.Background())
+		liveErr := c.Live(context.Background())
+		readiness, readyErr := c.Ready(context.Background())
+
+		// Then
+		assert.NoError(t, healthErr)
+		assert.Equal(t, health.StatusUp, report.Status)
+		assert.NoError(t, liveErr)
+		assert.NoError(t, readyErr)
+		assert.True(t, readiness.Ready)
+	})
+}
@@ -0,0 +1,104 @@
+package client
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"live-semantic/src/domain/dto"
+	"live-semantic/src/tracing"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// Headers describing how the server resumed the event stream.
+const (
+	// TruncatedHeader is set when events following Last-Event-ID were dropped.
+	TruncatedHeader = "X-Events-Truncated"
+	// ReplayedHeader counts the events replayed first in the stream.
+	ReplayedHeader = "X-Events-Replayed"
+)
+
+// Events streams the domain events of the server from GET /api/v1/events.
+// The channel is closed when the context ends or the stream drops: subscribe again with
+// the ID of the last event received to resume. The request timeout does not apply to the stream.
+func (c *Client) Events(ctx context.Context, req dto.EventRequest) (*dto.EventSubscription, error) {
+	query := url.Values{}
+	for _, eventType := range req.Types {
+		query.Add("type", eventType)
+	}
+	if req.Recent > 0 {
+		query.Set("recent", strconv.Itoa(req.Recent))
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL.String()+"/api/v1/events?"+query.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+	httpReq.Header.Set("Accept", "text/event-stream")
+	if req.LastEventID > 0 {
+		httpReq.Header.Set("Last-Event-ID", strconv.FormatUint(req.LastEventID, 10))
+	}
+	if c.token != "" {
+		httpReq.Header.Set("Authorization", "Bearer "+c.token)
+	}
+	tracing.Inject(ctx, httpReq.Header)
+
+	stream := *c.httpClient
+	stream.Timeout = 0
+
+	resp, err := stream.Do(httpReq)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		return nil, fmt.Errorf("%w at %s: %v", ErrUnreachable, c.baseURL, unwrapURLError(err))
+	}
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
+		return nil, decodeError(resp.StatusCode, body)
+	}
+
+	events := make(chan dto.EventResponse)
+	go func() {
+		defer close(events)
+		defer resp.Body.Close()
+
+		scanner := bufio.NewScanner(resp.Body)
+		var data strings.Builder
+		for scanner.Scan() {
+			line := scanner.Text()
+			switch {
+			case line == "":
+				// A blank line dispatches the event, comments and heartbeats carry no data
+				if data.Len() == 0 {
+					continue
+				}
+				var event dto.EventResponse
+				if err := json.Unmarshal([]byte(data.String()), &event); err == nil {
+					select {
+					case events <- event:
+					case <-ctx.Done():
+						return
+					}
+				}
+				data.Reset()
+			case strings.HasPrefix(line, "data:"):
+				if data.Len() > 0 {
+					data.WriteByte('\n')
+				}
+				data.WriteString(strings.TrimPrefix(strings.TrimPrefix(line, "data:"), " "))
+			}
+		}
+	}()
+	replayed, _ := strconv.Atoi(resp.Header.Get(ReplayedHeader))
+	return &dto.EventSubscription{
+		Events:    events,
+		Replayed:  replayed,
+		Truncated: resp.Header.Get(TruncatedHeader) == "true",
+	}, nil
+}
@@ -0,0 +1,50 @@
+package client
+
+import (
+	"context"
+	"live-semantic/src/health"
+	"net/http"
+	"time"
+)
+
+// Health is the aggregated report served by /health.
+type Health struct {
+	Status     health.Status            `json:"status"`
+	Service    string                   `json:"service"`
+	Version    string                   `json:"version"`
+	CheckedAt  time.Time                `json:"checked_at"`
+	Components []health.ComponentReport `json:"components"`
+	// Transports lists the components mounted by serve, Sessions the open WebSocket sessions.
+	Transports []string `json:"transports,omitempty"`
+	Sessions   int      `json:"sessions,omitempty"`
+}
+
+// Readiness is the answer of /readyz.
+type Readiness struct {
+	Status string   `json:"status"`
+	Ready  bool     `json:"ready"`
+	Failed []string `json:"failed,omitempty"`
+}
+
+// Health returns the health report of the server, also when a critical probe fails.
+func (c *Client) Health(ctx context.Context) (*Health, error) {
+	var report Health
+	if err := c.do(ctx, http.MethodGet, "/health", nil, &report, http.StatusServiceUnavailable); err != nil {
+		return nil, err
+	}
+	return &report, nil
+}
+
+// Live returns nil when the server process answers.
+func (c *Client) Live(ctx context.Context) error {
+	return c.do(ctx, http.MethodGet, "/livez", nil, nil)
+}
+
+// Ready reports whether the server accepts traffic, a server that is not ready is not an error.
+func (c *Client) Ready(ctx context.Context) (*Readiness, error) {
+	var readiness Readiness
+	if err := c.do(ctx, http.MethodGet, "/readyz", nil, &readiness, http.StatusServiceUnavailable); err != nil {
+		return nil, err
+	}
+	return &readiness, nil
+}
@@ -0,0 +1,74 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"live-semantic/src/domain/dto"
+	"live-semantic/src/domain/uc"
+)
+
+// UseCases runs the use cases on a remote server.
+// It lets the CLI transports swap the in-process use cases for a running server.
+type UseCases struct {
+	client *Client
+}
+
+// NewUseCases creates use cases backed by the client.
+func NewUseCases(client *Client) uc.UseCases {
+	return &UseCases{client: client}
+}
+
+// CreateTask creates the task on the server.
+func (u *UseCases) CreateTask(ctx context.Context, req dto.TaskRequest) (dto.Result[dto.TaskResponse], error) {
+	task, err := u.client.CreateTask(ctx, req)
+	if err != nil {
+		return failure[dto.TaskResponse](err)
+	}
+	return dto.Success(*task), nil
+}
+
+// ListTasks lists the tasks of the server.
+func (u *UseCases) ListTasks(ctx context.Context, req dto.ListRequest) (dto.Result[[]dto.TaskResponse], error) {
+	tasks, err := u.client.ListTasks(ctx, req)
+	if err != nil {
+		return failure[[]dto.TaskResponse](err)
+	}
+	return dto.Success(tasks), nil
+}
+
+// ListFilters lists the predefined filters of the server.
+func (u *UseCases) ListFilters(ctx context.Context, req dto.ListRequest) (dto.Result[[]dto.FilterResponse], error) {
+	filters, err := u.client.ListFilters(ctx, req)
+	if err != nil {
+		return failure[[]dto.FilterResponse](err)
+	}
+	return dto.Success(filters), nil
+}
+
+// ListSources lists the video sources of the server.
+func (u *UseCases) ListSources(ctx context.Context, req dto.ListRequest) (dto.Result[[]dto.SourceResponse], error) {
+	sources, err := u.client.ListSources(ctx, req)
+	if err != nil {
+		return failure[[]dto.SourceResponse](err)
+	}
+	return dto.Success(sources), nil
+}
+
+// SubscribeEvents streams the domain events of the server.
+func (u *UseCases) SubscribeEvents(ctx context.Context, req dto.EventRequest) (dto.Result[dto.EventSubscription], error) {
+	subscription, err := u.client.Events(ctx, req)
+	if err != nil {
+		return failure[dto.EventSubscription](err)
+	}
+	return dto.Success(*subscription), nil
+}
+
+// failure converts a client error to a result.
+// A request rejected by the server is a failed result, like a use case failure in-process.
+func failure[T any](err error) (dto.Result[T], error) {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) && apiErr.StatusCode < 500 {
+		return dto.Failure[T](apiErr.Error()), nil
+	}
+	return dto.Failure[T](err.Error()), err
+}
@@ -0,0 +1,533 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"live-semantic/src/domain/dto"
+	"live-semantic/src/transport"
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// Client side events delivered to the subscribers, next to the typed server messages.
+const (
+	// EventConnected follows the first connection and every reconnection.
+	EventConnected = "connected"
+	// EventDisconnected follows the loss of the connection, before reconnecting.
+	EventDisconnected = "disconnected"
+	// EventError carries an error reply no call was waiting for, or the error that stopped
+	// the reconnection or the renewal of the subscription to the domain events.
+	EventError = "error"
+	// EventSubscriptionClosed follows the end of the subscription by the server when the
+	// handlers fall behind the domain events, the client then renews it.
+	EventSubscriptionClosed = "subscription.closed"
+	// EventAll subscribes to every event.
+	EventAll = "*"
+)
+
+var (
+	// ErrClosed is returned by the calls made on a closed WebSocket client.
+	ErrClosed = errors.New("websocket client closed")
+	// ErrDisconnected fails the calls still waiting for a reply when the connection drops.
+	ErrDisconnected = errors.New("websocket connection lost")
+)
+
+// Event is a message pushed by the server or a connection event.
+// The domain events of Watch have their type, such as dto.EventTaskCreated, and a
+// dto.EventResponse as data.
+type Event struct {
+	Type string          `json:"type"`
+	Data json.RawMessage `json:"data,omitempty"`
+}
+
+// wsMessage is the envelope of the messages sent to the server.
+type wsMessage struct {
+	Type string `json:"type"`
+	Data any    `json:"data"`
+}
+
+// wsReply is the reply to a call, or the error that ended the connection.
+type wsReply struct {
+	data []byte
+	err  error
+}
+
+// WebSocket calls the WebSocket API and reconnects with backoff when the connection drops.
+//
+// The server answers the calls of a connection in order, so replies are matched to the
+// pending calls first in, first out. Calls pending when the connection drops fail with
+// ErrDisconnected and are not replayed, new calls wait for the reconnection. When the
+// server refuses the reconnection for good, such as for a revoked key, the client stops
+// and every call fails with that error.
+type WebSocket struct {
+	url    string
+	header http.Header
+	dialer *websocket.Dialer
+	retry  RetryPolicy
+
+	mu        sync.Mutex
+	running   bool
+	conn      *websocket.Conn
+	connected chan struct{}
+	pending   []chan wsReply
+	writeMu   sync.Mutex
+	// err stopped the reconnection
+	err error
+	// watch is the subscription to the domain events, renewed from lastEvent
+	watch     *dto.EventRequest
+	lastEvent uint64
+
+	subsMu  sync.RWMutex
+	subs    map[string]map[int]func(Event)
+	nextSub int
+
+	closed    chan struct{}
+	closeOnce sync.Once
+	done      chan struct{}
+}
+
+// WebSocket creates a WebSocket client for the /ws endpoint of the server.
+// It shares the token and the retry policy of the REST client, call Connect to open it.
+func (c *Client) WebSocket() *WebSocket {
+	u := *c.baseURL
+	u.Scheme = "ws"
+	if c.baseURL.Scheme == "https" {
+		u.Scheme = "wss"
+	}
+	u.Path += "/ws"
+
+	header := http.Header{}
+	if c.token != "" {
+		header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	return &WebSocket{
+		url:       u.String(),
+		header:    header,
+		dialer:    &websocket.Dialer{HandshakeTimeout: c.httpClient.Timeout, Proxy: http.ProxyFromEnvironment},
+		retry:     c.retry,
+		connected: make(chan struct{}),
+		subs:      map[string]map[int]func(Event){},
+		closed:    make(chan struct{}),
+		done:      make(chan struct{}),
+	}
+}
+
+// Connect opens the connection, retrying with the policy, then keeps it open until Close.
+func (w *WebSocket) Connect(ctx context.Context) error {
+	w.mu.Lock()
+	running := w.running
+	w.mu.Unlock()
+	if running {
+		return fmt.Errorf("websocket client already connected")
+	}
+
+	var err error
+	for attempt := 0; attempt < max(w.retry.MaxAttempts, 1); attempt++ {
+		if attempt > 0 {
+			if err := sleep(ctx, w.retry.Backoff(attempt-1)); err != nil {
+				return err
+			}
+		}
+
+		var conn *websocket.Conn
+		var final bool
+		if conn, final, err = w.dial(ctx); err == nil {
+			if !w.attach(conn) {
+				_ = conn.Close()
+				return ErrClosed
+			}
+			go w.run(conn)
+			return nil
+		}
+		if final {
+			break
+		}
+	}
+	return err
+}
+
+// Subscribe calls handler for each event of the type, or of every type with EventAll.
+// Handlers run on the reading goroutine and must not block. The returned func unsubscribes.
+func (w *WebSocket) Subscribe(eventType string, handler func(Event)) func() {
+	w.subsMu.Lock()
+	defer w.subsMu.Unlock()
+
+	id := w.nextSub
+	w.nextSub++
+	if w.subs[eventType] == nil {
+		w.subs[eventType] = map[int]func(Event){}
+	}
+	w.subs[eventType][id] = handler
+
+	return func() {
+		w.subsMu.Lock()
+		defer w.subsMu.Unlock()
+		delete(w.subs[eventType], id)
+	}
+}
+
+// Watch subscribes the connection to the domain events of req, delivered to the Subscribe
+// handlers. The subscription replaces the previous one and is renewed from the last event
+// received after a reconnection, or when the server ends it because the handlers fell behind.
+func (w *WebSocket) Watch(ctx context.Context, req dto.EventRequest) error {
+	// The events may follow the reply before it is read, they must find the subscription
+	w.mu.Lock()
+	w.watch = &req
+	w.lastEvent = req.LastEventID
+	w.mu.Unlock()
+
+	err := w.subscribe(ctx, req)
+	if err != nil {
+		w.mu.Lock()
+		w.watch = nil
+		w.mu.Unlock()
+	}
+	return err
+}
+
+// CreateTask creates a task over the WebSocket connection.
+func (w *WebSocket) CreateTask(ctx context.Context, req dto.TaskRequest) (*dto.TaskResponse, error) {
+	data, err := w.call(ctx, "Task", req)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp transport.TransportResponse[dto.TaskResponse]
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return nil, fmt.Errorf("decoding reply: %w", err)
+	}
+	if !resp.Success {
+		return nil, &APIError{Message: resp.Error}
+	}
+	if resp.Data == nil {
+		return nil, fmt.Errorf("server returned no task")
+	}
+	return resp.Data, nil
+}
+
+// Close closes the connection with a normal closure and stops reconnecting.
+func (w *WebSocket) Close() error {
+	var err error
+	w.closeOnce.Do(func() {
+		close(w.closed)
+
+		w.mu.Lock()
+		conn, running := w.conn, w.running
+		w.mu.Unlock()
+
+		if !running {
+			close(w.done)
+		}
+		if conn != nil {
+			w.writeMu.Lock()
+			message := websocket.FormatCloseMessage(websocket.CloseNormalClosure, "")
+			_ = conn.WriteControl(websocket.CloseMessage, message, time.Now().Add(time.Second))
+			w.writeMu.Unlock()
+			err = conn.Close()
+		}
+	})
+
+	<-w.done
+	return err
+}
+
+// call sends a message once connected and waits for its reply.
+func (w *WebSocket) call(ctx context.Context, messageType string, data any) ([]byte, error) {
+	conn, err := w.waitConnected(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	reply := make(chan wsReply, 1)
+
+	// Registering the reply and writing under the same lock keeps both in the same order
+	w.writeMu.Lock()
+	w.mu.Lock()
+	if w.conn != conn {
+		w.mu.Unlock()
+		w.writeMu.Unlock()
+		return nil, ErrDisconnected
+	}
+	w.pending = append(w.pending, reply)
+	w.mu.Unlock()
+	err = conn.WriteJSON(wsMessage{Type: messageType, Data: data})
+	w.writeMu.Unlock()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrDisconnected, err)
+	}
+
+	select {
+	case r := <-reply:
+		return r.data, r.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-w.closed:
+		return nil, ErrClosed
+	}
+}
+
+// subscribe sends the subscription to the domain events and waits for its acceptance.
+func (w *WebSocket) subscribe(ctx context.Context, req dto.EventRequest) error {
+	data, err := w.call(ctx, "Subscribe", req)
+	if err != nil {
+		return err
+	}
+
+	var resp transport.TransportResponse[dto.EventSubscription]
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return fmt.Errorf("decoding reply: %w", err)
+	}
+	if !resp.Success {
+		return &APIError{Message: resp.Error}
+	}
+	return nil
+}
+
+// resubscribe renews the subscription to the domain events from the last event received.
+// It runs apart from the reading goroutine, which delivers its reply.
+func (w *WebSocket) resubscribe() {
+	w.mu.Lock()
+	if w.watch == nil {
+		w.mu.Unlock()
+		return
+	}
+	req := *w.watch
+	req.LastEventID = w.lastEvent
+	w.mu.Unlock()
+
+	err := w.subscribe(context.Background(), req)
+	if err != nil && !errors.Is(err, ErrClosed) && !errors.Is(err, ErrDisconnected) {
+		w.publishError(fmt.Errorf("renewing the event subscription: %w", err))
+	}
+}
+
+// waitConnected returns the current connection, waiting for a reconnection if needed.
+func (w *WebSocket) waitConnected(ctx context.Context) (*websocket.Conn, error) {
+	for {
+		w.mu.Lock()
+		conn, connected, err := w.conn, w.connected, w.err
+		w.mu.Unlock()
+		if err != nil {
+			return nil, err
+		}
+		if conn != nil {
+			return conn, nil
+		}
+
+		select {
+		case <-connected:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-w.closed:
+			return nil, ErrClosed
+		}
+	}
+}
+
+// dial opens a connection, final reports an error that retrying will not fix.
+func (w *WebSocket) dial(ctx context.Context) (conn *websocket.Conn, final bool, err error) {
+	conn, resp, err := w.dialer.DialContext(ctx, w.url, w.header)
+	if err == nil {
+		return conn, false, nil
+	}
+	if ctx.Err() != nil {
+		return nil, true, ctx.Err()
+	}
+	if resp == nil {
+		return nil, false, fmt.Errorf("%w at %s: %v", ErrUnreachable, w.url, err)
+	}
+	return nil, !retryable(resp.StatusCode), &APIError{StatusCode: resp.StatusCode, Message: err.Error()}
+}
+
+// attach makes conn the current connection and wakes the waiting calls.
+// It returns false once the client is closed.
+func (w *WebSocket) attach(conn *websocket.Conn) bool {
+	w.mu.Lock()
+	select {
+	case <-w.closed:
+		w.mu.Unlock()
+		return false
+	default:
+	}
+	w.running = true
+	w.conn = conn
+	close(w.connected)
+	w.mu.Unlock()
+
+	w.publish(Event{Type: EventConnected})
+	return true
+}
+
+// detach forgets the lost connection and fails its pending calls.
+func (w *WebSocket) detach() {
+	w.mu.Lock()
+	w.conn = nil
+	w.connected = make(chan struct{})
+	pending := w.pending
+	w.pending = nil
+	w.mu.Unlock()
+
+	for _, reply := range pending {
+		reply <- wsReply{err: ErrDisconnected}
+	}
+}
+
+// run reads the connection and reconnects until Close.
+func (w *WebSocket) run(conn *websocket.Conn) {
+	defer close(w.done)
+
+	for {
+		w.read(conn)
+		w.detach()
+
+		select {
+		case <-w.closed:
+			return
+		default:
+		}
+		w.publish(Event{Type: EventDisconnected})
+
+		var err error
+		if conn, err = w.reconnect(); err != nil {
+			if !errors.Is(err, ErrClosed) {
+				w.fail(err)
+			}
+			return
+		}
+		if !w.attach(conn) {
+			_ = conn.Close()
+			return
+		}
+		go w.resubscribe()
+	}
+}
+
+// fail stops the client after a refused reconnection: the waiting calls and the next ones fail with err.
+func (w *WebSocket) fail(err error) {
+	w.mu.Lock()
+	w.err = err
+	close(w.connected)
+	w.mu.Unlock()
+
+	w.publishError(err)
+}
+
+// publishError delivers err to the subscribers as an error reply of the server.
+func (w *WebSocket) publishError(err error) {
+	data, _ := json.Marshal(transport.TransportResponse[dto.TaskResponse]{Success: false, Error: err.Error(), Source: "websocket"})
+	w.publish(Event{Type: EventError, Data: data})
+}
+
+// read dispatches the messages of the connection until it fails.
+func (w *WebSocket) read(conn *websocket.Conn) {
+	for {
+		_, data, err := conn.ReadMessage()
+		if err != nil {
+			return
+		}
+
+		var event Event
+		if err := json.Unmarshal(data, &event); err != nil {
+			continue
+		}
+
+		// Replies carry no type, typed messages are pushed by the server
+		if event.Type == "" {
+			if reply := w.nextPending(); reply != nil {
+				reply <- wsReply{data: data}
+				continue
+			}
+			event = Event{Type: EventError, Data: data}
+		}
+		w.track(event)
+		w.publish(event)
+	}
+}
+
+// track keeps the ID of the last domain event to renew the subscription from it,
+// and renews the subscription the server ended.
+func (w *WebSocket) track(event Event) {
+	if event.Type == EventSubscriptionClosed {
+		go w.resubscribe()
+		return
+	}
+	if !slices.Contains(dto.EventTypes, event.Type) {
+		return
+	}
+
+	var domainEvent struct {
+		ID uint64 `json:"id"`
+	}
+	if err := json.Unmarshal(event.Data, &domainEvent); err != nil {
+		return
+	}
+	w.mu.Lock()
+	w.lastEvent = max(w.lastEvent, domainEvent.ID)
+	w.mu.Unlock()
+}
+
+// nextPending pops the oldest call waiting for a reply.
+func (w *WebSocket) nextPending() chan wsReply {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if len(w.pending) == 0 {
+		return nil
+	}
+	reply := w.pending[0]
+	w.pending = w.pending[1:]
+	return reply
+}
+
+// reconnect dials with backoff until it succeeds, the client is closed, returning ErrClosed,
+// or the server refuses the connection for good.
+func (w *WebSocket) reconnect() (*websocket.Conn, error) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		select {
+		case <-w.closed:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	for attempt := 0; ; attempt++ {
+		if err := sleep(ctx, w.retry.Backoff(attempt)); err != nil {
+			return nil, ErrClosed
+		}
+		conn, final, err := w.dial(ctx)
+		if err == nil {
+			return conn, nil
+		}
+		if ctx.Err() != nil {
+			return nil, ErrClosed
+		}
+		if final {
+			return nil, err
+		}
+	}
+}
+
+// publish calls the subscribers of the event type and of every event.
+func (w *WebSocket) publish(event Event) {
+	w.subsMu.RLock()
+	var handlers []func(Event)
+	for _, eventType := range []string{event.Type, EventAll} {
+		for _, handler := range w.subs[eventType] {
+			handlers = append(handlers, handler)
+		}
+	}
+	w.subsMu.RUnlock()
+
+	for _, handler := range handlers {
+		handler(event)
+	}
+}
@@ -0,0 +1,212 @@
+package client_test
+
+import (
+	"context"
+	"encoding/json"
+	"live-semantic/src/client"
+	"live-semantic/src/domain/dto"
+	"live-semantic/src/domain/uc"
+	"live-semantic/src/logging"
+	"live-semantic/src/transport/websocket"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newWebSocketServer(t *testing.T) *websocket.Server {
+	t.Helper()
+
+	useCases, err := uc.NewUseCase(logging.Discard)
+	assert.NoError(t, err)
+	return websocket.NewServer(useCases, logging.Discard, 0)
+}
+
+// switchable serves the current handler, so a test can replace a drained server.
+type switchable struct {
+	handler atomic.Value
+}
+
+func (s *switchable) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.handler.Load().(http.Handler).ServeHTTP(w, r)
+}
+
+func TestWebSocket(t *testing.T) {
+	t.Run("should create a task and publish the connection", func(t *testing.T) {
+		// Given
+		server := httptest.NewServer(newWebSocketServer(t).Handler())
+		defer server.Close()
+		c, _ := client.New(server.URL, client.WithRetry(fastRetry))
+		ws := c.WebSocket()
+		defer ws.Close()
+
+		connected := make(chan client.Event, 1)
+		ws.Subscribe(client.EventConnected, func(event client.Event) { connected <- event })
+
+		// When
+		assert.NoError(t, ws.Connect(context.Background()))
+		task, err := ws.CreateTask(context.Background(), dto.TaskRequest{Title: "title"})
+
+		// Then
+		assert.NoError(t, err)
+		if assert.NotNil(t, task) {
+			assert.Equal(t, "12345", task.ID)
+		}
+		assert.Equal(t, client.EventConnected, (<-connected).Type)
+	})
+
+	t.Run("should reconnect after the server goes away", func(t *testing.T) {
+		// Given
+		first := newWebSocketServer(t)
+		handler := &switchable{}
+		handler.handler.Store(first.Handler())
+		server := httptest.NewServer(handler)
+		defer server.Close()
+
+		c, _ := client.New(server.URL, client.WithRetry(fastRetry))
+		ws := c.WebSocket()
+		defer ws.Close()
+
+		events := make(chan string, 10)
+		ws.Subscribe(client.EventAll, func(event client.Event) { events <- event.Type })
+		assert.NoError(t, ws.Connect(context.Background()))
+		assert.Equal(t, client.EventConnected, <-events)
+
+		// When
+		handler.handler.Store(newWebSocketServer(t).Handler())
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		assert.NoError(t, first.Drain(ctx))
+
+		// Then
+		assert.Equal(t, client.EventDisconnected, <-events)
+		assert.Equal(t, client.EventConnected, <-events)
+		task, err := ws.CreateTask(ctx, dto.TaskRequest{Title: "title"})
+		assert.NoError(t, err)
+		assert.NotNil(t, task)
+	})
+
+	t.Run("should stop reconnecting when the server refuses the connection for good", func(t *testing.T) {
+		// Given
+		first := newWebSocketServer(t)
+		handler := &switchable{}
+		handler.handler.Store(http.HandlerFunc(first.Handler().ServeHTTP))
+		server := httptest.NewServer(handler)
+		defer server.Close()
+
+		c, _ := client.New(server.URL, client.WithRetry(fastRetry))
+		ws := c.WebSocket()
+		defer ws.Close()
+
+		events := make(chan string, 10)
+		ws.Subscribe(client.EventAll, func(event client.Event) { events <- event.Type })
+		assert.NoError(t, ws.Connect(context.Background()))
+		assert.Equal(t, client.EventConnected, <-events)
+
+		// When
+		handler.handler.Store(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			http.Error(w, "revoked key", http.StatusForbidden)
+		}))
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		assert.NoError(t, first.Drain(ctx))
+
+		// Then
+		assert.Equal(t, client.EventDisconnected, <-events)
+		assert.Equal(t, client.EventError, <-events)
+		_, err := ws.CreateTask(ctx, dto.TaskRequest{Title: "title"})
+		var apiErr *client.APIError
+		if assert.ErrorAs(t, err, &apiErr) {
+			assert.Equal(t, http.StatusForbidden, apiErr.StatusCode)
+		}
+	})
+
+	t.Run("should deliver the watched domain events and renew the subscription after a reconnection", func(t *testing.T) {
+		// Given
+		useCases, err := uc.NewUseCase(logging.Discard)
+		if !assert.NoError(t, err) {
+			return
+		}
+		first := websocket.NewServer(useCases, logging.Discard, 0)
+		handler := &switchable{}
+		handler.handler.Store(first.Handler())
+		server := httptest.NewServer(handler)
+		defer server.Close()
+
+		c, _ := client.New(server.URL, client.WithRetry(fastRetry))
+		ws := c.WebSocket()
+		defer ws.Close()
+
+		created := make(chan dto.EventResponse, 10)
+		ws.Subscribe(dto.EventTaskCreated, func(event client.Event) {
+			var domainEvent dto.EventResponse
+			assert.NoError(t, json.Unmarshal(event.Data, &domainEvent))
+			created <- domainEvent
+		})
+		connected := make(chan struct{}, 10)
+		ws.Subscribe(client.EventConnected, func(client.Event) { connected <- struct{}{} })
+		assert.NoError(t, ws.Connect(context.Background()))
+		<-connected
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		defer cancel()
+
+		// When
+		err = ws.Watch(ctx, dto.EventRequest{Types: []string{dto.EventTaskCreated}})
+		_, createErr := ws.CreateTask(ctx, dto.TaskRequest{Title: "before"})
+		handler.handler.Store(websocket.NewServer(useCases, logging.Discard, 0).Handler())
+		assert.NoError(t, first.Drain(ctx))
+		<-connected
+		_, recreateErr := ws.CreateTask(ctx, dto.TaskRequest{Title: "after"})
+
+		// Then
+		assert.NoError(t, err)
+		assert.NoError(t, createErr)
+		assert.NoError(t, recreateErr)
+		var ids []uint64
+		for range 2 {
+			select {
+			case event := <-created:
+				ids = append(ids, event.ID)
+			case <-ctx.Done():
+				t.Fatal("the domain event was not delivered")
+			}
+		}
+		assert.Equal(t, []uint64{1, 2}, ids)
+	})
+
+	t.Run("should refuse to watch an unknown event type", func(t *testing.T) {
+		// Given
+		server := httptest.NewServer(newWebSocketServer(t).Handler())
+		defer server.Close()
+		c, _ := client.New(server.URL)
+		ws := c.WebSocket()
+		defer ws.Close()
+		assert.NoError(t, ws.Connect(context.Background()))
+
+		// When
+		err := ws.Watch(context.Background(), dto.EventRequest{Types: []string{"unknown"}})
+
+		// Then
+		var apiErr *client.APIError
+		assert.ErrorAs(t, err, &apiErr)
+	})
+
+	t.Run("should fail the calls after close", func(t *testing.T) {
+		// Given
+		server := httptest.NewServer(newWebSocketServer(t).Handler())
+		defer server.Close()
+		c, _ := client.New(server.URL)
+		ws := c.WebSocket()
+		assert.NoError(t, ws.Connect(context.Background()))
+
+		// When
+		assert.NoError(t, ws.Close())
+		_, err := ws.CreateTask(context.Background(), dto.TaskRequest{})
+
+		// Then
+		assert.ErrorIs(t, err, client.ErrClosed)
+	})
+}
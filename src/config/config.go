@@ -0,0 +1,174 @@
+// Package config provides the typed application configuration.
+package config
+
+import "time"
+
+// Config is the effective application configuration.
+// Values are resolved with the precedence flags > environment > config file > profile > defaults.
+// Fields tagged reload:"restart" are not applied by a hot reload, fields tagged secret:"true"
+// are redacted on export and doc tags feed the generated configuration template.
+type Config struct {
+	Profile string        `mapstructure:"profile" reload:"restart" doc:"Configuration profile: local, docker or cloud."`
+	Server  ServerConfig  `mapstructure:"server" doc:"Network transports."`
+	Storage StorageConfig `mapstructure:"storage" doc:"Persistence backend."`
+	Logging LoggingConfig `mapstructure:"logging" doc:"Application logger."`
+	AI      AIConfig      `mapstructure:"ai" doc:"Embedding provider."`
+	Video   VideoConfig   `mapstructure:"video" doc:"Video sources."`
+	Alerts  AlertsConfig  `mapstructure:"alerts" doc:"Alert channels."`
+	Remote  RemoteConfig  `mapstructure:"remote" doc:"Remote server used by the CLI commands."`
+	Auth    AuthConfig    `mapstructure:"auth" doc:"Authentication of the network transports."`
+	Metrics MetricsConfig `mapstructure:"metrics" doc:"Metrics of the use cases and transports."`
+	Tracing TracingConfig `mapstructure:"tracing" doc:"Traces of the requests, exported in the OTLP JSON format."`
+	Audit   AuditConfig   `mapstructure:"audit" doc:"Tamper-evident record of the mutating operations."`
+}
+
+// ServerConfig configures the network transports.
+type ServerConfig struct {
+	Host            string          `mapstructure:"host" reload:"restart" doc:"Interface to listen on, empty for all interfaces."`
+	TrustedProxies  []string        `mapstructure:"trusted_proxies" reload:"restart" doc:"IP addresses or CIDR ranges of the reverse proxies trusted for X-Forwarded-For and X-Forwarded-Proto, empty trusts none."`
+	Port            int             `mapstructure:"port" reload:"restart" doc:"Port of the shared listener used by serve and serve api."`
+	ShutdownTimeout time.Duration   `mapstructure:"shutdown_timeout" doc:"Time allowed to drain in-flight requests and sockets on shutdown."`
+	API             ComponentConfig `mapstructure:"api" doc:"REST API component."`
+	WebSocket       ComponentConfig `mapstructure:"websocket" doc:"WebSocket component."`
+	RPC             ComponentConfig `mapstructure:"rpc" doc:"JSON-RPC 2.0 component, over HTTP and WebSocket."`
+	RateLimit       RateLimitConfig `mapstructure:"rate_limit" doc:"Per-client rate and concurrency limits of the network transports."`
+	CORS            CORSConfig      `mapstructure:"cors" doc:"Browser origins allowed to call the REST API and open WebSockets."`
+	Admin           AdminConfig     `mapstructure:"admin" doc:"Operator endpoints under /api/v1/admin, they require the system:admin scope."`
+}
+
+// AdminConfig configures the operator endpoints of the REST API.
+type AdminConfig struct {
+	Enabled bool `mapstructure:"enabled" reload:"restart" doc:"Expose the log levels, the effective config, the components, sessions and queues, and the drain and disconnect actions, requires auth.enabled."`
+	// Pprof exposes the runtime profiles, which reveal the internals of the process.
+	Pprof bool `mapstructure:"pprof" reload:"restart" doc:"Expose the pprof profiles under /api/v1/admin/debug/pprof."`
+	// PersistLogLevels lets an admin rewrite the config file, it is never created by the endpoint.
+	PersistLogLevels bool `mapstructure:"persist_log_levels" reload:"restart" doc:"Let PUT /api/v1/admin/logging write the log levels to the config file in use."`
+}
+
+// CORSConfig configures the origin policy of the REST API and of the WebSocket upgrades.
+// Requests without an Origin header and same-origin requests are always accepted.
+type CORSConfig struct {
+	// AllowedOrigins accepts *, exact origins and patterns such as https://*.example.com.
+	AllowedOrigins   []string      `mapstructure:"allowed_origins" reload:"restart" doc:"Allowed browser origins: *, https://app.example.com or patterns like https://*.example.com, empty allows same-origin only."`
+	AllowCredentials bool          `mapstructure:"allow_credentials" reload:"restart" doc:"Let browsers send cookies and the Authorization header cross-origin, not allowed with *."`
+	MaxAge           time.Duration `mapstructure:"max_age" reload:"restart" doc:"How long browsers may cache a preflight response."`
+}
+
+// ComponentConfig configures a transport hosted by the serve command.
+type ComponentConfig struct {
+	Enabled bool `mapstructure:"enabled" reload:"restart" doc:"Host the component in serve mode."`
+	// Port moves the component to its own listener, 0 shares server.port.
+	Port int `mapstructure:"port" reload:"restart" doc:"Dedicated listener port, 0 shares server.port."`
+}
+
+// RateLimitConfig configures the per-client limits of the REST, WebSocket and JSON-RPC transports.
+// A client is the authenticated principal, or the client IP when authentication is disabled.
+// A coarser limit per client IP applies before authentication.
+type RateLimitConfig struct {
+	Enabled     bool    `mapstructure:"enabled" reload:"restart" doc:"Limit the request rate and the concurrent use case calls of each client."`
+	Rate        float64 `mapstructure:"rate" doc:"Requests per second refilled in each client bucket, 0 disables the rate limit."`
+	Burst       int     `mapstructure:"burst" doc:"Requests a client may send at once before being throttled."`
+	MaxInFlight int     `mapstructure:"max_in_flight" doc:"Concurrent use case calls per client, 0 disables the limit."`
+	IPRate      float64 `mapstructure:"ip_rate" doc:"Requests per second refilled in the bucket of a client IP before authentication, shared by its principals, 0 disables it."`
+	IPBurst     int     `mapstructure:"ip_burst" doc:"Requests a client IP may send at once before authentication."`
+	// Overrides accepts KEY=RATE:BURST entries where KEY is a route such as "POST /api/v1/createTask"
+	// or a message type such as ws:Task or rpc:ws.
+	Overrides []string `mapstructure:"overrides" doc:"Route or message type limits declared as KEY=RATE:BURST, e.g. POST /api/v1/createTask=5:10 or ws:Task=2:5."`
+}
+
+// MetricsConfig configures the metrics collection and reporting.
+type MetricsConfig struct {
+	Enabled         bool          `mapstructure:"enabled" reload:"restart" doc:"Serve the metrics in the Prometheus text format on /metrics in serve mode."`
+	Console         bool          `mapstructure:"console" reload:"restart" doc:"Print a metrics summary to stderr when a CLI command ends."`
+	ConsoleInterval time.Duration `mapstructure:"console_interval" reload:"restart" doc:"Interval of the console reports of the long-running commands, 0 reports at exit only."`
+}
+
+// TracingConfig configures the request tracing.
+type TracingConfig struct {
+	Enabled     bool    `mapstructure:"enabled" reload:"restart" doc:"Record the spans of the transports, use cases and adapters."`
+	Exporter    string  `mapstructure:"exporter" reload:"restart" doc:"Span destination: file or stdout."`
+	Path        string  `mapstructure:"path" reload:"restart" doc:"File appended to by the file exporter, one OTLP JSON request per line."`
+	SampleRatio float64 `mapstructure:"sample_ratio" reload:"restart" doc:"Share of the new traces recorded, between 0.0 and 1.0."`
+}
+
+// AuditConfig configures the audit log, a hash-chained JSON Lines file kept apart from the application log.
+type AuditConfig struct {
+	Enabled bool   `mapstructure:"enabled" reload:"restart" doc:"Record every mutating operation in the audit log, nothing is recorded when off."`
+	Path    string `mapstructure:"path" reload:"restart" doc:"Active audit file, the rotated files are kept next to it."`
+	// MaxSize and MaxAge rotate the active file, whichever comes first.
+	MaxSize int64         `mapstructure:"max_size" reload:"restart" doc:"Size in bytes rotating the active file, 0 disables the size rotation."`
+	MaxAge  time.Duration `mapstructure:"max_age" reload:"restart" doc:"Age of the first entry rotating the active file, 0 disables the time rotation."`
+}
+
+// StorageConfig configures the persistence backend.
+type StorageConfig struct {
+	Driver string `mapstructure:"driver" reload:"restart" doc:"Storage driver: memory or postgres."`
+	DSN    string `mapstructure:"dsn" reload:"restart" secret:"true" doc:"Connection string, required with the postgres driver."`
+	Path   string `mapstructure:"path" reload:"restart" doc:"Directory for snapshots and exported clips."`
+}
+
+// LoggingConfig configures the application logger.
+type LoggingConfig struct {
+	Level string `mapstructure:"level" doc:"Log level: debug, info, warn or error."`
+	// Modules accepts MODULE=LEVEL entries, an entry also applies to the submodules.
+	Modules []string `mapstructure:"modules" doc:"Per-module levels declared as MODULE=LEVEL, e.g. transport.websocket=debug."`
+	Format  string   `mapstructure:"format" reload:"restart" doc:"Log format: console or json."`
+	// Redaction applies to every field logged through the application logger.
+	Redaction RedactionConfig `mapstructure:"redaction" doc:"Masking of the personal data in the log fields."`
+}
+
+// RedactionConfig configures the masking of the log fields.
+type RedactionConfig struct {
+	Enabled bool `mapstructure:"enabled" reload:"restart" doc:"Redact the log fields."`
+	// Fields are matched at any depth, e.g. the title of a logged task request.
+	Fields []string `mapstructure:"fields" reload:"restart" doc:"Field names whose values are replaced by a hash, case insensitive."`
+	// Patterns accepts the built-in email, phone and token patterns or regular expressions.
+	Patterns  []string `mapstructure:"patterns" reload:"restart" doc:"Patterns masked in every string value: email, phone, token or a regular expression."`
+	MaxLength int      `mapstructure:"max_length" reload:"restart" doc:"Longest string value logged, longer values are truncated, 0 keeps them whole."`
+	// HashKey is shared by the processes of a deployment so that their hashes correlate,
+	// changing it changes every hash.
+	HashKey string `mapstructure:"hash_key" reload:"restart" secret:"true" doc:"HMAC key of the field hashes, at least 32 bytes, empty draws a random key per process."`
+}
+
+// AIConfig configures the embedding provider.
+type AIConfig struct {
+	Provider  string  `mapstructure:"provider" reload:"restart" doc:"Embedding provider: onnx, python or rest."`
+	Model     string  `mapstructure:"model" reload:"restart" doc:"Model name, e.g. ViT-B/32 or ViT-L/14."`
+	ModelPath string  `mapstructure:"model_path" reload:"restart" doc:"Directory containing the exported models."`
+	Threshold float64 `mapstructure:"threshold" reload:"restart" doc:"Default filter confidence threshold between 0.0 and 1.0, read at startup only."`
+	BatchSize int     `mapstructure:"batch_size" reload:"restart" doc:"Inference batch size."`
+	Workers   int     `mapstructure:"workers" reload:"restart" doc:"Inference workers, 0 uses one per CPU core."`
+	GPU       bool    `mapstructure:"gpu" reload:"restart" doc:"Enable GPU acceleration (requires CUDA)."`
+}
+
+// VideoConfig configures the video sources.
+type VideoConfig struct {
+	Source     string `mapstructure:"source" reload:"restart" doc:"Default video source: cam0, rtmp://... or http://..."`
+	Resolution string `mapstructure:"resolution" reload:"restart" doc:"Capture resolution: 480p, 720p, 1080p or auto."`
+	FPS        int    `mapstructure:"fps" reload:"restart" doc:"Frames processed per second, between 1 and 60."`
+	BufferSize int    `mapstructure:"buffer_size" reload:"restart" doc:"Frame buffer size."`
+}
+
+// AlertsConfig configures the alert channels.
+type AlertsConfig struct {
+	// Channels accepts console, webhook:URL and slack:TOKEN entries, a change needs a restart.
+	Channels []string      `mapstructure:"channels" secret:"true" reload:"restart" doc:"Alert channels: console, webhook:URL or slack:TOKEN, read at startup only."`
+	Timeout  time.Duration `mapstructure:"timeout" reload:"restart" doc:"Delivery timeout for a single alert."`
+}
+
+// RemoteConfig points the CLI commands at a running server instead of the in-process use cases.
+type RemoteConfig struct {
+	URL     string        `mapstructure:"url" reload:"restart" doc:"Base URL of the server, e.g. http://host:8080, empty runs commands in-process."`
+	Token   string        `mapstructure:"token" secret:"true" reload:"restart" doc:"Bearer token sent in the Authorization header."`
+	Timeout time.Duration `mapstructure:"timeout" reload:"restart" doc:"Timeout of a single request to the server."`
+}
+
+// AuthConfig configures the authentication of the REST, WebSocket and JSON-RPC transports.
+type AuthConfig struct {
+	Enabled bool `mapstructure:"enabled" reload:"restart" doc:"Require an API key or a bearer token on every transport but the health checks."`
+	// APIKeys accepts NAME:SHA256:GRANT,GRANT entries, printed by apikey create --no-store.
+	APIKeys     []string      `mapstructure:"api_keys" reload:"restart" secret:"true" doc:"API keys declared as NAME:SHA256:GRANT,GRANT, a grant is a scope or role:NAME."`
+	KeysFile    string        `mapstructure:"keys_file" reload:"restart" doc:"File of the keys managed by the apikey commands, empty uses storage.path/apikeys.json."`
+	TokenSecret string        `mapstructure:"token_secret" reload:"restart" secret:"true" doc:"HMAC secret signing the bearer tokens, at least 32 bytes, empty disables tokens."`
+	TokenTTL    time.Duration `mapstructure:"token_ttl" reload:"restart" doc:"Default lifetime of the tokens issued by apikey token."`
+}
@@ -0,0 +1,183 @@
+package config_test
+
+import (
+	"errors"
+	"live-semantic/src/config"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+func writeConfig(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	assert.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func TestDefault(t *testing.T) {
+	t.Run("should be valid for every profile", func(t *testing.T) {
+		for _, name := range config.Profiles() {
+			// When
+			cfg, err := config.ForProfile(name)
+
+			// Then
+			assert.NoError(t, err)
+			if name == config.ProfileCloud {
+				// The cloud profile needs a DSN from the environment
+				cfg.Storage.DSN = "postgres://db"
+			}
+			assert.NoError(t, cfg.Validate(), name)
+		}
+	})
+
+	t.Run("should reject an unknown profile", func(t *testing.T) {
+		// When
+		_, err := config.ForProfile("mars")
+
+		// Then
+		assert.ErrorIs(t, err, config.ErrInvalid)
+	})
+}
+
+func TestValidate(t *testing.T) {
+	t.Run("should report every problem", func(t *testing.T) {
+		// Given
+		cfg := config.Default()
+		cfg.Server.Port = 0
+		cfg.AI.Threshold = 1.5
+		cfg.Alerts.Channels = []string{"console", "webhook:not-a-url", "pager"}
+		cfg.Remote.URL = "ftp://host"
+
+		// When
+		err := cfg.Validate()
+
+		// Then
+		var validationErr *config.ValidationError
+		assert.ErrorIs(t, err, config.ErrInvalid)
+		if assert.True(t, errors.As(err, &validationErr)) {
+			assert.Len(t, validationErr.Problems, 5)
+		}
+	})
+
+	t.Run("should reject an unknown trace exporter and an out of range sample ratio", func(t *testing.T) {
+		// Given
+		cfg := config.Default()
+		cfg.Tracing.Exporter = "jaeger"
+		cfg.Tracing.SampleRatio = 2
+
+		// When
+		err := cfg.Validate()
+
+		// Then
+		var validationErr *config.ValidationError
+		if assert.True(t, errors.As(err, &validationErr)) {
+			assert.Equal(t, []string{
+				`tracing.exporter: "jaeger" must be one of [file stdout]`,
+				"tracing.sample_ratio: 2 must be between 0.0 and 1.0",
+			}, validationErr.Problems)
+		}
+	})
+
+	t.Run("should reject the admin endpoints without authentication", func(t *testing.T) {
+		// Given
+		cfg := config.Default()
+		cfg.Server.Admin.Enabled = true
+
+		// When
+		anonymous := cfg.Validate()
+		cfg.Auth.Enabled = true
+		authenticated := cfg.Validate()
+
+		// Then
+		var validationErr *config.ValidationError
+		if assert.True(t, errors.As(anonymous, &validationErr)) {
+			assert.Equal(t, []string{"server.admin.enabled: requires auth.enabled, the admin endpoints are never served anonymously"}, validationErr.Problems)
+		}
+		assert.NoError(t, authenticated)
+	})
+
+	t.Run("should reject a trusted proxy that is neither an IP address nor a CIDR range", func(t *testing.T) {
+		// Given
+		cfg := config.Default()
+		cfg.Server.TrustedProxies = []string{"10.0.0.1", "10.0.0.0/8", "::1", "proxy.local"}
+
+		// When
+		err := cfg.Validate()
+
+		// Then
+		var validationErr *config.ValidationError
+		if assert.True(t, errors.As(err, &validationErr)) {
+			assert.Equal(t, []string{`server.trusted_proxies: "proxy.local" is not an IP address or a CIDR range`}, validationErr.Problems)
+		}
+	})
+
+	t.Run("should reject an IP rate limit without burst", func(t *testing.T) {
+		// Given
+		cfg := config.Default()
+		cfg.Server.RateLimit.IPBurst = 0
+
+		// When
+		err := cfg.Validate()
+
+		// Then
+		var validationErr *config.ValidationError
+		if assert.True(t, errors.As(err, &validationErr)) {
+			assert.Equal(t, []string{"server.rate_limit.ip_burst: must be at least 1"}, validationErr.Problems)
+		}
+	})
+}
+
+func TestLoad(t *testing.T) {
+	t.Run("should apply profile, file, env and flags in order", func(t *testing.T) {
+		// Given
+		file := writeConfig(t, "profile: docker\nserver:\n  port: 9000\n  shutdown_timeout: 5s\nvideo:\n  fps: 20\n")
+		t.Setenv("LIVESEMANTIC_SERVER_PORT", "9100")
+		t.Setenv("LIVESEMANTIC_LOG_LEVEL", "debug")
+
+		flags := pflag.NewFlagSet("test", pflag.ContinueOnError)
+		flags.Int("fps", 0, "")
+		assert.NoError(t, flags.Parse([]string{"--fps=30"}))
+
+		v := viper.New()
+		assert.NoError(t, v.BindPFlag("video.fps", flags.Lookup("fps")))
+
+		// When
+		cfg, err := config.Load(v, file)
+
+		// Then
+		if !assert.NoError(t, err) {
+			return
+		}
+		assert.Equal(t, config.ProfileDocker, cfg.Profile)
+		assert.Equal(t, "json", cfg.Logging.Format)
+		assert.Equal(t, 5*time.Second, cfg.Server.ShutdownTimeout)
+		assert.Equal(t, 9100, cfg.Server.Port)
+		assert.Equal(t, "debug", cfg.Logging.Level)
+		assert.Equal(t, 30, cfg.Video.FPS)
+	})
+
+	t.Run("should fail on an invalid value", func(t *testing.T) {
+		// Given
+		file := writeConfig(t, "ai:\n  provider: magic\n")
+
+		// When
+		_, err := config.Load(viper.New(), file)
+
+		// Then
+		assert.ErrorIs(t, err, config.ErrInvalid)
+	})
+
+	t.Run("should fail on a missing explicit file", func(t *testing.T) {
+		// When
+		_, err := config.Load(viper.New(), filepath.Join(t.TempDir(), "missing.yaml"))
+
+		// Then
+		assert.ErrorIs(t, err, config.ErrInvalid)
+	})
+}
@@ -0,0 +1,282 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// RedactedValue replaces secret values on export.
+const RedactedValue = "********"
+
+// Formats lists the supported export formats.
+var Formats = []string{"yaml", "json", "toml"}
+
+// templateHeader opens every generated template.
+const templateHeader = `LiveSemantic configuration template.
+Values are the built-in defaults, every key can also be set with a
+LIVESEMANTIC_<SECTION>_<KEY> environment variable or the matching flag.`
+
+// node is an exported configuration entry, either a section or a value.
+type node struct {
+	key      string
+	doc      string
+	value    any
+	children []node
+}
+
+// Redacted returns a copy of the configuration with secret values masked.
+func (c Config) Redacted() Config {
+	out := c
+	walk(&out, func(f field) {
+		if f.Field.Tag.Get("secret") != "true" {
+			return
+		}
+
+		switch f.Value.Kind() {
+		case reflect.String:
+			if f.Value.String() != "" {
+				f.Value.SetString(RedactedValue)
+			}
+		case reflect.Slice:
+			// Copy before masking so the original configuration is untouched
+			masked := reflect.MakeSlice(f.Value.Type(), f.Value.Len(), f.Value.Len())
+			reflect.Copy(masked, f.Value)
+			for i := 0; i < masked.Len(); i++ {
+				item := masked.Index(i)
+				if kind, _, found := strings.Cut(item.String(), ":"); found {
+					item.SetString(kind + ":" + RedactedValue)
+				}
+			}
+			f.Value.Set(masked)
+		}
+	})
+	return out
+}
+
+// Export renders the configuration as yaml, json or toml with secret values masked.
+func Export(cfg Config, format string) ([]byte, error) {
+	return render(tree(reflect.ValueOf(cfg.Redacted())), format, false)
+}
+
+// Template renders the built-in defaults as a yaml or toml template
+// commented with the documentation of every field.
+func Template(format string) ([]byte, error) {
+	if format == "json" {
+		return nil, fmt.Errorf("json does not support comments, use yaml or toml for templates")
+	}
+	return render(tree(reflect.ValueOf(Default())), format, true)
+}
+
+// render dispatches to the renderer of the format.
+func render(nodes []node, format string, template bool) ([]byte, error) {
+	switch format {
+	case "yaml", "yml":
+		return renderYAML(nodes, template)
+	case "json":
+		return renderJSON(nodes)
+	case "toml":
+		return renderTOML(nodes, template), nil
+	default:
+		return nil, fmt.Errorf("unsupported format %q (available: %v)", format, Formats)
+	}
+}
+
+// tree converts a configuration struct into ordered nodes.
+func tree(v reflect.Value) []node {
+	var nodes []node
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		name, _, _ := strings.Cut(sf.Tag.Get("mapstructure"), ",")
+		if name == "" || name == "-" {
+			continue
+		}
+
+		n := node{key: name, doc: sf.Tag.Get("doc")}
+		fv := v.Field(i)
+		switch {
+		case sf.Type == reflect.TypeOf(time.Duration(0)):
+			n.value = time.Duration(fv.Int()).String()
+		case sf.Type.Kind() == reflect.Struct:
+			n.children = tree(fv)
+		default:
+			n.value = fv.Interface()
+		}
+		nodes = append(nodes, n)
+	}
+	return nodes
+}
+
+// renderYAML renders the nodes as yaml, with head comments for templates.
+func renderYAML(nodes []node, template bool) ([]byte, error) {
+	root, err := yamlMapping(nodes, template)
+	if err != nil {
+		return nil, err
+	}
+	document := &yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{root}}
+	if template {
+		document.HeadComment = templateHeader
+	}
+
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(document); err != nil {
+		return nil, err
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func yamlMapping(nodes []node, template bool) (*yaml.Node, error) {
+	mapping := &yaml.Node{Kind: yaml.MappingNode}
+	for _, n := range nodes {
+		key := &yaml.Node{Kind: yaml.ScalarNode, Value: n.key}
+		if template {
+			key.HeadComment = n.doc
+		}
+
+		var value *yaml.Node
+		if n.children != nil {
+			child, err := yamlMapping(n.children, template)
+			if err != nil {
+				return nil, err
+			}
+			value = child
+		} else {
+			value = &yaml.Node{}
+			if err := value.Encode(n.value); err != nil {
+				return nil, fmt.Errorf("encoding %s: %w", n.key, err)
+			}
+		}
+
+		mapping.Content = append(mapping.Content, key, value)
+	}
+	return mapping, nil
+}
+
+// renderJSON renders the nodes as indented json keeping the declaration order.
+func renderJSON(nodes []node) ([]byte, error) {
+	var compact bytes.Buffer
+	if err := writeJSONObject(&compact, nodes); err != nil {
+		return nil, err
+	}
+
+	var out bytes.Buffer
+	if err := json.Indent(&out, compact.Bytes(), "", "  "); err != nil {
+		return nil, err
+	}
+	out.WriteByte('\n')
+	return out.Bytes(), nil
+}
+
+func writeJSONObject(buf *bytes.Buffer, nodes []node) error {
+	buf.WriteByte('{')
+	for i, n := range nodes {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		key, _ := json.Marshal(n.key)
+		buf.Write(key)
+		buf.WriteByte(':')
+
+		if n.children != nil {
+			if err := writeJSONObject(buf, n.children); err != nil {
+				return err
+			}
+			continue
+		}
+
+		value, err := json.Marshal(n.value)
+		if err != nil {
+			return fmt.Errorf("encoding %s: %w", n.key, err)
+		}
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+	return nil
+}
+
+// renderTOML renders the nodes as toml, values first then one table per section.
+func renderTOML(nodes []node, template bool) []byte {
+	var buf bytes.Buffer
+	if template {
+		writeTOMLComment(&buf, templateHeader)
+		buf.WriteByte('\n')
+	}
+	writeTOMLTable(&buf, "", nodes, template)
+	return buf.Bytes()
+}
+
+func writeTOMLTable(buf *bytes.Buffer, path string, nodes []node, template bool) {
+	for _, n := range nodes {
+		if n.children != nil {
+			continue
+		}
+		if template {
+			writeTOMLComment(buf, n.doc)
+		}
+		fmt.Fprintf(buf, "%s = %s\n", n.key, tomlValue(n.value))
+	}
+
+	for _, n := range nodes {
+		if n.children == nil {
+			continue
+		}
+		table := n.key
+		if path != "" {
+			table = path + "." + n.key
+		}
+
+		buf.WriteByte('\n')
+		if template {
+			writeTOMLComment(buf, n.doc)
+		}
+		fmt.Fprintf(buf, "[%s]\n", table)
+		writeTOMLTable(buf, table, n.children, template)
+	}
+}
+
+func writeTOMLComment(buf *bytes.Buffer, comment string) {
+	if comment == "" {
+		return
+	}
+	for _, line := range strings.Split(comment, "\n") {
+		fmt.Fprintf(buf, "# %s\n", line)
+	}
+}
+
+// tomlValue formats a scalar or a string list as a toml value.
+func tomlValue(value any) string {
+	switch v := value.(type) {
+	case string:
+		return strconv.Quote(v)
+	case bool:
+		return strconv.FormatBool(v)
+	case int:
+		return strconv.Itoa(v)
+	case float64:
+		s := strconv.FormatFloat(v, 'f', -1, 64)
+		if !strings.ContainsAny(s, ".eE") {
+			s += ".0"
+		}
+		return s
+	case []string:
+		items := make([]string, len(v))
+		for i, item := range v {
+			items[i] = strconv.Quote(item)
+		}
+		return "[" + strings.Join(items, ", ") + "]"
+	default:
+		return strconv.Quote(fmt.Sprint(v))
+	}
+}
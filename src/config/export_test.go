@@ -0,0 +1,83 @@
+package config_test
+
+import (
+	"bytes"
+	"live-semantic/src/config"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRedacted(t *testing.T) {
+	t.Run("should mask secrets without touching the original", func(t *testing.T) {
+		// Given
+		cfg := config.Default()
+		cfg.Storage.DSN = "postgres://user:pass@db/live"
+		cfg.Alerts.Channels = []string{"console", "slack:xoxb-token"}
+
+		// When
+		redacted := cfg.Redacted()
+
+		// Then
+		assert.Equal(t, config.RedactedValue, redacted.Storage.DSN)
+		assert.Equal(t, []string{"console", "slack:" + config.RedactedValue}, redacted.Alerts.Channels)
+		assert.Equal(t, "slack:xoxb-token", cfg.Alerts.Channels[1])
+	})
+}
+
+func TestExport(t *testing.T) {
+	for _, format := range config.Formats {
+		t.Run("should round trip "+format, func(t *testing.T) {
+			// Given
+			cfg := config.Default()
+			cfg.Server.Port = 9090
+			cfg.Video.FPS = 25
+
+			// When
+			out, err := config.Export(cfg, format)
+
+			// Then
+			assert.NoError(t, err)
+			v := viper.New()
+			v.SetConfigType(format)
+			assert.NoError(t, v.ReadConfig(bytes.NewReader(out)))
+			assert.Equal(t, 9090, v.GetInt("server.port"))
+			assert.Equal(t, 25, v.GetInt("video.fps"))
+			assert.Equal(t, cfg.Server.ShutdownTimeout, v.GetDuration("server.shutdown_timeout"))
+		})
+	}
+
+	t.Run("should reject an unknown format", func(t *testing.T) {
+		// When
+		_, err := config.Export(config.Default(), "xml")
+
+		// Then
+		assert.Error(t, err)
+	})
+}
+
+func TestTemplate(t *testing.T) {
+	for _, format := range []string{"yaml", "toml"} {
+		t.Run("should document every field in "+format, func(t *testing.T) {
+			// When
+			out, err := config.Template(format)
+
+			// Then
+			assert.NoError(t, err)
+			assert.Contains(t, string(out), "# Log level: debug, info, warn or error.")
+			v := viper.New()
+			v.SetConfigType(format)
+			assert.NoError(t, v.ReadConfig(bytes.NewReader(out)))
+			assert.Equal(t, "info", v.GetString("logging.level"))
+		})
+	}
+
+	t.Run("should reject json", func(t *testing.T) {
+		// When
+		_, err := config.Template("json")
+
+		// Then
+		assert.Error(t, err)
+	})
+}
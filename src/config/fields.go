@@ -0,0 +1,42 @@
+package config
+
+import (
+	"reflect"
+	"strings"
+)
+
+// field is a leaf of the configuration tree addressed by its dotted key.
+type field struct {
+	Key   string
+	Value reflect.Value
+	Field reflect.StructField
+}
+
+// walk visits every leaf field of the configuration in declaration order.
+func walk(cfg any, visit func(f field)) {
+	walkValue("", reflect.Indirect(reflect.ValueOf(cfg)), visit)
+}
+
+func walkValue(prefix string, v reflect.Value, visit func(f field)) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		name, _, _ := strings.Cut(sf.Tag.Get("mapstructure"), ",")
+		if name == "" || name == "-" {
+			continue
+		}
+
+		key := name
+		if prefix != "" {
+			key = prefix + "." + name
+		}
+
+		fv := v.Field(i)
+		if sf.Type.Kind() == reflect.Struct && sf.Type.PkgPath() != "time" {
+			walkValue(key, fv, visit)
+			continue
+		}
+
+		visit(field{Key: key, Value: fv, Field: sf})
+	}
+}
@@ -0,0 +1,106 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+const (
+	// EnvPrefix prefixes every environment variable, e.g. LIVESEMANTIC_SERVER_PORT.
+	EnvPrefix = "LIVESEMANTIC"
+	// EnvConfigFile names the environment variable holding the config file path.
+	EnvConfigFile = EnvPrefix + "_CONFIG"
+	// defaultConfigName is searched in the home directory when no file is given.
+	defaultConfigName = ".live-semantic"
+)
+
+// envAliases maps documented environment variables that do not follow the key naming.
+var envAliases = map[string]string{
+	"ai.model_path": EnvPrefix + "_MODEL_PATH",
+	"ai.gpu":        EnvPrefix + "_GPU",
+	"ai.workers":    EnvPrefix + "_WORKERS",
+	"logging.level": EnvPrefix + "_LOG_LEVEL",
+}
+
+// Load resolves the configuration from defaults, the selected profile, the config file,
+// LIVESEMANTIC_* environment variables and the flags already bound on v.
+// An empty file falls back to $LIVESEMANTIC_CONFIG then to $HOME/.live-semantic.yaml.
+// Every returned error wraps ErrInvalid.
+func Load(v *viper.Viper, file string) (*Config, error) {
+	v.SetEnvPrefix(EnvPrefix)
+	v.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
+	v.AutomaticEnv()
+
+	for key, env := range envAliases {
+		if err := v.BindEnv(key, envName(key), env); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalid, err)
+		}
+	}
+
+	if err := readFile(v, file); err != nil {
+		return nil, err
+	}
+
+	profile := v.GetString("profile")
+	if profile == "" {
+		profile = ProfileLocal
+	}
+
+	defaults, err := ForProfile(profile)
+	if err != nil {
+		return nil, err
+	}
+
+	walk(defaults, func(f field) {
+		v.SetDefault(f.Key, f.Value.Interface())
+	})
+
+	var cfg Config
+	if err := v.Unmarshal(&cfg); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalid, err)
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	return &cfg, nil
+}
+
+// readFile reads the config file, a missing default file is not an error.
+func readFile(v *viper.Viper, file string) error {
+	if file == "" {
+		file = os.Getenv(EnvConfigFile)
+	}
+
+	if file != "" {
+		v.SetConfigFile(file)
+	} else {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalid, err)
+		}
+		v.AddConfigPath(home)
+		v.SetConfigType("yaml")
+		v.SetConfigName(defaultConfigName)
+	}
+
+	if err := v.ReadInConfig(); err != nil {
+		var notFound viper.ConfigFileNotFoundError
+		if file == "" && errors.As(err, &notFound) {
+			return nil
+		}
+		return fmt.Errorf("%w: reading config file: %v", ErrInvalid, err)
+	}
+
+	return nil
+}
+
+// envName returns the environment variable bound to a configuration key.
+func envName(key string) string {
+	return EnvPrefix + "_" + strings.ToUpper(strings.ReplaceAll(key, ".", "_"))
+}
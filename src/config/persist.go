@@ -0,0 +1,58 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+// Persist writes values, keyed like logging.level, to the config file in use by v so
+// that they survive a restart. The other keys of the file are kept, the values coming
+// from the environment, the flags or the defaults are not written.
+// Without config file in use, it creates $LIVESEMANTIC_CONFIG or $HOME/.live-semantic.yaml.
+// It returns the path of the written file.
+func Persist(v *viper.Viper, values map[string]any) (string, error) {
+	path, err := persistPath(v)
+	if err != nil {
+		return "", err
+	}
+
+	file := viper.New()
+	file.SetConfigFile(path)
+	if ext := strings.TrimPrefix(filepath.Ext(path), "."); ext == "" {
+		file.SetConfigType("yaml")
+	}
+	if _, err := os.Stat(path); err == nil {
+		if err := file.ReadInConfig(); err != nil {
+			return "", fmt.Errorf("reading config file: %w", err)
+		}
+	} else if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return "", fmt.Errorf("creating config directory: %w", err)
+	}
+
+	for key, value := range values {
+		file.Set(key, value)
+	}
+	if err := file.WriteConfigAs(path); err != nil {
+		return "", fmt.Errorf("writing config file: %w", err)
+	}
+	return path, nil
+}
+
+// persistPath returns the config file in use, else the file Load would read.
+func persistPath(v *viper.Viper) (string, error) {
+	if used := v.ConfigFileUsed(); used != "" {
+		return used, nil
+	}
+	if file := os.Getenv(EnvConfigFile); file != "" {
+		return file, nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, defaultConfigName+".yaml"), nil
+}
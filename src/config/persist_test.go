@@ -0,0 +1,41 @@
+package config_test
+
+import (
+	"live-semantic/src/config"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPersist(t *testing.T) {
+	t.Run("should write the values to the config file in use and keep its other keys", func(t *testing.T) {
+		// Given
+		t.Setenv("LIVESEMANTIC_SERVER_PORT", "9090")
+		file := writeConfig(t, "ai:\n  threshold: 0.5\nlogging:\n  level: info\n")
+		v := viper.New()
+		_, err := config.Load(v, file)
+		assert.NoError(t, err)
+
+		// When
+		path, err := config.Persist(v, map[string]any{
+			"logging.level":   "debug",
+			"logging.modules": []string{"transport.websocket=debug"},
+		})
+
+		// Then
+		assert.NoError(t, err)
+		assert.Equal(t, file, path)
+		reloaded, err := config.Load(viper.New(), file)
+		if assert.NoError(t, err) {
+			assert.Equal(t, "debug", reloaded.Logging.Level)
+			assert.Equal(t, []string{"transport.websocket=debug"}, reloaded.Logging.Modules)
+			assert.Equal(t, 0.5, reloaded.AI.Threshold)
+		}
+		written := viper.New()
+		written.SetConfigFile(file)
+		if assert.NoError(t, written.ReadInConfig()) {
+			assert.False(t, written.IsSet("server.port"), "environment values must not be persisted")
+		}
+	})
+}
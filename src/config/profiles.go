@@ -0,0 +1,141 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+const (
+	// ProfileLocal is the default profile for a developer workstation.
+	ProfileLocal = "local"
+	// ProfileDocker targets a containerized deployment.
+	ProfileDocker = "docker"
+	// ProfileCloud targets a managed cloud deployment.
+	ProfileCloud = "cloud"
+)
+
+// profiles holds the overrides applied on top of Default for each named profile.
+var profiles = map[string]func(*Config){
+	ProfileLocal: func(c *Config) {},
+	ProfileDocker: func(c *Config) {
+		c.Server.Host = "0.0.0.0"
+		c.Server.CORS.AllowedOrigins = nil
+		c.Storage.Path = "/data"
+		c.Logging.Format = "json"
+		c.Tracing.Path = "/data/traces.jsonl"
+		c.Audit.Path = "/data/audit.jsonl"
+		c.AI.ModelPath = "/models"
+	},
+	ProfileCloud: func(c *Config) {
+		c.Server.Host = "0.0.0.0"
+		c.Server.ShutdownTimeout = 30 * time.Second
+		c.Server.CORS.AllowedOrigins = nil
+		c.Storage.Driver = "postgres"
+		c.Logging.Level = "warn"
+		c.Logging.Format = "json"
+		c.AI.ModelPath = "/models"
+		c.Auth.Enabled = true
+		c.Audit.Enabled = true
+	},
+}
+
+// Default returns the built-in configuration.
+func Default() Config {
+	return Config{
+		Profile: ProfileLocal,
+		Server: ServerConfig{
+			Port:            8080,
+			ShutdownTimeout: 15 * time.Second,
+			API:             ComponentConfig{Enabled: true},
+			WebSocket:       ComponentConfig{Enabled: true},
+			RPC:             ComponentConfig{Enabled: true},
+			RateLimit: RateLimitConfig{
+				Enabled:     true,
+				Rate:        50,
+				Burst:       100,
+				MaxInFlight: 32,
+				IPRate:      200,
+				IPBurst:     400,
+			},
+			// The local profile is for development, the other profiles only allow same-origin browsers
+			CORS: CORSConfig{
+				AllowedOrigins: []string{"*"},
+				MaxAge:         10 * time.Minute,
+			},
+		},
+		Storage: StorageConfig{
+			Driver: "memory",
+			Path:   "./data",
+		},
+		Logging: LoggingConfig{
+			Level:  "info",
+			Format: "console",
+			Redaction: RedactionConfig{
+				Enabled:   true,
+				Fields:    []string{"title", "description", "password", "secret", "token", "authorization", "api_key"},
+				Patterns:  []string{"email", "phone", "token"},
+				MaxLength: 256,
+			},
+		},
+		AI: AIConfig{
+			Provider:  "onnx",
+			Model:     "ViT-B/32",
+			ModelPath: "./models",
+			Threshold: 0.7,
+			BatchSize: 1,
+		},
+		Video: VideoConfig{
+			Source:     "cam0",
+			Resolution: "auto",
+			FPS:        10,
+			BufferSize: 10,
+		},
+		Alerts: AlertsConfig{
+			Channels: []string{"console"},
+			Timeout:  5 * time.Second,
+		},
+		Remote: RemoteConfig{
+			Timeout: 30 * time.Second,
+		},
+		Auth: AuthConfig{
+			TokenTTL: time.Hour,
+		},
+		Metrics: MetricsConfig{
+			Enabled: true,
+		},
+		Tracing: TracingConfig{
+			Exporter:    "file",
+			Path:        "./data/traces.jsonl",
+			SampleRatio: 1,
+		},
+		Audit: AuditConfig{
+			Path:    "./data/audit.jsonl",
+			MaxSize: 10 << 20,
+			MaxAge:  7 * 24 * time.Hour,
+		},
+	}
+}
+
+// ForProfile returns the built-in configuration with the profile overrides applied.
+func ForProfile(name string) (Config, error) {
+	apply, ok := profiles[name]
+	if !ok {
+		return Config{}, fmt.Errorf("%w: unknown profile %q (available: %v)", ErrInvalid, name, Profiles())
+	}
+
+	cfg := Default()
+	cfg.Profile = name
+	apply(&cfg)
+	return cfg, nil
+}
+
+// Profiles returns the names of the built-in profiles.
+func Profiles() []string {
+	names := make([]string, 0, len(profiles))
+	for name := range profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
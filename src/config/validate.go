@@ -0,0 +1,240 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"live-semantic/src/auth"
+	"live-semantic/src/logging"
+	"net"
+	"net/url"
+	"slices"
+	"strings"
+)
+
+// ErrInvalid is wrapped by every loading and validation error.
+var ErrInvalid = errors.New("invalid configuration")
+
+// ValidationError lists every invalid field of a configuration.
+type ValidationError struct {
+	Problems []string
+}
+
+// Error implements the error interface.
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("%s: %s", ErrInvalid, strings.Join(e.Problems, "; "))
+}
+
+// Unwrap allows errors.Is(err, ErrInvalid).
+func (e *ValidationError) Unwrap() error {
+	return ErrInvalid
+}
+
+// Accepted values of the enumerated settings, also offered by the shell completion.
+var (
+	LogLevels   = []string{"debug", "info", "warn", "error"}
+	Resolutions = []string{"480p", "720p", "1080p", "auto"}
+)
+
+var (
+	logFormats = []string{"console", "json"}
+	drivers    = []string{"memory", "postgres"}
+	providers  = []string{"onnx", "python", "rest"}
+	exporters  = []string{"file", "stdout"}
+)
+
+// Validate checks the configuration and reports every problem at once.
+func (c Config) Validate() error {
+	var problems []string
+	add := func(format string, args ...any) {
+		problems = append(problems, fmt.Sprintf(format, args...))
+	}
+
+	if _, ok := profiles[c.Profile]; !ok {
+		add("profile: unknown profile %q (available: %v)", c.Profile, Profiles())
+	}
+
+	// Server
+	if c.Server.Port < 1 || c.Server.Port > 65535 {
+		add("server.port: %d is not a valid port", c.Server.Port)
+	}
+	if c.Server.API.Port < 0 || c.Server.API.Port > 65535 {
+		add("server.api.port: %d is not a valid port", c.Server.API.Port)
+	}
+	if c.Server.WebSocket.Port < 0 || c.Server.WebSocket.Port > 65535 {
+		add("server.websocket.port: %d is not a valid port", c.Server.WebSocket.Port)
+	}
+	if c.Server.RPC.Port < 0 || c.Server.RPC.Port > 65535 {
+		add("server.rpc.port: %d is not a valid port", c.Server.RPC.Port)
+	}
+	if c.Server.ShutdownTimeout < 0 {
+		add("server.shutdown_timeout: must not be negative")
+	}
+	for _, proxy := range c.Server.TrustedProxies {
+		if _, _, err := net.ParseCIDR(proxy); err != nil && net.ParseIP(proxy) == nil {
+			add("server.trusted_proxies: %q is not an IP address or a CIDR range", proxy)
+		}
+	}
+	if c.Server.RateLimit.Rate < 0 {
+		add("server.rate_limit.rate: must not be negative")
+	}
+	if c.Server.RateLimit.Rate > 0 && c.Server.RateLimit.Burst < 1 {
+		add("server.rate_limit.burst: must be at least 1")
+	}
+	if c.Server.RateLimit.IPRate < 0 {
+		add("server.rate_limit.ip_rate: must not be negative")
+	}
+	if c.Server.RateLimit.IPRate > 0 && c.Server.RateLimit.IPBurst < 1 {
+		add("server.rate_limit.ip_burst: must be at least 1")
+	}
+	if c.Server.RateLimit.MaxInFlight < 0 {
+		add("server.rate_limit.max_in_flight: must not be negative")
+	}
+	// The overrides and the origins are parsed by the transports, which own their syntax
+	if c.Server.CORS.MaxAge < 0 {
+		add("server.cors.max_age: must not be negative")
+	}
+	if c.Server.Admin.Enabled && !c.Auth.Enabled {
+		add("server.admin.enabled: requires auth.enabled, the admin endpoints are never served anonymously")
+	}
+
+	// Storage
+	if !slices.Contains(drivers, c.Storage.Driver) {
+		add("storage.driver: %q must be one of %v", c.Storage.Driver, drivers)
+	}
+	if c.Storage.Driver == "postgres" && c.Storage.DSN == "" {
+		add("storage.dsn: required with the postgres driver")
+	}
+
+	// Logging
+	if !slices.Contains(LogLevels, c.Logging.Level) {
+		add("logging.level: %q must be one of %v", c.Logging.Level, LogLevels)
+	}
+	if !slices.Contains(logFormats, c.Logging.Format) {
+		add("logging.format: %q must be one of %v", c.Logging.Format, logFormats)
+	}
+	if _, err := logging.ParseModules(c.Logging.Modules); err != nil {
+		add("logging.modules: %v", err)
+	}
+	if _, err := logging.ParsePatterns(c.Logging.Redaction.Patterns); err != nil {
+		add("logging.redaction.patterns: %v", err)
+	}
+	if c.Logging.Redaction.MaxLength < 0 {
+		add("logging.redaction.max_length: must not be negative")
+	}
+	if c.Logging.Redaction.HashKey != "" && len(c.Logging.Redaction.HashKey) < logging.MinHashKeyLength {
+		add("logging.redaction.hash_key: must be at least %d bytes", logging.MinHashKeyLength)
+	}
+
+	// AI
+	if !slices.Contains(providers, c.AI.Provider) {
+		add("ai.provider: %q must be one of %v", c.AI.Provider, providers)
+	}
+	if c.AI.Threshold < 0 || c.AI.Threshold > 1 {
+		add("ai.threshold: %v must be between 0.0 and 1.0", c.AI.Threshold)
+	}
+	if c.AI.BatchSize < 1 {
+		add("ai.batch_size: must be at least 1")
+	}
+	if c.AI.Workers < 0 {
+		add("ai.workers: must not be negative")
+	}
+
+	// Video
+	if !slices.Contains(Resolutions, c.Video.Resolution) {
+		add("video.resolution: %q must be one of %v", c.Video.Resolution, Resolutions)
+	}
+	if c.Video.FPS < 1 || c.Video.FPS > 60 {
+		add("video.fps: %d must be between 1 and 60", c.Video.FPS)
+	}
+	if c.Video.BufferSize < 1 {
+		add("video.buffer_size: must be at least 1")
+	}
+
+	// Alerts
+	for _, channel := range c.Alerts.Channels {
+		if err := validateChannel(channel); err != nil {
+			add("alerts.channels: %v", err)
+		}
+	}
+	if c.Alerts.Timeout < 0 {
+		add("alerts.timeout: must not be negative")
+	}
+
+	// Remote
+	if c.Remote.URL != "" {
+		u, err := url.Parse(c.Remote.URL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			add("remote.url: %q must be an http(s) URL", c.Remote.URL)
+		}
+	}
+	if c.Remote.Timeout < 0 {
+		add("remote.timeout: must not be negative")
+	}
+
+	// Auth
+	for _, entry := range c.Auth.APIKeys {
+		if _, err := auth.ParseConfigEntry(entry); err != nil {
+			add("auth.api_keys: %v", err)
+		}
+	}
+	if c.Auth.TokenSecret != "" && len(c.Auth.TokenSecret) < auth.MinSecretLength {
+		add("auth.token_secret: must be at least %d bytes", auth.MinSecretLength)
+	}
+	if c.Auth.TokenTTL < 0 {
+		add("auth.token_ttl: must not be negative")
+	}
+
+	// Metrics
+	if c.Metrics.ConsoleInterval < 0 {
+		add("metrics.console_interval: must not be negative")
+	}
+
+	// Tracing
+	if !slices.Contains(exporters, c.Tracing.Exporter) {
+		add("tracing.exporter: %q must be one of %v", c.Tracing.Exporter, exporters)
+	}
+	if c.Tracing.Exporter == "file" && c.Tracing.Path == "" {
+		add("tracing.path: required with the file exporter")
+	}
+	if c.Tracing.SampleRatio < 0 || c.Tracing.SampleRatio > 1 {
+		add("tracing.sample_ratio: %v must be between 0.0 and 1.0", c.Tracing.SampleRatio)
+	}
+
+	// Audit
+	if c.Audit.Enabled && c.Audit.Path == "" {
+		add("audit.path: required when the audit log is enabled")
+	}
+	if c.Audit.MaxSize < 0 {
+		add("audit.max_size: must not be negative")
+	}
+	if c.Audit.MaxAge < 0 {
+		add("audit.max_age: must not be negative")
+	}
+
+	if len(problems) > 0 {
+		return &ValidationError{Problems: problems}
+	}
+	return nil
+}
+
+// validateChannel checks a console, webhook:URL or slack:TOKEN alert channel.
+func validateChannel(channel string) error {
+	kind, target, _ := strings.Cut(channel, ":")
+	switch kind {
+	case "console":
+		return nil
+	case "webhook":
+		u, err := url.Parse(target)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("%q needs an http(s) URL", channel)
+		}
+		return nil
+	case "slack":
+		if target == "" {
+			return fmt.Errorf("%q needs a token", channel)
+		}
+		return nil
+	default:
+		return fmt.Errorf("%q must be console, webhook:URL or slack:TOKEN", channel)
+	}
+}
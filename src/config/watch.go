@@ -0,0 +1,187 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/deadelus/go-clean-app/src/logger"
+	"github.com/fsnotify/fsnotify"
+	"github.com/spf13/viper"
+)
+
+// Subscriber receives the new configuration when a section it watches changes.
+type Subscriber func(cfg *Config) error
+
+// subscription is a named subscriber of a configuration section.
+type subscription struct {
+	name    string
+	section string
+	notify  Subscriber
+}
+
+// ReloadResult describes the outcome of a reload.
+type ReloadResult struct {
+	// Applied lists the changed keys applied at runtime.
+	Applied []string
+	// RestartRequired lists the changed keys ignored until the next restart.
+	RestartRequired []string
+	// Err is set when the reload was rejected or a subscriber failed.
+	Err error
+}
+
+// Watcher reloads the configuration file and notifies subscribers of changed sections.
+type Watcher struct {
+	v           *viper.Viper
+	logger      logger.Logger
+	mu          sync.Mutex
+	current     *Config
+	subscribers []subscription
+}
+
+// NewWatcher creates a watcher starting from the configuration returned by Load.
+func NewWatcher(v *viper.Viper, cfg *Config, logger logger.Logger) *Watcher {
+	return &Watcher{
+		v:       v,
+		logger:  logger,
+		current: cfg,
+	}
+}
+
+// Current returns the configuration currently in effect.
+func (w *Watcher) Current() *Config {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.current
+}
+
+// Subscribe registers a subscriber notified when a key under section changes,
+// e.g. "logging" or "alerts".
+func (w *Watcher) Subscribe(section, name string, notify Subscriber) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.subscribers = append(w.subscribers, subscription{name: name, section: section, notify: notify})
+}
+
+// Start watches the config file and reloads it on every change.
+// It does nothing when no config file is in use.
+func (w *Watcher) Start() {
+	if w.v.ConfigFileUsed() == "" {
+		return
+	}
+
+	w.v.OnConfigChange(func(fsnotify.Event) {
+		w.Reload()
+	})
+	w.v.WatchConfig()
+
+	w.logger.Info("Watching configuration file", map[string]interface{}{
+		"file": w.v.ConfigFileUsed(),
+	})
+}
+
+// Reload re-reads the config file, validates it and applies the changed keys.
+// An invalid file leaves the current configuration untouched.
+func (w *Watcher) Reload() ReloadResult {
+	w.mu.Lock()
+	result, next, subscribers := w.prepare()
+	if result.Err == nil {
+		w.current = next
+	}
+	w.mu.Unlock()
+
+	if result.Err == nil {
+		result.Err = w.notify(subscribers, result.Applied, next)
+	}
+
+	w.log(result)
+	return result
+}
+
+// prepare loads the new configuration and keeps the current value of restart-only keys.
+func (w *Watcher) prepare() (ReloadResult, *Config, []subscription) {
+	if w.v.ConfigFileUsed() != "" {
+		if err := w.v.ReadInConfig(); err != nil {
+			return ReloadResult{Err: fmt.Errorf("%w: reading config file: %v", ErrInvalid, err)}, nil, nil
+		}
+	}
+
+	var next Config
+	if err := w.v.Unmarshal(&next); err != nil {
+		return ReloadResult{Err: fmt.Errorf("%w: %v", ErrInvalid, err)}, nil, nil
+	}
+	if err := next.Validate(); err != nil {
+		return ReloadResult{Err: err}, nil, nil
+	}
+
+	previous := map[string]reflect.Value{}
+	walk(w.current, func(f field) {
+		previous[f.Key] = f.Value
+	})
+
+	var result ReloadResult
+	walk(&next, func(f field) {
+		old := previous[f.Key]
+		if reflect.DeepEqual(old.Interface(), f.Value.Interface()) {
+			return
+		}
+		if f.Field.Tag.Get("reload") == "restart" {
+			result.RestartRequired = append(result.RestartRequired, f.Key)
+			f.Value.Set(old)
+			return
+		}
+		result.Applied = append(result.Applied, f.Key)
+	})
+
+	subscribers := make([]subscription, len(w.subscribers))
+	copy(subscribers, w.subscribers)
+
+	return result, &next, subscribers
+}
+
+// notify calls the subscribers of every section with an applied change.
+func (w *Watcher) notify(subscribers []subscription, applied []string, cfg *Config) error {
+	var failures []string
+	for _, sub := range subscribers {
+		if !sectionChanged(sub.section, applied) {
+			continue
+		}
+		if err := sub.notify(cfg); err != nil {
+			failures = append(failures, fmt.Sprintf("%s: %v", sub.name, err))
+		}
+	}
+
+	if len(failures) > 0 {
+		return fmt.Errorf("subscribers failed: %s", strings.Join(failures, "; "))
+	}
+	return nil
+}
+
+// log reports the outcome of a reload.
+func (w *Watcher) log(result ReloadResult) {
+	fields := map[string]interface{}{
+		"applied":          result.Applied,
+		"restart_required": result.RestartRequired,
+	}
+
+	switch {
+	case result.Err != nil:
+		fields["error"] = result.Err.Error()
+		w.logger.Error("Configuration reload failed", fields)
+	case len(result.RestartRequired) > 0:
+		w.logger.Warn("Configuration reloaded, some changes need a restart", fields)
+	default:
+		w.logger.Info("Configuration reloaded", fields)
+	}
+}
+
+// sectionChanged reports whether a key under section is in keys.
+func sectionChanged(section string, keys []string) bool {
+	for _, key := range keys {
+		if key == section || strings.HasPrefix(key, section+".") {
+			return true
+		}
+	}
+	return false
+}
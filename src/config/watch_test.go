@@ -0,0 +1,86 @@
+package config_test
+
+import (
+	"live-semantic/src/config"
+	"os"
+	"testing"
+
+	"github.com/deadelus/go-clean-app/src/logger"
+	"github.com/golang/mock/gomock"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+func newWatcher(t *testing.T, content string) (*config.Watcher, string) {
+	t.Helper()
+
+	ctrl := gomock.NewController(t)
+	mockLogger := logger.NewMockLogger(ctrl)
+	mockLogger.EXPECT().Info(gomock.Any(), gomock.Any()).AnyTimes()
+	mockLogger.EXPECT().Warn(gomock.Any(), gomock.Any()).AnyTimes()
+	mockLogger.EXPECT().Error(gomock.Any(), gomock.Any()).AnyTimes()
+
+	file := writeConfig(t, content)
+	v := viper.New()
+	cfg, err := config.Load(v, file)
+	assert.NoError(t, err)
+
+	return config.NewWatcher(v, cfg, mockLogger), file
+}
+
+func TestWatcher_Reload(t *testing.T) {
+	t.Run("should apply hot keys and notify subscribers", func(t *testing.T) {
+		// Given
+		watcher, file := newWatcher(t, "logging:\n  level: info\n")
+		var notified *config.Config
+		watcher.Subscribe("logging", "log-level", func(cfg *config.Config) error {
+			notified = cfg
+			return nil
+		})
+		watcher.Subscribe("alerts", "alerts", func(cfg *config.Config) error {
+			t.Fatal("alerts subscriber should not be notified")
+			return nil
+		})
+		assert.NoError(t, os.WriteFile(file, []byte("logging:\n  level: debug\n"), 0o600))
+
+		// When
+		result := watcher.Reload()
+
+		// Then
+		assert.NoError(t, result.Err)
+		assert.Equal(t, []string{"logging.level"}, result.Applied)
+		assert.Empty(t, result.RestartRequired)
+		assert.Equal(t, "debug", watcher.Current().Logging.Level)
+		if assert.NotNil(t, notified) {
+			assert.Equal(t, "debug", notified.Logging.Level)
+		}
+	})
+
+	t.Run("should report restart-only keys without applying them", func(t *testing.T) {
+		// Given
+		watcher, file := newWatcher(t, "server:\n  port: 9000\n")
+		assert.NoError(t, os.WriteFile(file, []byte("server:\n  port: 9001\n  shutdown_timeout: 3s\n"), 0o600))
+
+		// When
+		result := watcher.Reload()
+
+		// Then
+		assert.NoError(t, result.Err)
+		assert.Equal(t, []string{"server.shutdown_timeout"}, result.Applied)
+		assert.Equal(t, []string{"server.port"}, result.RestartRequired)
+		assert.Equal(t, 9000, watcher.Current().Server.Port)
+	})
+
+	t.Run("should keep the current configuration when invalid", func(t *testing.T) {
+		// Given
+		watcher, file := newWatcher(t, "video:\n  fps: 10\n")
+		assert.NoError(t, os.WriteFile(file, []byte("video:\n  fps: 500\n"), 0o600))
+
+		// When
+		result := watcher.Reload()
+
+		// Then
+		assert.ErrorIs(t, result.Err, config.ErrInvalid)
+		assert.Equal(t, 10, watcher.Current().Video.FPS)
+	})
+}
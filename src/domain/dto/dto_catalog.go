@@ -0,0 +1,13 @@
+package dto
+
+// FilterResponse DTO pour un filtre sémantique prédéfini
+type FilterResponse struct {
+	Name     string `json:"name"`
+	Category string `json:"category"`
+}
+
+// SourceResponse DTO pour une source vidéo
+type SourceResponse struct {
+	ID   string `json:"id"`
+	Kind string `json:"kind"` // "camera", "stream", "file"
+}
@@ -0,0 +1,41 @@
+package dto
+
+import "time"
+
+// Types d'événements du domaine
+const (
+	// EventTaskCreated suit la création d'une tâche, data est la TaskResponse
+	EventTaskCreated = "task.created"
+)
+
+// EventTypes liste les types d'événements publiés
+var EventTypes = []string{EventTaskCreated}
+
+// EventRequest DTO pour s'abonner aux événements
+type EventRequest struct {
+	// Types ne garde que les événements de ces types, tous si vide
+	Types []string `json:"types,omitempty" doc:"Only keep the events of these types, such as task.created."`
+	// LastEventID rejoue les événements suivants encore en mémoire, 0 pour ne rien rejouer
+	LastEventID uint64 `json:"last_event_id,omitempty" doc:"Replay the events following this ID."`
+	// Recent rejoue au plus ce nombre d'événements récents lorsque LastEventID vaut 0
+	Recent int `json:"recent,omitempty" doc:"Replay up to this number of recent events."`
+}
+
+// EventResponse DTO pour un événement du domaine
+type EventResponse struct {
+	ID        uint64    `json:"id"`
+	Type      string    `json:"type"`
+	Data      any       `json:"data"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// EventSubscription abonnement aux événements
+// Le canal est fermé à la fin du contexte de l'abonnement, ou lorsque l'abonné ne suit plus
+// le rythme des événements : il se réabonne alors avec le dernier identifiant reçu.
+type EventSubscription struct {
+	Events <-chan EventResponse `json:"-"`
+	// Replayed nombre d'événements rejoués en tête du canal
+	Replayed int `json:"replayed"`
+	// Truncated indique que des événements suivant LastEventID ne sont plus en mémoire
+	Truncated bool `json:"truncated"`
+}
@@ -0,0 +1,25 @@
+package dto
+
+import "strings"
+
+// ListRequest DTO pour lister une collection
+type ListRequest struct {
+	// Prefix ne garde que les éléments dont l'identifiant commence par la valeur
+	Prefix string `json:"prefix,omitempty" doc:"Only keep the items whose identifier starts with the prefix."`
+	// Limit borne le nombre d'éléments retournés, 0 pour tous
+	Limit int `json:"limit,omitempty" doc:"Maximum number of items, 0 for all."`
+}
+
+// Apply retourne les éléments dont la clé correspond au préfixe, dans la limite demandée
+func Apply[T any](req ListRequest, items []T, key func(T) string) []T {
+	out := []T{}
+	for _, item := range items {
+		if req.Limit > 0 && len(out) >= req.Limit {
+			break
+		}
+		if strings.HasPrefix(key(item), req.Prefix) {
+			out = append(out, item)
+		}
+	}
+	return out
+}
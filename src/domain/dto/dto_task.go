@@ -3,15 +3,24 @@ package dto
 import "time"
 
 // TaskRequest DTO pour créer un utilisateur
+// Le tag doc décrit les champs dans les schémas publiés par les transports
 type TaskRequest struct {
-	Title       string `json:"title"`
-	Description string `json:"name"`
+	Title       string   `json:"title" doc:"Title of the task."`
+	Description string   `json:"name" doc:"Description of the task."`
+	Source      string   `json:"source,omitempty" doc:"Video source to analyse, e.g. cam0."`
+	Filters     []string `json:"filters,omitempty" doc:"Semantic filters to detect, e.g. person running."`
+	Resolution  string   `json:"resolution,omitempty" doc:"Capture resolution: 480p, 720p, 1080p or auto."`
 }
 
 // TaskResponse DTO pour la réponse utilisateur
+// CreatedBy est le sujet du principal ayant créé la tâche, "local" pour les commandes en processus
 type TaskResponse struct {
 	ID          string    `json:"id"`
 	Title       string    `json:"title"`
 	Description string    `json:"description"`
+	Source      string    `json:"source,omitempty"`
+	Filters     []string  `json:"filters,omitempty"`
+	Resolution  string    `json:"resolution,omitempty"`
+	CreatedBy   string    `json:"created_by,omitempty"`
 	CreatedAt   time.Time `json:"created_at"`
 }
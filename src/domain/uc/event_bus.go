@@ -0,0 +1,143 @@
+package uc
+
+import (
+	"context"
+	"live-semantic/src/domain/dto"
+	"live-semantic/src/lifecycle"
+	"live-semantic/src/tracing"
+	"slices"
+	"sync"
+	"time"
+)
+
+// DefaultEventBuffer is the number of events kept for the subscribers resuming a stream.
+const DefaultEventBuffer = 256
+
+// subscriberBuffer is the number of pending events before a slow subscriber is dropped.
+const subscriberBuffer = 64
+
+// eventBus broadcasts the domain events and keeps the latest ones for resumption.
+type eventBus struct {
+	mu      sync.Mutex
+	lastID  uint64
+	history []dto.EventResponse
+	size    int
+	subs    map[*subscriber]struct{}
+}
+
+// subscriber receives the events of its types, or of every type when types is empty.
+type subscriber struct {
+	types []string
+	ch    chan dto.EventResponse
+}
+
+func newEventBus(size int) *eventBus {
+	return &eventBus{
+		size: size,
+		subs: map[*subscriber]struct{}{},
+	}
+}
+
+// publish numbers the event, keeps it and broadcasts it.
+// A subscriber whose queue is full is dropped and its channel closed.
+func (b *eventBus) publish(ctx context.Context, eventType string, data any) dto.EventResponse {
+	_, span := tracing.Start(ctx, "events.Publish", tracing.WithAttributes(tracing.Attr("event.type", eventType)))
+	defer span.End()
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.lastID++
+	event := dto.EventResponse{ID: b.lastID, Type: eventType, Data: data, CreatedAt: time.Now()}
+	span.SetAttributes(tracing.Attr("event.id", int64(event.ID)), tracing.Attr("event.subscribers", len(b.subs)))
+
+	if b.size > 0 {
+		if len(b.history) >= b.size {
+			b.history = slices.Delete(b.history, 0, len(b.history)-b.size+1)
+		}
+		b.history = append(b.history, event)
+	}
+
+	for sub := range b.subs {
+		if !sub.accepts(eventType) {
+			continue
+		}
+		select {
+		case sub.ch <- event:
+		default:
+			delete(b.subs, sub)
+			close(sub.ch)
+		}
+	}
+	return event
+}
+
+// subscribe replays the kept events following lastID, or the recent ones without lastID,
+// then delivers the new ones. The returned func ends the subscription.
+func (b *eventBus) subscribe(types []string, lastID uint64, recent int) (dto.EventSubscription, func()) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	sub := &subscriber{types: types}
+
+	var replay []dto.EventResponse
+	truncated := false
+	if lastID > 0 {
+		// An unknown ID comes from a previous instance, everything kept is new
+		if lastID > b.lastID {
+			lastID = 0
+			truncated = true
+		}
+		if len(b.history) > 0 && b.history[0].ID > lastID+1 {
+			truncated = true
+		}
+		for _, event := range b.history {
+			if event.ID > lastID && sub.accepts(event.Type) {
+				replay = append(replay, event)
+			}
+		}
+	} else if recent > 0 {
+		for i := len(b.history) - 1; i >= 0 && len(replay) < recent; i-- {
+			if sub.accepts(b.history[i].Type) {
+				replay = append(replay, b.history[i])
+			}
+		}
+		slices.Reverse(replay)
+	}
+
+	sub.ch = make(chan dto.EventResponse, len(replay)+subscriberBuffer)
+	for _, event := range replay {
+		sub.ch <- event
+	}
+	b.subs[sub] = struct{}{}
+
+	cancel := func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		if _, ok := b.subs[sub]; ok {
+			delete(b.subs, sub)
+			close(sub.ch)
+		}
+	}
+	return dto.EventSubscription{Events: sub.ch, Replayed: len(replay), Truncated: truncated}, cancel
+}
+
+func (s *subscriber) accepts(eventType string) bool {
+	return len(s.types) == 0 || slices.Contains(s.types, eventType)
+}
+
+// queues reports the kept events and the events pending for the subscribers.
+func (b *eventBus) queues() []lifecycle.Queue {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	pending := lifecycle.Queue{Name: "events.subscribers", Consumers: len(b.subs)}
+	for sub := range b.subs {
+		pending.Depth += len(sub.ch)
+		pending.Capacity += cap(sub.ch)
+	}
+	return []lifecycle.Queue{
+		{Name: "events.history", Depth: len(b.history), Capacity: b.size},
+		pending,
+	}
+}
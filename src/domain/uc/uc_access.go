@@ -0,0 +1,150 @@
+package uc
+
+import (
+	"context"
+	"live-semantic/src/auth"
+	"time"
+)
+
+// Actions checked and audited by the use cases.
+const (
+	ActionTaskCreate     = "task.create"
+	ActionTaskList       = "task.list"
+	ActionFilterList     = "filter.list"
+	ActionSourceList     = "source.list"
+	ActionEventSubscribe = "event.subscribe"
+)
+
+// actionScopes maps each action to the scope it requires.
+var actionScopes = map[string]string{
+	ActionTaskCreate:     auth.ScopeTasksWrite,
+	ActionTaskList:       auth.ScopeTasksRead,
+	ActionFilterList:     auth.ScopeTasksRead,
+	ActionSourceList:     auth.ScopeTasksRead,
+	ActionEventSubscribe: auth.ScopeTasksRead,
+}
+
+// LocalUser is the user of the calls made without principal by the in-process commands.
+const LocalUser = "local"
+
+// Outcomes of the audited actions.
+const (
+	// OutcomeSuccess is the outcome of a mutating action carried out.
+	OutcomeSuccess = "success"
+	// OutcomeFailure is the outcome of a mutating action allowed but failed.
+	OutcomeFailure = "failure"
+	// OutcomeDenied is the outcome of an action refused to its principal.
+	OutcomeDenied = "denied"
+)
+
+// AuditRecord is an action recorded for the security reviewers.
+type AuditRecord struct {
+	Time      time.Time `json:"time"`
+	Principal string    `json:"principal"`
+	Method    string    `json:"method,omitempty"`
+	Source    string    `json:"source,omitempty"`
+	RequestID string    `json:"request_id,omitempty"`
+	Action    string    `json:"action"`
+	Resource  string    `json:"resource,omitempty"`
+	Outcome   string    `json:"outcome"`
+	Reason    string    `json:"reason,omitempty"`
+}
+
+// NewAuditRecord returns the record of an action by the principal of ctx, LocalUser without principal,
+// through the transport request carried by ctx.
+func NewAuditRecord(ctx context.Context, action, outcome string) AuditRecord {
+	record := AuditRecord{
+		Time:      time.Now().UTC(),
+		Principal: LocalUser,
+		Action:    action,
+		Outcome:   outcome,
+	}
+	if principal, ok := auth.PrincipalFrom(ctx); ok {
+		record.Principal = principal.Subject
+		record.Method = principal.Method
+	}
+	if request, ok := RequestFrom(ctx); ok {
+		record.Source = request.Source
+		record.RequestID = request.ID
+	}
+	return record
+}
+
+// Request identifies the transport request behind a use case call.
+type Request struct {
+	// ID correlates the audit records, logs and responses of a request.
+	ID string
+	// Source is the transport of the request: cli, web, websocket, rpc, pipe or mcp.
+	Source string
+}
+
+type requestKey struct{}
+
+// WithRequest returns a context carrying the transport request.
+func WithRequest(ctx context.Context, request Request) context.Context {
+	return context.WithValue(ctx, requestKey{}, request)
+}
+
+// RequestFrom returns the transport request carried by ctx.
+func RequestFrom(ctx context.Context) (Request, bool) {
+	request, ok := ctx.Value(requestKey{}).(Request)
+	return request, ok
+}
+
+// Auditor records the audit trail of the use cases.
+type Auditor interface {
+	Record(ctx context.Context, record AuditRecord)
+}
+
+// DiscardAuditor drops every record, the auditor of the use cases when no audit log is configured.
+// The audit trail is never written to the application log, which has its own retention and readers.
+var DiscardAuditor Auditor = discardAuditor{}
+
+type discardAuditor struct{}
+
+// Record implements Auditor.
+func (discardAuditor) Record(ctx context.Context, record AuditRecord) {}
+
+// WithAuditor replaces the auditor, DiscardAuditor by default.
+func WithAuditor(auditor Auditor) Option {
+	return func(uc *UseCase) {
+		uc.auditor = auditor
+	}
+}
+
+// authorize checks that the principal of ctx is granted the scope of the action, and audits a denial.
+// Calls without principal come from the in-process commands and are trusted.
+func (uc *UseCase) authorize(ctx context.Context, action string) error {
+	if _, ok := auth.PrincipalFrom(ctx); !ok {
+		return nil
+	}
+
+	err := auth.Check(ctx, actionScopes[action])
+	if err == nil {
+		return nil
+	}
+
+	record := NewAuditRecord(ctx, action, OutcomeDenied)
+	record.Reason = err.Error()
+	uc.auditor.Record(ctx, record)
+	return err
+}
+
+// audit records the outcome of a mutating action on resource, a failure when reason is set.
+func (uc *UseCase) audit(ctx context.Context, action, resource, reason string) {
+	record := NewAuditRecord(ctx, action, OutcomeSuccess)
+	record.Resource = resource
+	if reason != "" {
+		record.Outcome = OutcomeFailure
+		record.Reason = reason
+	}
+	uc.auditor.Record(ctx, record)
+}
+
+// currentUser returns the subject of the principal of ctx, or LocalUser without principal.
+func currentUser(ctx context.Context) string {
+	if principal, ok := auth.PrincipalFrom(ctx); ok {
+		return principal.Subject
+	}
+	return LocalUser
+}
@@ -0,0 +1,99 @@
+package uc_test
+
+import (
+	"context"
+	"live-semantic/src/auth"
+	"live-semantic/src/domain/dto"
+	"live-semantic/src/domain/uc"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// recordingAuditor keeps the recorded entries
+type recordingAuditor struct {
+	records []uc.AuditRecord
+}
+
+func (a *recordingAuditor) Record(ctx context.Context, record uc.AuditRecord) {
+	a.records = append(a.records, record)
+}
+
+func withPrincipal(subject string, roles ...string) context.Context {
+	return auth.WithPrincipal(context.Background(), auth.Principal{Subject: subject, Method: auth.MethodAPIKey, Roles: roles})
+}
+
+func TestUseCase_Authorization(t *testing.T) {
+	t.Run("should deny and audit an action outside the roles of the principal", func(t *testing.T) {
+		// Given
+		auditor := &recordingAuditor{}
+		useCase := newUseCase(t, uc.WithAuditor(auditor))
+
+		// When
+		created, err := useCase.CreateTask(withPrincipal("dashboard", auth.RoleViewer), dto.TaskRequest{Title: "title"})
+		listed, listErr := useCase.ListTasks(withPrincipal("dashboard", auth.RoleViewer), dto.ListRequest{})
+
+		// Then
+		assert.NoError(t, err)
+		assert.False(t, created.Success)
+		assert.Contains(t, created.Error, "missing scope tasks:write")
+		assert.NoError(t, listErr)
+		assert.True(t, listed.Success)
+		assert.Empty(t, *listed.Data)
+		if assert.Len(t, auditor.records, 1) {
+			assert.Equal(t, "dashboard", auditor.records[0].Principal)
+			assert.Equal(t, uc.ActionTaskCreate, auditor.records[0].Action)
+			assert.Equal(t, uc.OutcomeDenied, auditor.records[0].Outcome)
+		}
+	})
+
+	t.Run("should audit a created task with its principal and request", func(t *testing.T) {
+		// Given
+		auditor := &recordingAuditor{}
+		useCase := newUseCase(t, uc.WithAuditor(auditor))
+		ctx := uc.WithRequest(withPrincipal("alice", auth.RoleAnalyst), uc.Request{ID: "req-42", Source: "web"})
+
+		// When
+		result, err := useCase.CreateTask(ctx, dto.TaskRequest{Title: "title"})
+
+		// Then
+		assert.NoError(t, err)
+		assert.True(t, result.Success)
+		if assert.Len(t, auditor.records, 1) {
+			record := auditor.records[0]
+			assert.Equal(t, "alice", record.Principal)
+			assert.Equal(t, auth.MethodAPIKey, record.Method)
+			assert.Equal(t, "web", record.Source)
+			assert.Equal(t, "req-42", record.RequestID)
+			assert.Equal(t, uc.ActionTaskCreate, record.Action)
+			assert.Equal(t, result.Data.ID, record.Resource)
+			assert.Equal(t, uc.OutcomeSuccess, record.Outcome)
+		}
+	})
+
+	t.Run("should record the principal creating a task", func(t *testing.T) {
+		// Given
+		useCase := newUseCase(t)
+
+		// When
+		result, err := useCase.CreateTask(withPrincipal("alice", auth.RoleAnalyst), dto.TaskRequest{Title: "title"})
+
+		// Then
+		assert.NoError(t, err)
+		assert.True(t, result.Success)
+		assert.Equal(t, "alice", result.Data.CreatedBy)
+	})
+
+	t.Run("should trust the in-process calls without principal", func(t *testing.T) {
+		// Given
+		useCase := newUseCase(t)
+
+		// When
+		result, err := useCase.CreateTask(context.Background(), dto.TaskRequest{Title: "title"})
+
+		// Then
+		assert.NoError(t, err)
+		assert.True(t, result.Success)
+		assert.Equal(t, uc.LocalUser, result.Data.CreatedBy)
+	})
+}
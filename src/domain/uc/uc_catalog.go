@@ -0,0 +1,78 @@
+package uc
+
+import (
+	"context"
+	"live-semantic/src/domain/dto"
+	"live-semantic/src/tracing"
+	"strings"
+)
+
+// filterCatalog lists the predefined semantic filters by category.
+var filterCatalog = []dto.FilterResponse{
+	{Name: "person walking", Category: "security"},
+	{Name: "person running", Category: "security"},
+	{Name: "person hiding", Category: "security"},
+	{Name: "person climbing", Category: "security"},
+	{Name: "suspicious behavior", Category: "security"},
+	{Name: "unauthorized person", Category: "security"},
+	{Name: "unauthorized access", Category: "security"},
+	{Name: "vehicle in restricted area", Category: "vehicles"},
+	{Name: "unauthorized vehicle", Category: "vehicles"},
+	{Name: "vehicle at night", Category: "vehicles"},
+	{Name: "truck loading", Category: "vehicles"},
+	{Name: "crowd gathering", Category: "incidents"},
+	{Name: "fight", Category: "incidents"},
+	{Name: "violence", Category: "incidents"},
+	{Name: "vandalism", Category: "incidents"},
+	{Name: "break-in", Category: "incidents"},
+	{Name: "theft", Category: "incidents"},
+	{Name: "person after hours", Category: "after-hours"},
+	{Name: "vehicle after hours", Category: "after-hours"},
+	{Name: "activity after hours", Category: "after-hours"},
+}
+
+// ListFilters returns the predefined semantic filters.
+func (uc *UseCase) ListFilters(ctx context.Context, req dto.ListRequest) (dto.Result[[]dto.FilterResponse], error) {
+	ctx, span := tracing.Start(ctx, "uc.ListFilters")
+	defer span.End()
+
+	if err := ctx.Err(); err != nil {
+		return dto.Failure[[]dto.FilterResponse]("context cancelled"), err
+	}
+	if err := uc.authorize(ctx, ActionFilterList); err != nil {
+		return dto.Failure[[]dto.FilterResponse](err.Error()), nil
+	}
+
+	return dto.Success(dto.Apply(req, filterCatalog, func(f dto.FilterResponse) string { return f.Name })), nil
+}
+
+// ListSources returns the declared video sources.
+func (uc *UseCase) ListSources(ctx context.Context, req dto.ListRequest) (dto.Result[[]dto.SourceResponse], error) {
+	ctx, span := tracing.Start(ctx, "uc.ListSources")
+	defer span.End()
+
+	if err := ctx.Err(); err != nil {
+		return dto.Failure[[]dto.SourceResponse]("context cancelled"), err
+	}
+	if err := uc.authorize(ctx, ActionSourceList); err != nil {
+		return dto.Failure[[]dto.SourceResponse](err.Error()), nil
+	}
+
+	sources := make([]dto.SourceResponse, 0, len(uc.sources))
+	for _, id := range uc.sources {
+		sources = append(sources, dto.SourceResponse{ID: id, Kind: sourceKind(id)})
+	}
+	return dto.Success(dto.Apply(req, sources, func(s dto.SourceResponse) string { return s.ID })), nil
+}
+
+// sourceKind classifies a source: camN devices, URL streams or files.
+func sourceKind(id string) string {
+	switch {
+	case strings.HasPrefix(id, "cam"):
+		return "camera"
+	case strings.Contains(id, "://"):
+		return "stream"
+	default:
+		return "file"
+	}
+}
@@ -0,0 +1,104 @@
+package uc_test
+
+import (
+	"context"
+	"live-semantic/src/domain/dto"
+	"live-semantic/src/domain/uc"
+	"testing"
+
+	"github.com/deadelus/go-clean-app/src/logger"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func newUseCase(t *testing.T, options ...uc.Option) uc.UseCases {
+	t.Helper()
+
+	ctrl := gomock.NewController(t)
+	mockLogger := logger.NewMockLogger(ctrl)
+	mockLogger.EXPECT().Info(gomock.Any(), gomock.Any()).AnyTimes()
+
+	useCase, err := uc.NewUseCase(mockLogger, options...)
+	assert.NoError(t, err)
+	return useCase
+}
+
+func TestUseCase_ListTasks(t *testing.T) {
+	t.Run("should list the created tasks once per ID", func(t *testing.T) {
+		// Given
+		useCase := newUseCase(t)
+		_, _ = useCase.CreateTask(context.Background(), dto.TaskRequest{Title: "first"})
+		_, _ = useCase.CreateTask(context.Background(), dto.TaskRequest{Title: "second", Source: "cam0"})
+
+		// When
+		result, err := useCase.ListTasks(context.Background(), dto.ListRequest{Prefix: "123"})
+
+		// Then
+		assert.NoError(t, err)
+		if assert.True(t, result.Success) && assert.Len(t, *result.Data, 1) {
+			assert.Equal(t, "cam0", (*result.Data)[0].Source)
+		}
+	})
+
+	t.Run("should return an empty list for an unknown prefix", func(t *testing.T) {
+		// Given
+		useCase := newUseCase(t)
+		_, _ = useCase.CreateTask(context.Background(), dto.TaskRequest{Title: "first"})
+
+		// When
+		result, err := useCase.ListTasks(context.Background(), dto.ListRequest{Prefix: "9"})
+
+		// Then
+		assert.NoError(t, err)
+		assert.Empty(t, *result.Data)
+	})
+}
+
+func TestUseCase_ListFilters(t *testing.T) {
+	t.Run("should filter the catalog by prefix and limit", func(t *testing.T) {
+		// Given
+		useCase := newUseCase(t)
+
+		// When
+		result, err := useCase.ListFilters(context.Background(), dto.ListRequest{Prefix: "vehicle", Limit: 2})
+
+		// Then
+		assert.NoError(t, err)
+		assert.Equal(t, []dto.FilterResponse{
+			{Name: "vehicle in restricted area", Category: "vehicles"},
+			{Name: "vehicle at night", Category: "vehicles"},
+		}, *result.Data)
+	})
+
+	t.Run("should fail when the context is cancelled", func(t *testing.T) {
+		// Given
+		useCase := newUseCase(t)
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		// When
+		result, err := useCase.ListFilters(ctx, dto.ListRequest{})
+
+		// Then
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.False(t, result.Success)
+	})
+}
+
+func TestUseCase_ListSources(t *testing.T) {
+	t.Run("should list the declared sources with their kind", func(t *testing.T) {
+		// Given
+		useCase := newUseCase(t, uc.WithVideoSources("cam0", "rtmp://host/live", "/videos/lobby.mp4"))
+
+		// When
+		result, err := useCase.ListSources(context.Background(), dto.ListRequest{})
+
+		// Then
+		assert.NoError(t, err)
+		assert.Equal(t, []dto.SourceResponse{
+			{ID: "cam0", Kind: "camera"},
+			{ID: "rtmp://host/live", Kind: "stream"},
+			{ID: "/videos/lobby.mp4", Kind: "file"},
+		}, *result.Data)
+	})
+}
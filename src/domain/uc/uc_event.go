@@ -0,0 +1,42 @@
+package uc
+
+import (
+	"context"
+	"fmt"
+	"live-semantic/src/domain/dto"
+	"live-semantic/src/lifecycle"
+	"live-semantic/src/tracing"
+	"slices"
+)
+
+// SubscribeEvents subscribes to the domain events until the context ends.
+func (uc *UseCase) SubscribeEvents(ctx context.Context, req dto.EventRequest) (dto.Result[dto.EventSubscription], error) {
+	_, span := tracing.Start(ctx, "uc.SubscribeEvents")
+	defer span.End()
+
+	if err := ctx.Err(); err != nil {
+		return dto.Failure[dto.EventSubscription]("context cancelled"), err
+	}
+	if err := uc.authorize(ctx, ActionEventSubscribe); err != nil {
+		return dto.Failure[dto.EventSubscription](err.Error()), nil
+	}
+
+	for _, eventType := range req.Types {
+		if !slices.Contains(dto.EventTypes, eventType) {
+			return dto.Failure[dto.EventSubscription](fmt.Sprintf("unknown event type %q (available: %v)", eventType, dto.EventTypes)), nil
+		}
+	}
+
+	subscription, cancel := uc.events.subscribe(req.Types, req.LastEventID, req.Recent)
+	go func() {
+		<-ctx.Done()
+		cancel()
+	}()
+
+	return dto.Success(subscription), nil
+}
+
+// Queues implements lifecycle.QueueProvider for the admin endpoints.
+func (uc *UseCase) Queues() []lifecycle.Queue {
+	return uc.events.queues()
+}
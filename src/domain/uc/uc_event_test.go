@@ -0,0 +1,160 @@
+package uc_test
+
+import (
+	"context"
+	"live-semantic/src/domain/dto"
+	"live-semantic/src/domain/uc"
+	"live-semantic/src/lifecycle"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// receive lit les événements disponibles sans attendre les suivants
+func receive(events <-chan dto.EventResponse) []dto.EventResponse {
+	var out []dto.EventResponse
+	for {
+		select {
+		case event, ok := <-events:
+			if !ok {
+				return out
+			}
+			out = append(out, event)
+		case <-time.After(50 * time.Millisecond):
+			return out
+		}
+	}
+}
+
+func TestUseCase_SubscribeEvents(t *testing.T) {
+	t.Run("should deliver the created tasks", func(t *testing.T) {
+		// Given
+		useCase := newUseCase(t)
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		result, err := useCase.SubscribeEvents(ctx, dto.EventRequest{Types: []string{dto.EventTaskCreated}})
+		assert.NoError(t, err)
+
+		// When
+		_, _ = useCase.CreateTask(context.Background(), dto.TaskRequest{Title: "title"})
+
+		// Then
+		events := receive(result.Data.Events)
+		if assert.Len(t, events, 1) {
+			assert.Equal(t, uint64(1), events[0].ID)
+			assert.Equal(t, dto.EventTaskCreated, events[0].Type)
+		}
+	})
+
+	t.Run("should replay the events following the last event ID", func(t *testing.T) {
+		// Given
+		useCase := newUseCase(t)
+		for range 3 {
+			_, _ = useCase.CreateTask(context.Background(), dto.TaskRequest{})
+		}
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		// When
+		result, err := useCase.SubscribeEvents(ctx, dto.EventRequest{LastEventID: 1})
+
+		// Then
+		assert.NoError(t, err)
+		assert.False(t, result.Data.Truncated)
+		events := receive(result.Data.Events)
+		if assert.Len(t, events, 2) {
+			assert.Equal(t, uint64(2), events[0].ID)
+			assert.Equal(t, uint64(3), events[1].ID)
+		}
+	})
+
+	t.Run("should replay the recent events without a last event ID", func(t *testing.T) {
+		// Given
+		useCase := newUseCase(t)
+		for range 3 {
+			_, _ = useCase.CreateTask(context.Background(), dto.TaskRequest{})
+		}
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		// When
+		result, _ := useCase.SubscribeEvents(ctx, dto.EventRequest{Recent: 2})
+
+		// Then
+		assert.Equal(t, 2, result.Data.Replayed)
+		events := receive(result.Data.Events)
+		if assert.Len(t, events, 2) {
+			assert.Equal(t, uint64(2), events[0].ID)
+			assert.Equal(t, uint64(3), events[1].ID)
+		}
+	})
+
+	t.Run("should report the events dropped from the buffer", func(t *testing.T) {
+		// Given
+		useCase := newUseCase(t, uc.WithEventBuffer(2))
+		for range 4 {
+			_, _ = useCase.CreateTask(context.Background(), dto.TaskRequest{})
+		}
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		// When
+		result, _ := useCase.SubscribeEvents(ctx, dto.EventRequest{LastEventID: 1})
+
+		// Then
+		assert.True(t, result.Data.Truncated)
+		assert.Len(t, receive(result.Data.Events), 2)
+	})
+
+	t.Run("should close the stream with the context", func(t *testing.T) {
+		// Given
+		useCase := newUseCase(t)
+		ctx, cancel := context.WithCancel(context.Background())
+		result, _ := useCase.SubscribeEvents(ctx, dto.EventRequest{})
+
+		// When
+		cancel()
+
+		// Then
+		select {
+		case _, ok := <-result.Data.Events:
+			assert.False(t, ok)
+		case <-time.After(time.Second):
+			t.Fatal("stream still open after cancellation")
+		}
+	})
+
+	t.Run("should reject an unknown event type", func(t *testing.T) {
+		// When
+		result, err := newUseCase(t).SubscribeEvents(context.Background(), dto.EventRequest{Types: []string{"unknown"}})
+
+		// Then
+		assert.NoError(t, err)
+		assert.False(t, result.Success)
+		assert.Contains(t, result.Error, "unknown event type")
+	})
+}
+
+func TestUseCase_Queues(t *testing.T) {
+	t.Run("should report the kept and pending events", func(t *testing.T) {
+		// Given
+		useCase := newUseCase(t, uc.WithEventBuffer(8))
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		_, err := useCase.SubscribeEvents(ctx, dto.EventRequest{})
+		assert.NoError(t, err)
+		_, _ = useCase.CreateTask(context.Background(), dto.TaskRequest{Title: "title"})
+
+		// When
+		queues := useCase.(lifecycle.QueueProvider).Queues()
+
+		// Then
+		if assert.Len(t, queues, 2) {
+			assert.Equal(t, lifecycle.Queue{Name: "events.history", Depth: 1, Capacity: 8}, queues[0])
+			assert.Equal(t, "events.subscribers", queues[1].Name)
+			assert.Equal(t, 1, queues[1].Depth)
+			assert.Equal(t, 1, queues[1].Consumers)
+		}
+	})
+}
@@ -3,26 +3,38 @@ package uc
 import (
 	"context"
 	"live-semantic/src/domain/dto"
+	"live-semantic/src/tracing"
 	"time"
 )
 
 func (uc *UseCase) CreateTask(ctx context.Context, er dto.TaskRequest) (dto.Result[dto.TaskResponse], error) {
+	ctx, span := tracing.Start(ctx, "uc.CreateTask")
+	defer span.End()
+
 	// Check if the context is done before proceeding
 	select {
 	case <-ctx.Done():
+		uc.audit(ctx, ActionTaskCreate, "", "context cancelled")
 		return dto.Failure[dto.TaskResponse]("context cancelled"), ctx.Err()
 	default:
 	}
 
+	if err := uc.authorize(ctx, ActionTaskCreate); err != nil {
+		return dto.Failure[dto.TaskResponse](err.Error()), nil
+	}
+
 	/*
 		Here you would typically interact with your repositories or services
 		to perform the business logic. For example, you might create a user
 		in a database and return the created user as a response.
 	*/
 
-	// Log the request for debugging purposes
+	// Log the request for debugging purposes, without the title and the description that may hold personal data
 	uc.logger.Info("Processing Task use case", map[string]interface{}{
-		"request": er,
+		"title_length":       len(er.Title),
+		"description_length": len(er.Description),
+		"source":             er.Source,
+		"filters":            er.Filters,
 	})
 
 	// For demonstration, let's return a dummy response.
@@ -30,7 +42,53 @@ func (uc *UseCase) CreateTask(ctx context.Context, er dto.TaskRequest) (dto.Resu
 		ID:          "12345",
 		Title:       "Task 1",
 		Description: "Description 1",
+		Source:      er.Source,
+		Filters:     er.Filters,
+		Resolution:  er.Resolution,
+		CreatedBy:   currentUser(ctx),
 		CreatedAt:   time.Now(),
 	}
+	uc.storeTask(ctx, response)
+	uc.events.publish(ctx, dto.EventTaskCreated, response)
+	uc.audit(ctx, ActionTaskCreate, response.ID, "")
 	return dto.Success(response), nil
 }
+
+// ListTasks returns the tasks created by this instance, most recent first.
+func (uc *UseCase) ListTasks(ctx context.Context, req dto.ListRequest) (dto.Result[[]dto.TaskResponse], error) {
+	ctx, span := tracing.Start(ctx, "uc.ListTasks")
+	defer span.End()
+
+	if err := ctx.Err(); err != nil {
+		return dto.Failure[[]dto.TaskResponse]("context cancelled"), err
+	}
+	if err := uc.authorize(ctx, ActionTaskList); err != nil {
+		return dto.Failure[[]dto.TaskResponse](err.Error()), nil
+	}
+
+	uc.tasksMu.RLock()
+	tasks := make([]dto.TaskResponse, 0, len(uc.tasks))
+	for i := len(uc.tasks) - 1; i >= 0; i-- {
+		tasks = append(tasks, uc.tasks[i])
+	}
+	uc.tasksMu.RUnlock()
+
+	return dto.Success(dto.Apply(req, tasks, func(t dto.TaskResponse) string { return t.ID })), nil
+}
+
+// storeTask keeps the created task in memory, a task created again with the same ID replaces it.
+func (uc *UseCase) storeTask(ctx context.Context, task dto.TaskResponse) {
+	_, span := tracing.Start(ctx, "store.SaveTask", tracing.WithAttributes(tracing.Attr("task.id", task.ID)))
+	defer span.End()
+
+	uc.tasksMu.Lock()
+	defer uc.tasksMu.Unlock()
+
+	for i := range uc.tasks {
+		if uc.tasks[i].ID == task.ID {
+			uc.tasks = append(uc.tasks[:i], uc.tasks[i+1:]...)
+			break
+		}
+	}
+	uc.tasks = append(uc.tasks, task)
+}
@@ -20,8 +20,13 @@ func TestUseCase_CreateTask(t *testing.T) {
 	// Create a new mock logger
 	mockLogger := logger.NewMockLogger(ctrl)
 
-	// Expect the Info method to be called
-	mockLogger.EXPECT().Info(gomock.Any(), gomock.Any()).Return()
+	// Expect the Info method to be called with the lengths of the title and the description only
+	mockLogger.EXPECT().Info("Processing Task use case", map[string]interface{}{
+		"title_length":       9,
+		"description_length": 19,
+		"source":             "",
+		"filters":            []string(nil),
+	}).Return()
 
 	// Create a new use case with the mock logger
 	useCase, err := uc.NewUseCase(mockLogger)
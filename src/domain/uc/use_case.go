@@ -4,6 +4,7 @@ package uc
 import (
 	"context"
 	"live-semantic/src/domain/dto"
+	"sync"
 
 	"github.com/deadelus/go-clean-app/src/logger"
 )
@@ -11,16 +12,48 @@ import (
 // UseCases defines the interface for the use cases in the application.
 type UseCases interface {
 	CreateTask(context.Context, dto.TaskRequest) (dto.Result[dto.TaskResponse], error)
+	ListTasks(context.Context, dto.ListRequest) (dto.Result[[]dto.TaskResponse], error)
+	ListFilters(context.Context, dto.ListRequest) (dto.Result[[]dto.FilterResponse], error)
+	ListSources(context.Context, dto.ListRequest) (dto.Result[[]dto.SourceResponse], error)
+	SubscribeEvents(context.Context, dto.EventRequest) (dto.Result[dto.EventSubscription], error)
 }
 
 // useCase implements the UseCases interface.
 type UseCase struct {
-	logger logger.Logger
+	logger  logger.Logger
+	sources []string
+	tasks   []dto.TaskResponse
+	tasksMu sync.RWMutex
+	events  *eventBus
+	auditor Auditor
+}
+
+// Option configures the use cases.
+type Option func(*UseCase)
+
+// WithVideoSources declares the video sources listed by ListSources.
+func WithVideoSources(sources ...string) Option {
+	return func(uc *UseCase) {
+		uc.sources = append(uc.sources, sources...)
+	}
+}
+
+// WithEventBuffer sets how many events are kept for the subscribers resuming after a disconnection.
+func WithEventBuffer(size int) Option {
+	return func(uc *UseCase) {
+		uc.events = newEventBus(size)
+	}
 }
 
 // NewUseCase initializes your use cases with all the necessary dependencies
-func NewUseCase(logger logger.Logger) (UseCases, error) {
-	return &UseCase{
-		logger: logger,
-	}, nil
+func NewUseCase(logger logger.Logger, options ...Option) (UseCases, error) {
+	uc := &UseCase{
+		logger:  logger,
+		events:  newEventBus(DefaultEventBuffer),
+		auditor: DiscardAuditor,
+	}
+	for _, option := range options {
+		option(uc)
+	}
+	return uc, nil
 }
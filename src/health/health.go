@@ -0,0 +1,206 @@
+// Package health aggregates the probes registered by the application components.
+package health
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// DefaultProbeTimeout bounds a single probe when the caller context has no earlier deadline.
+const DefaultProbeTimeout = 2 * time.Second
+
+// Status is the health of a component or of the whole application.
+type Status string
+
+const (
+	// StatusUp means every probe succeeded.
+	StatusUp Status = "up"
+	// StatusDegraded means only non-critical probes failed.
+	StatusDegraded Status = "degraded"
+	// StatusDown means at least one critical probe failed.
+	StatusDown Status = "down"
+)
+
+// Probe checks a dependency and returns a short description of its state.
+type Probe func(ctx context.Context) (detail string, err error)
+
+// ProbeOption configures a registered probe.
+type ProbeOption func(*entry)
+
+// NonCritical marks a probe whose failure degrades the application without making it unready.
+func NonCritical() ProbeOption {
+	return func(e *entry) {
+		e.critical = false
+	}
+}
+
+// OnDemand excludes a probe from the default checks, it only runs when requested by name.
+func OnDemand() ProbeOption {
+	return func(e *entry) {
+		e.onDemand = true
+	}
+}
+
+// entry is a registered probe.
+type entry struct {
+	name     string
+	probe    Probe
+	critical bool
+	onDemand bool
+}
+
+// ComponentReport is the result of a single probe.
+type ComponentReport struct {
+	Name     string        `json:"name"`
+	Status   Status        `json:"status"`
+	Critical bool          `json:"critical"`
+	Detail   string        `json:"detail,omitempty"`
+	Error    string        `json:"error,omitempty"`
+	Duration time.Duration `json:"-"`
+	Latency  string        `json:"latency"`
+}
+
+// Report is the aggregated result of the probes.
+type Report struct {
+	Status     Status            `json:"status"`
+	CheckedAt  time.Time         `json:"checked_at"`
+	Components []ComponentReport `json:"components"`
+}
+
+// Ready reports whether every critical probe succeeded.
+func (r Report) Ready() bool {
+	return r.Status != StatusDown
+}
+
+// Registry holds the probes of the application components.
+type Registry struct {
+	mu      sync.RWMutex
+	entries []entry
+	timeout time.Duration
+}
+
+// Option configures a registry.
+type Option func(*Registry)
+
+// WithProbeTimeout sets the time allowed to each probe.
+func WithProbeTimeout(timeout time.Duration) Option {
+	return func(r *Registry) {
+		r.timeout = timeout
+	}
+}
+
+// NewRegistry creates an empty registry, which reports the application as up.
+func NewRegistry(options ...Option) *Registry {
+	registry := &Registry{timeout: DefaultProbeTimeout}
+	for _, option := range options {
+		option(registry)
+	}
+	return registry
+}
+
+// Register adds a probe, critical unless NonCritical is given.
+// Registering a name twice replaces the previous probe.
+func (r *Registry) Register(name string, probe Probe, options ...ProbeOption) {
+	e := entry{name: name, probe: probe, critical: true}
+	for _, option := range options {
+		option(&e)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i := range r.entries {
+		if r.entries[i].name == name {
+			r.entries[i] = e
+			return
+		}
+	}
+	r.entries = append(r.entries, e)
+}
+
+// Names returns the registered probe names in registration order.
+func (r *Registry) Names() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	names := make([]string, len(r.entries))
+	for i, e := range r.entries {
+		names[i] = e.name
+	}
+	return names
+}
+
+// Check runs the default probes plus the on-demand probes named in include, concurrently.
+// Components are reported in registration order.
+func (r *Registry) Check(ctx context.Context, include ...string) Report {
+	r.mu.RLock()
+	var selected []entry
+	for _, e := range r.entries {
+		if !e.onDemand || contains(include, e.name) {
+			selected = append(selected, e)
+		}
+	}
+	r.mu.RUnlock()
+
+	report := Report{
+		Status:     StatusUp,
+		CheckedAt:  time.Now(),
+		Components: make([]ComponentReport, len(selected)),
+	}
+
+	var wg sync.WaitGroup
+	for i, e := range selected {
+		wg.Add(1)
+		go func(i int, e entry) {
+			defer wg.Done()
+			report.Components[i] = r.run(ctx, e)
+		}(i, e)
+	}
+	wg.Wait()
+
+	for _, c := range report.Components {
+		switch {
+		case c.Status == StatusUp:
+		case c.Critical:
+			report.Status = StatusDown
+		case report.Status == StatusUp:
+			report.Status = StatusDegraded
+		}
+	}
+	return report
+}
+
+// run executes a probe within the registry timeout and recovers its panics.
+func (r *Registry) run(ctx context.Context, e entry) (report ComponentReport) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	start := time.Now()
+	report = ComponentReport{Name: e.name, Status: StatusUp, Critical: e.critical}
+	defer func() {
+		if p := recover(); p != nil {
+			report.Status = StatusDown
+			report.Error = fmt.Sprintf("probe panicked: %v", p)
+		}
+		report.Duration = time.Since(start)
+		report.Latency = report.Duration.Round(time.Microsecond).String()
+	}()
+
+	detail, err := e.probe(ctx)
+	report.Detail = detail
+	if err != nil {
+		report.Status = StatusDown
+		report.Error = err.Error()
+	}
+	return report
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,185 @@
+package health_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"live-semantic/src/health"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func up(detail string) health.Probe {
+	return func(ctx context.Context) (string, error) {
+		return detail, nil
+	}
+}
+
+func down(message string) health.Probe {
+	return func(ctx context.Context) (string, error) {
+		return "", errors.New(message)
+	}
+}
+
+func TestRegistry_Check(t *testing.T) {
+	t.Run("should report up without probes", func(t *testing.T) {
+		// When
+		report := health.NewRegistry().Check(context.Background())
+
+		// Then
+		assert.Equal(t, health.StatusUp, report.Status)
+		assert.True(t, report.Ready())
+		assert.Empty(t, report.Components)
+	})
+
+	t.Run("should degrade on a non-critical failure", func(t *testing.T) {
+		// Given
+		registry := health.NewRegistry()
+		registry.Register("storage", up("memory"))
+		registry.Register("video", down("camera not found"), health.NonCritical())
+
+		// When
+		report := registry.Check(context.Background())
+
+		// Then
+		assert.Equal(t, health.StatusDegraded, report.Status)
+		assert.True(t, report.Ready())
+		if assert.Len(t, report.Components, 2) {
+			assert.Equal(t, "storage", report.Components[0].Name)
+			assert.Equal(t, "memory", report.Components[0].Detail)
+			assert.Equal(t, health.StatusDown, report.Components[1].Status)
+			assert.Equal(t, "camera not found", report.Components[1].Error)
+		}
+	})
+
+	t.Run("should be down on a critical failure", func(t *testing.T) {
+		// Given
+		registry := health.NewRegistry()
+		registry.Register("storage", down("database unreachable"))
+		registry.Register("video", down("camera not found"), health.NonCritical())
+
+		// When
+		report := registry.Check(context.Background())
+
+		// Then
+		assert.Equal(t, health.StatusDown, report.Status)
+		assert.False(t, report.Ready())
+	})
+
+	t.Run("should run on-demand probes only when included", func(t *testing.T) {
+		// Given
+		registry := health.NewRegistry()
+		registry.Register("models", down("missing"), health.OnDemand())
+
+		// When
+		skipped := registry.Check(context.Background())
+		included := registry.Check(context.Background(), "models")
+
+		// Then
+		assert.Empty(t, skipped.Components)
+		assert.Len(t, included.Components, 1)
+		assert.Equal(t, health.StatusDown, included.Status)
+	})
+
+	t.Run("should bound probes with the timeout and recover panics", func(t *testing.T) {
+		// Given
+		registry := health.NewRegistry(health.WithProbeTimeout(10 * time.Millisecond))
+		registry.Register("slow", func(ctx context.Context) (string, error) {
+			<-ctx.Done()
+			return "", ctx.Err()
+		})
+		registry.Register("broken", func(ctx context.Context) (string, error) {
+			panic("boom")
+		})
+
+		// When
+		report := registry.Check(context.Background())
+
+		// Then
+		assert.Equal(t, health.StatusDown, report.Status)
+		assert.Contains(t, report.Components[0].Error, "deadline exceeded")
+		assert.Equal(t, "probe panicked: boom", report.Components[1].Error)
+	})
+
+	t.Run("should replace a probe registered twice", func(t *testing.T) {
+		// Given
+		registry := health.NewRegistry()
+		registry.Register("storage", down("unreachable"))
+		registry.Register("storage", up("memory"))
+
+		// When
+		report := registry.Check(context.Background())
+
+		// Then
+		assert.Equal(t, []string{"storage"}, registry.Names())
+		assert.Equal(t, health.StatusUp, report.Status)
+	})
+}
+
+func serve(endpoints health.Endpoints, path string) (int, map[string]any) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	endpoints.Register(router)
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, path, nil))
+
+	var body map[string]any
+	_ = json.Unmarshal(recorder.Body.Bytes(), &body)
+	return recorder.Code, body
+}
+
+func TestEndpoints(t *testing.T) {
+	t.Run("should aggregate the probes on /health", func(t *testing.T) {
+		// Given
+		registry := health.NewRegistry()
+		registry.Register("video", down("camera not found"), health.NonCritical())
+		endpoints := health.Endpoints{Registry: registry, Service: "live-semantic", Version: "1.0.0"}
+
+		// When
+		code, body := serve(endpoints, "/health")
+
+		// Then
+		assert.Equal(t, http.StatusOK, code)
+		assert.Equal(t, "degraded", body["status"])
+		assert.Len(t, body["components"], 1)
+	})
+
+	t.Run("should report unready when a critical probe fails", func(t *testing.T) {
+		// Given
+		registry := health.NewRegistry()
+		registry.Register("storage", down("database unreachable"))
+		endpoints := health.Endpoints{Registry: registry}
+
+		// When
+		healthCode, _ := serve(endpoints, "/health")
+		readyCode, body := serve(endpoints, "/readyz")
+		liveCode, _ := serve(endpoints, "/livez")
+
+		// Then
+		assert.Equal(t, http.StatusServiceUnavailable, healthCode)
+		assert.Equal(t, http.StatusServiceUnavailable, readyCode)
+		assert.Equal(t, []any{"storage"}, body["failed"])
+		assert.Equal(t, http.StatusOK, liveCode)
+	})
+
+	t.Run("should report unready while draining", func(t *testing.T) {
+		// Given
+		endpoints := health.Endpoints{
+			Registry: health.NewRegistry(),
+			Draining: func() bool { return true },
+		}
+
+		// When
+		code, body := serve(endpoints, "/readyz")
+
+		// Then
+		assert.Equal(t, http.StatusServiceUnavailable, code)
+		assert.Equal(t, "draining", body["status"])
+	})
+}
@@ -0,0 +1,100 @@
+package health
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Endpoints serves /health, /livez and /readyz for a server.
+type Endpoints struct {
+	Registry *Registry
+	Service  string
+	Version  string
+	// Draining reports a server that is shutting down, which is no longer ready.
+	Draining func() bool
+	// Extra adds server specific fields to the /health response.
+	Extra func() gin.H
+}
+
+// Register mounts the health endpoints on the router.
+func (e Endpoints) Register(router gin.IRouter) {
+	router.GET("/health", e.health)
+	router.GET("/livez", e.live)
+	router.GET("/readyz", e.ready)
+}
+
+// health returns the aggregated report, 503 when a critical probe fails.
+func (e Endpoints) health(c *gin.Context) {
+	report := e.check(c)
+
+	body := gin.H{
+		"status":     report.Status,
+		"service":    e.Service,
+		"version":    e.Version,
+		"checked_at": report.CheckedAt,
+		"components": report.Components,
+	}
+	if e.Extra != nil {
+		for key, value := range e.Extra() {
+			body[key] = value
+		}
+	}
+
+	c.JSON(statusCode(report.Ready() && !e.draining()), body)
+}
+
+// live reports that the process is serving requests, without running probes.
+func (e Endpoints) live(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"status":  "alive",
+		"service": e.Service,
+	})
+}
+
+// ready reports whether the server can accept traffic.
+func (e Endpoints) ready(c *gin.Context) {
+	if e.draining() {
+		c.JSON(http.StatusServiceUnavailable, gin.H{
+			"status": "draining",
+			"ready":  false,
+		})
+		return
+	}
+
+	report := e.check(c)
+	body := gin.H{
+		"status": report.Status,
+		"ready":  report.Ready(),
+	}
+
+	var failed []string
+	for _, component := range report.Components {
+		if component.Status != StatusUp && component.Critical {
+			failed = append(failed, component.Name)
+		}
+	}
+	if len(failed) > 0 {
+		body["failed"] = failed
+	}
+
+	c.JSON(statusCode(report.Ready()), body)
+}
+
+func (e Endpoints) check(c *gin.Context) Report {
+	if e.Registry == nil {
+		return NewRegistry().Check(c.Request.Context())
+	}
+	return e.Registry.Check(c.Request.Context())
+}
+
+func (e Endpoints) draining() bool {
+	return e.Draining != nil && e.Draining()
+}
+
+func statusCode(ok bool) int {
+	if ok {
+		return http.StatusOK
+	}
+	return http.StatusServiceUnavailable
+}
@@ -0,0 +1,256 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"live-semantic/src/config"
+	"net"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// Probe names registered by RegisterProbes.
+const (
+	ProbeStorage = "storage"
+	ProbeAI      = "ai"
+	ProbeModels  = "models"
+	ProbeVideo   = "video"
+	ProbeAlerts  = "alerts"
+)
+
+// requiredModels lists the files the onnx provider loads from ai.model_path.
+var requiredModels = []string{"clip_text.onnx"}
+
+// defaultPorts completes source and sink URLs without an explicit port.
+var defaultPorts = map[string]string{
+	"http":  "80",
+	"https": "443",
+	"rtmp":  "1935",
+	"rtsp":  "554",
+}
+
+// RegisterProbes registers the storage, AI provider, model, video source and alert sink probes.
+// current is read on every check so a hot reloaded configuration is picked up.
+// Only storage is critical, the models probe runs on demand.
+func RegisterProbes(r *Registry, current func() *config.Config) {
+	r.Register(ProbeStorage, func(ctx context.Context) (string, error) {
+		return checkStorage(ctx, current().Storage)
+	})
+	r.Register(ProbeAI, func(ctx context.Context) (string, error) {
+		return checkAI(current().AI)
+	}, NonCritical())
+	r.Register(ProbeModels, func(ctx context.Context) (string, error) {
+		return checkModels(current().AI)
+	}, NonCritical(), OnDemand())
+	r.Register(ProbeVideo, func(ctx context.Context) (string, error) {
+		return checkVideo(ctx, current().Video.Source)
+	}, NonCritical())
+	r.Register(ProbeAlerts, func(ctx context.Context) (string, error) {
+		return checkAlerts(ctx, current().Alerts.Channels)
+	}, NonCritical())
+}
+
+// Fix creates the missing storage and model directories and returns the actions taken.
+func Fix(cfg *config.Config) ([]string, error) {
+	var actions []string
+	for _, dir := range []string{cfg.Storage.Path, cfg.AI.ModelPath} {
+		if dir == "" {
+			continue
+		}
+		if _, err := os.Stat(dir); !errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return actions, fmt.Errorf("creating %s: %w", dir, err)
+		}
+		actions = append(actions, "created directory "+dir)
+	}
+	return actions, nil
+}
+
+// checkStorage checks the storage directory and, for postgres, that the database accepts connections.
+func checkStorage(ctx context.Context, cfg config.StorageConfig) (string, error) {
+	detail := cfg.Driver + " driver"
+	if cfg.Path != "" {
+		info, err := os.Stat(cfg.Path)
+		switch {
+		case errors.Is(err, os.ErrNotExist):
+			detail += fmt.Sprintf(", path %s not created yet", cfg.Path)
+		case err != nil:
+			return detail, err
+		case !info.IsDir():
+			return detail, fmt.Errorf("storage path %s is not a directory", cfg.Path)
+		default:
+			detail += ", path " + cfg.Path
+		}
+	}
+
+	if cfg.Driver != "postgres" {
+		return detail, nil
+	}
+
+	address, err := dsnAddress(cfg.DSN)
+	if err != nil {
+		return detail, err
+	}
+	if err := dial(ctx, address); err != nil {
+		return detail, fmt.Errorf("database unreachable: %w", err)
+	}
+	return detail + ", database " + address, nil
+}
+
+// checkAI checks that the embedding provider can be started.
+func checkAI(cfg config.AIConfig) (string, error) {
+	detail := fmt.Sprintf("%s provider, model %s", cfg.Provider, cfg.Model)
+	switch cfg.Provider {
+	case "onnx":
+		if err := checkDir(cfg.ModelPath); err != nil {
+			return detail, err
+		}
+	case "python":
+		if _, err := exec.LookPath("python3"); err != nil {
+			return detail, fmt.Errorf("python3 not found in PATH")
+		}
+	}
+	return detail, nil
+}
+
+// checkModels checks that the model files loaded by the provider are available.
+func checkModels(cfg config.AIConfig) (string, error) {
+	if cfg.Provider != "onnx" {
+		return fmt.Sprintf("models served by the %s provider", cfg.Provider), nil
+	}
+	if err := checkDir(cfg.ModelPath); err != nil {
+		return "", err
+	}
+
+	var missing []string
+	for _, name := range requiredModels {
+		if _, err := os.Stat(filepath.Join(cfg.ModelPath, name)); err != nil {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) > 0 {
+		return "", fmt.Errorf("missing %s in %s", strings.Join(missing, ", "), cfg.ModelPath)
+	}
+
+	found, _ := filepath.Glob(filepath.Join(cfg.ModelPath, "*.onnx"))
+	names := make([]string, len(found))
+	for i, file := range found {
+		names[i] = filepath.Base(file)
+	}
+	return "found " + strings.Join(names, ", "), nil
+}
+
+// checkVideo checks a camera device, a stream URL or a video file.
+func checkVideo(ctx context.Context, source string) (string, error) {
+	if device, ok := strings.CutPrefix(source, "cam"); ok && device != "" && !strings.Contains(device, "/") {
+		path := "/dev/video" + device
+		if _, err := os.Stat(path); err != nil {
+			return source, fmt.Errorf("camera %s not found", path)
+		}
+		return source + " (" + path + ")", nil
+	}
+
+	if u, err := url.Parse(source); err == nil && defaultPorts[u.Scheme] != "" {
+		if err := dial(ctx, hostPort(u)); err != nil {
+			return source, fmt.Errorf("stream unreachable: %w", err)
+		}
+		return source, nil
+	}
+
+	if _, err := os.Stat(source); err != nil {
+		return source, fmt.Errorf("video file %s not found", source)
+	}
+	return source, nil
+}
+
+// checkAlerts checks that every webhook and slack sink is reachable.
+func checkAlerts(ctx context.Context, channels []string) (string, error) {
+	var kinds, failures []string
+	for _, channel := range channels {
+		kind, target, _ := strings.Cut(channel, ":")
+		kinds = append(kinds, kind)
+
+		var address string
+		switch kind {
+		case "webhook":
+			u, err := url.Parse(target)
+			if err != nil {
+				failures = append(failures, fmt.Sprintf("webhook: %v", err))
+				continue
+			}
+			address = hostPort(u)
+		case "slack":
+			address = "slack.com:443"
+		default:
+			continue
+		}
+
+		if err := dial(ctx, address); err != nil {
+			failures = append(failures, fmt.Sprintf("%s unreachable: %v", kind, err))
+		}
+	}
+
+	detail := strings.Join(kinds, ", ")
+	if len(failures) > 0 {
+		return detail, errors.New(strings.Join(failures, "; "))
+	}
+	return detail, nil
+}
+
+func checkDir(dir string) error {
+	info, err := os.Stat(dir)
+	if err != nil {
+		return fmt.Errorf("model path %s not found", dir)
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("model path %s is not a directory", dir)
+	}
+	return nil
+}
+
+// dsnAddress extracts host:port from a postgres URL or key=value DSN.
+func dsnAddress(dsn string) (string, error) {
+	if u, err := url.Parse(dsn); err == nil && u.Host != "" {
+		if u.Port() == "" {
+			return net.JoinHostPort(u.Hostname(), "5432"), nil
+		}
+		return u.Host, nil
+	}
+
+	host, port := "localhost", "5432"
+	for _, pair := range strings.Fields(dsn) {
+		key, value, _ := strings.Cut(pair, "=")
+		switch key {
+		case "host":
+			host = value
+		case "port":
+			port = value
+		}
+	}
+	if host == "" {
+		return "", fmt.Errorf("storage dsn has no host")
+	}
+	return net.JoinHostPort(host, port), nil
+}
+
+func hostPort(u *url.URL) string {
+	if u.Port() != "" {
+		return u.Host
+	}
+	return net.JoinHostPort(u.Hostname(), defaultPorts[u.Scheme])
+}
+
+func dial(ctx context.Context, address string) error {
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", address)
+	if err != nil {
+		return err
+	}
+	return conn.Close()
+}
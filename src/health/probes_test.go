@@ -0,0 +1,136 @@
+package health_test
+
+import (
+	"context"
+	"live-semantic/src/config"
+	"live-semantic/src/health"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func checkProbes(cfg config.Config, include ...string) map[string]health.ComponentReport {
+	registry := health.NewRegistry()
+	health.RegisterProbes(registry, func() *config.Config { return &cfg })
+
+	components := map[string]health.ComponentReport{}
+	for _, component := range registry.Check(context.Background(), include...).Components {
+		components[component.Name] = component
+	}
+	return components
+}
+
+func TestRegisterProbes(t *testing.T) {
+	t.Run("should report missing models on demand", func(t *testing.T) {
+		// Given
+		cfg := config.Default()
+		cfg.AI.ModelPath = t.TempDir()
+
+		// When
+		skipped := checkProbes(cfg)
+		included := checkProbes(cfg, health.ProbeModels)
+
+		// Then
+		assert.NotContains(t, skipped, health.ProbeModels)
+		assert.Equal(t, health.StatusUp, included[health.ProbeAI].Status)
+		assert.Equal(t, health.StatusDown, included[health.ProbeModels].Status)
+		assert.Contains(t, included[health.ProbeModels].Error, "clip_text.onnx")
+	})
+
+	t.Run("should find the exported models", func(t *testing.T) {
+		// Given
+		cfg := config.Default()
+		cfg.AI.ModelPath = t.TempDir()
+		assert.NoError(t, os.WriteFile(filepath.Join(cfg.AI.ModelPath, "clip_text.onnx"), nil, 0o600))
+
+		// When
+		components := checkProbes(cfg, health.ProbeModels)
+
+		// Then
+		assert.Equal(t, health.StatusUp, components[health.ProbeModels].Status)
+		assert.Equal(t, "found clip_text.onnx", components[health.ProbeModels].Detail)
+	})
+
+	t.Run("should check video files and streams", func(t *testing.T) {
+		// Given
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		assert.NoError(t, err)
+		defer listener.Close()
+
+		file := filepath.Join(t.TempDir(), "sample.mp4")
+		assert.NoError(t, os.WriteFile(file, nil, 0o600))
+
+		for source, want := range map[string]health.Status{
+			file:                                 health.StatusUp,
+			file + ".missing":                    health.StatusDown,
+			"rtmp://" + listener.Addr().String(): health.StatusUp,
+			"http://" + listener.Addr().String() + "/": health.StatusUp,
+		} {
+			cfg := config.Default()
+			cfg.Video.Source = source
+
+			// When
+			components := checkProbes(cfg)
+
+			// Then
+			assert.Equal(t, want, components[health.ProbeVideo].Status, source)
+		}
+	})
+
+	t.Run("should fail storage when the database is unreachable", func(t *testing.T) {
+		// Given
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		assert.NoError(t, err)
+		address := listener.Addr().String()
+		listener.Close()
+
+		cfg := config.Default()
+		cfg.Storage.Driver = "postgres"
+		cfg.Storage.DSN = "postgres://user:pass@" + address + "/live"
+
+		// When
+		components := checkProbes(cfg)
+
+		// Then
+		assert.Equal(t, health.StatusDown, components[health.ProbeStorage].Status)
+		assert.Contains(t, components[health.ProbeStorage].Error, "database unreachable")
+	})
+
+	t.Run("should check webhook sinks", func(t *testing.T) {
+		// Given
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		assert.NoError(t, err)
+		defer listener.Close()
+
+		cfg := config.Default()
+		cfg.Alerts.Channels = []string{"console", "webhook:http://" + listener.Addr().String() + "/alerts"}
+
+		// When
+		components := checkProbes(cfg)
+
+		// Then
+		assert.Equal(t, health.StatusUp, components[health.ProbeAlerts].Status)
+		assert.Equal(t, "console, webhook", components[health.ProbeAlerts].Detail)
+	})
+}
+
+func TestFix(t *testing.T) {
+	t.Run("should create the missing directories", func(t *testing.T) {
+		// Given
+		root := t.TempDir()
+		cfg := config.Default()
+		cfg.Storage.Path = filepath.Join(root, "data")
+		cfg.AI.ModelPath = root
+
+		// When
+		actions, err := health.Fix(&cfg)
+
+		// Then
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"created directory " + cfg.Storage.Path}, actions)
+		assert.DirExists(t, cfg.Storage.Path)
+	})
+}
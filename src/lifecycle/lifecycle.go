@@ -0,0 +1,197 @@
+// Package lifecycle keeps the long-running components of the process, such as the servers,
+// so that the admin endpoints can report their state, drain them, list their sessions and
+// the depth of their queues.
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ErrNotFound is returned for an unknown component or session.
+var ErrNotFound = errors.New("not found")
+
+// State is the state of a component.
+type State string
+
+const (
+	// StateRunning means the component serves new requests.
+	StateRunning State = "running"
+	// StateDraining means the component refuses new requests and lets the current ones end.
+	StateDraining State = "draining"
+	// StateStopped means the component no longer serves.
+	StateStopped State = "stopped"
+)
+
+// Component is a long-running part of the process.
+type Component interface {
+	State() State
+	// Drain refuses the new requests and waits for the current ones until ctx expires.
+	Drain(ctx context.Context) error
+}
+
+// Session is a client connection held open by a component, e.g. a WebSocket.
+type Session struct {
+	ID        string    `json:"id"`
+	Component string    `json:"component"`
+	Principal string    `json:"principal,omitempty"`
+	Remote    string    `json:"remote"`
+	OpenedAt  time.Time `json:"opened_at"`
+}
+
+// SessionProvider is implemented by the components holding sessions.
+type SessionProvider interface {
+	Sessions() []Session
+	// Disconnect closes the session id and reports whether the component held it.
+	Disconnect(id string) bool
+}
+
+// Queue is the depth of a buffer of pending items.
+type Queue struct {
+	Name     string `json:"name"`
+	Depth    int    `json:"depth"`
+	Capacity int    `json:"capacity"`
+	// Consumers is the number of readers sharing the queue, when it has several.
+	Consumers int `json:"consumers,omitempty"`
+}
+
+// QueueProvider is implemented by the components and adapters buffering items.
+type QueueProvider interface {
+	Queues() []Queue
+}
+
+// Status is the state of a registered component.
+type Status struct {
+	Name  string `json:"name"`
+	State State  `json:"state"`
+	// Sessions is the number of open sessions of the components holding sessions.
+	Sessions *int `json:"sessions,omitempty"`
+}
+
+// entry is a registered component.
+type entry struct {
+	name      string
+	component Component
+}
+
+// Registry keeps the components in registration order. It is safe for concurrent use.
+type Registry struct {
+	mu         sync.RWMutex
+	components []entry
+	queues     []QueueProvider
+}
+
+// NewRegistry creates an empty registry.
+func NewRegistry() *Registry {
+	return &Registry{}
+}
+
+// Register adds a component, replacing a component registered under the same name.
+func (r *Registry) Register(name string, component Component) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i := range r.components {
+		if r.components[i].name == name {
+			r.components[i].component = component
+			return
+		}
+	}
+	r.components = append(r.components, entry{name: name, component: component})
+}
+
+// RegisterQueues adds a provider of queues that is not a component, such as the use cases.
+func (r *Registry) RegisterQueues(provider QueueProvider) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.queues = append(r.queues, provider)
+}
+
+// Components returns the state of the components.
+func (r *Registry) Components() []Status {
+	statuses := []Status{}
+	for _, e := range r.entries() {
+		status := Status{Name: e.name, State: e.component.State()}
+		if provider, ok := e.component.(SessionProvider); ok {
+			count := len(provider.Sessions())
+			status.Sessions = &count
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses
+}
+
+// Drain drains the component name, an error wrapping ErrNotFound when it is unknown.
+func (r *Registry) Drain(ctx context.Context, name string) error {
+	for _, e := range r.entries() {
+		if e.name == name {
+			return e.component.Drain(ctx)
+		}
+	}
+	return fmt.Errorf("component %q: %w", name, ErrNotFound)
+}
+
+// State returns the state of the component name.
+func (r *Registry) State(name string) (State, bool) {
+	for _, e := range r.entries() {
+		if e.name == name {
+			return e.component.State(), true
+		}
+	}
+	return "", false
+}
+
+// Sessions returns the open sessions of every component, with the name of their component.
+func (r *Registry) Sessions() []Session {
+	sessions := []Session{}
+	for _, e := range r.entries() {
+		provider, ok := e.component.(SessionProvider)
+		if !ok {
+			continue
+		}
+		for _, session := range provider.Sessions() {
+			session.Component = e.name
+			sessions = append(sessions, session)
+		}
+	}
+	return sessions
+}
+
+// Disconnect closes the session id, an error wrapping ErrNotFound when no component holds it.
+func (r *Registry) Disconnect(id string) error {
+	for _, e := range r.entries() {
+		if provider, ok := e.component.(SessionProvider); ok && provider.Disconnect(id) {
+			return nil
+		}
+	}
+	return fmt.Errorf("session %q: %w", id, ErrNotFound)
+}
+
+// Queues returns the queues of the components and of the registered providers.
+func (r *Registry) Queues() []Queue {
+	queues := []Queue{}
+	for _, e := range r.entries() {
+		if provider, ok := e.component.(QueueProvider); ok {
+			queues = append(queues, provider.Queues()...)
+		}
+	}
+
+	r.mu.RLock()
+	providers := append([]QueueProvider(nil), r.queues...)
+	r.mu.RUnlock()
+	for _, provider := range providers {
+		queues = append(queues, provider.Queues()...)
+	}
+	return queues
+}
+
+// entries returns a copy of the registered components, so that they are called without the lock.
+func (r *Registry) entries() []entry {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return append([]entry(nil), r.components...)
+}
@@ -0,0 +1,158 @@
+package lifecycle_test
+
+import (
+	"context"
+	"live-semantic/src/lifecycle"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeComponent est un composant dont les sessions sont gardées en mémoire
+type fakeComponent struct {
+	state    lifecycle.State
+	sessions []lifecycle.Session
+}
+
+func (c *fakeComponent) State() lifecycle.State {
+	return c.state
+}
+
+func (c *fakeComponent) Drain(ctx context.Context) error {
+	c.state = lifecycle.StateDraining
+	return nil
+}
+
+func (c *fakeComponent) Sessions() []lifecycle.Session {
+	return c.sessions
+}
+
+func (c *fakeComponent) Disconnect(id string) bool {
+	for i, session := range c.sessions {
+		if session.ID == id {
+			c.sessions = append(c.sessions[:i], c.sessions[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// fakeQueues fournit une file fixe
+type fakeQueues []lifecycle.Queue
+
+func (q fakeQueues) Queues() []lifecycle.Queue {
+	return q
+}
+
+func newRegistry() (*lifecycle.Registry, *fakeComponent) {
+	ws := &fakeComponent{
+		state:    lifecycle.StateRunning,
+		sessions: []lifecycle.Session{{ID: "s1", Remote: "127.0.0.1", OpenedAt: time.Now()}},
+	}
+	registry := lifecycle.NewRegistry()
+	registry.Register("websocket", ws)
+	return registry, ws
+}
+
+func TestRegistry_Components(t *testing.T) {
+	t.Run("should report the state and the session count", func(t *testing.T) {
+		// Given
+		registry, _ := newRegistry()
+
+		// When
+		statuses := registry.Components()
+
+		// Then
+		if assert.Len(t, statuses, 1) {
+			assert.Equal(t, "websocket", statuses[0].Name)
+			assert.Equal(t, lifecycle.StateRunning, statuses[0].State)
+			if assert.NotNil(t, statuses[0].Sessions) {
+				assert.Equal(t, 1, *statuses[0].Sessions)
+			}
+		}
+	})
+
+	t.Run("should replace a component registered under the same name", func(t *testing.T) {
+		// Given
+		registry, _ := newRegistry()
+
+		// When
+		registry.Register("websocket", &fakeComponent{state: lifecycle.StateStopped})
+
+		// Then
+		state, ok := registry.State("websocket")
+		assert.True(t, ok)
+		assert.Equal(t, lifecycle.StateStopped, state)
+		assert.Len(t, registry.Components(), 1)
+	})
+}
+
+func TestRegistry_Drain(t *testing.T) {
+	t.Run("should drain the named component", func(t *testing.T) {
+		// Given
+		registry, ws := newRegistry()
+
+		// When
+		err := registry.Drain(context.Background(), "websocket")
+
+		// Then
+		assert.NoError(t, err)
+		assert.Equal(t, lifecycle.StateDraining, ws.State())
+	})
+
+	t.Run("should reject an unknown component", func(t *testing.T) {
+		// Given
+		registry, _ := newRegistry()
+
+		// When
+		err := registry.Drain(context.Background(), "unknown")
+
+		// Then
+		assert.ErrorIs(t, err, lifecycle.ErrNotFound)
+	})
+}
+
+func TestRegistry_Sessions(t *testing.T) {
+	t.Run("should list the sessions with their component", func(t *testing.T) {
+		// Given
+		registry, _ := newRegistry()
+
+		// When
+		sessions := registry.Sessions()
+
+		// Then
+		if assert.Len(t, sessions, 1) {
+			assert.Equal(t, "s1", sessions[0].ID)
+			assert.Equal(t, "websocket", sessions[0].Component)
+		}
+	})
+
+	t.Run("should disconnect a known session only", func(t *testing.T) {
+		// Given
+		registry, ws := newRegistry()
+
+		// When
+		err := registry.Disconnect("s1")
+		unknown := registry.Disconnect("s1")
+
+		// Then
+		assert.NoError(t, err)
+		assert.Empty(t, ws.Sessions())
+		assert.ErrorIs(t, unknown, lifecycle.ErrNotFound)
+	})
+}
+
+func TestRegistry_Queues(t *testing.T) {
+	t.Run("should gather the queues of the providers", func(t *testing.T) {
+		// Given
+		registry, _ := newRegistry()
+		registry.RegisterQueues(fakeQueues{{Name: "events.history", Depth: 3, Capacity: 256}})
+
+		// When
+		queues := registry.Queues()
+
+		// Then
+		assert.Equal(t, []lifecycle.Queue{{Name: "events.history", Depth: 3, Capacity: 256}}, queues)
+	})
+}
@@ -0,0 +1,254 @@
+// Package logging wraps the application logger with a level adjustable at runtime,
+// globally or per module such as transport.websocket.
+package logging
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// Level is the minimum severity of the logged messages.
+type Level int8
+
+const (
+	// LevelDebug logs everything.
+	LevelDebug Level = iota
+	// LevelInfo logs the information, warnings and errors.
+	LevelInfo
+	// LevelWarn logs the warnings and errors.
+	LevelWarn
+	// LevelError logs the errors only.
+	LevelError
+)
+
+// levelNames maps each level to its configuration name.
+var levelNames = map[Level]string{
+	LevelDebug: "debug",
+	LevelInfo:  "info",
+	LevelWarn:  "warn",
+	LevelError: "error",
+}
+
+// String returns the configuration name of the level.
+func (l Level) String() string {
+	if name, ok := levelNames[l]; ok {
+		return name
+	}
+	return fmt.Sprintf("level(%d)", int8(l))
+}
+
+// ParseLevel parses debug, info, warn or error, case insensitive.
+func ParseLevel(name string) (Level, error) {
+	for level, levelName := range levelNames {
+		if strings.EqualFold(strings.TrimSpace(name), levelName) {
+			return level, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown log level %q (available: debug, info, warn, error)", name)
+}
+
+// Snapshot is the state of the levels as configured and exposed by the admin endpoint.
+type Snapshot struct {
+	// Level applies to the modules without override.
+	Level string `json:"level"`
+	// Modules maps a module, e.g. transport.websocket, to its level.
+	Modules map[string]string `json:"modules,omitempty"`
+}
+
+// Entries returns the module levels as sorted MODULE=LEVEL entries, the form of logging.modules.
+func (s Snapshot) Entries() []string {
+	entries := make([]string, 0, len(s.Modules))
+	for module, level := range s.Modules {
+		entries = append(entries, module+"="+level)
+	}
+	slices.Sort(entries)
+	return entries
+}
+
+// ParseModules parses MODULE=LEVEL entries such as transport.websocket=debug.
+func ParseModules(entries []string) (map[string]string, error) {
+	modules := make(map[string]string, len(entries))
+	for _, entry := range entries {
+		module, name, found := strings.Cut(entry, "=")
+		module = strings.TrimSpace(module)
+		if !found || module == "" {
+			return nil, fmt.Errorf("invalid module level %q, expected MODULE=LEVEL", entry)
+		}
+		level, err := ParseLevel(name)
+		if err != nil {
+			return nil, fmt.Errorf("module %s: %w", module, err)
+		}
+		modules[module] = level.String()
+	}
+	return modules, nil
+}
+
+// levelState is an immutable set of levels, replaced as a whole on each change.
+type levelState struct {
+	level   Level
+	modules map[string]Level
+}
+
+// Levels holds the default level and the per-module overrides.
+// Reads are lock-free, so that every log call can check its level.
+type Levels struct {
+	// mu serializes the writers
+	mu    sync.Mutex
+	state atomic.Pointer[levelState]
+}
+
+// NewLevels creates levels logging at level without override.
+func NewLevels(level Level) *Levels {
+	l := &Levels{}
+	l.state.Store(&levelState{level: level})
+	return l
+}
+
+// Level returns the default level.
+func (l *Levels) Level() Level {
+	return l.state.Load().level
+}
+
+// SetLevel changes the default level, the overrides are kept.
+func (l *Levels) SetLevel(level Level) {
+	l.update(func(s *levelState) {
+		s.level = level
+	})
+}
+
+// SetModuleLevel overrides the level of module and of its submodules.
+func (l *Levels) SetModuleLevel(module string, level Level) {
+	l.update(func(s *levelState) {
+		s.modules[module] = level
+	})
+}
+
+// ResetModule removes the override of module, which follows the default level again.
+func (l *Levels) ResetModule(module string) {
+	l.update(func(s *levelState) {
+		delete(s.modules, module)
+	})
+}
+
+// ModuleLevel returns the level of module: the override of the module or of its
+// closest parent, e.g. transport for transport.websocket, else the default level.
+func (l *Levels) ModuleLevel(module string) Level {
+	state := l.state.Load()
+	for name := module; name != ""; {
+		if level, ok := state.modules[name]; ok {
+			return level
+		}
+		i := strings.LastIndexByte(name, '.')
+		if i < 0 {
+			break
+		}
+		name = name[:i]
+	}
+	return state.level
+}
+
+// Enabled reports whether module logs the messages of level.
+func (l *Levels) Enabled(module string, level Level) bool {
+	return level >= l.ModuleLevel(module)
+}
+
+// Snapshot returns the current levels.
+func (l *Levels) Snapshot() Snapshot {
+	state := l.state.Load()
+	snapshot := Snapshot{Level: state.level.String()}
+	if len(state.modules) > 0 {
+		snapshot.Modules = make(map[string]string, len(state.modules))
+		for module, level := range state.modules {
+			snapshot.Modules[module] = level.String()
+		}
+	}
+	return snapshot
+}
+
+// Apply replaces the default level and every override at once.
+// An invalid snapshot leaves the levels untouched.
+func (l *Levels) Apply(snapshot Snapshot) error {
+	state, err := parseSnapshot(snapshot)
+	if err != nil {
+		return err
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.state.Store(state)
+	return nil
+}
+
+// Update changes the default level unless level is empty and sets the levels of the
+// given modules, an empty module level removes its override. It returns the new levels,
+// an invalid level leaves them untouched.
+func (l *Levels) Update(level string, modules map[string]string) (Snapshot, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	next := l.Snapshot()
+	if level != "" {
+		next.Level = level
+	}
+	if len(modules) > 0 {
+		next.Modules = maps.Clone(next.Modules)
+		if next.Modules == nil {
+			next.Modules = map[string]string{}
+		}
+		for module, name := range modules {
+			if name == "" {
+				delete(next.Modules, module)
+				continue
+			}
+			next.Modules[module] = name
+		}
+	}
+
+	state, err := parseSnapshot(next)
+	if err != nil {
+		return Snapshot{}, err
+	}
+	l.state.Store(state)
+	return l.Snapshot(), nil
+}
+
+// parseSnapshot converts a snapshot to the state it describes.
+func parseSnapshot(snapshot Snapshot) (*levelState, error) {
+	level, err := ParseLevel(snapshot.Level)
+	if err != nil {
+		return nil, err
+	}
+	modules := make(map[string]Level, len(snapshot.Modules))
+	for module, name := range snapshot.Modules {
+		if strings.TrimSpace(module) == "" {
+			return nil, fmt.Errorf("empty module name")
+		}
+		moduleLevel, err := ParseLevel(name)
+		if err != nil {
+			return nil, fmt.Errorf("module %s: %w", module, err)
+		}
+		modules[module] = moduleLevel
+	}
+	return &levelState{level: level, modules: modules}, nil
+}
+
+// update applies change to a copy of the current state and publishes it.
+func (l *Levels) update(change func(*levelState)) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	current := l.state.Load()
+	next := &levelState{level: current.level, modules: maps.Clone(current.modules)}
+	if next.modules == nil {
+		next.modules = map[string]Level{}
+	}
+	change(next)
+	l.state.Store(next)
+}
+
+// Persister saves the levels so that they survive a restart.
+type Persister func(Snapshot) error
@@ -0,0 +1,109 @@
+package logging
+
+import (
+	"github.com/deadelus/go-clean-app/src/logger"
+)
+
+// ModuleField is the field naming the module of a message.
+const ModuleField = "module"
+
+// Logger is a logger.Logger dropping the messages below the level of its module
+// and redacting the fields of the others.
+// The base logger must let every level through, see NewZapLogger.
+type Logger struct {
+	base     logger.Logger
+	levels   *Levels
+	redactor *Redactor
+	module   string
+}
+
+// Option configures the logger.
+type Option func(*Logger)
+
+// WithRedactor redacts the fields of every message, including those of the named loggers.
+func WithRedactor(redactor *Redactor) Option {
+	return func(l *Logger) {
+		l.redactor = redactor
+	}
+}
+
+// New wraps base with levels, the messages of the root logger follow the default level.
+func New(base logger.Logger, levels *Levels, options ...Option) *Logger {
+	l := &Logger{base: base, levels: levels}
+	for _, option := range options {
+		option(l)
+	}
+	return l
+}
+
+// Named returns the logger of module, nested under the module of l:
+// Named("websocket") on the transport logger logs as transport.websocket.
+func (l *Logger) Named(module string) *Logger {
+	if l.module != "" {
+		module = l.module + "." + module
+	}
+	return &Logger{base: l.base, levels: l.levels, redactor: l.redactor, module: module}
+}
+
+// Module returns the module of the logger, empty for the root logger.
+func (l *Logger) Module() string {
+	return l.module
+}
+
+// Levels returns the levels shared by the logger and its named loggers.
+func (l *Logger) Levels() *Levels {
+	return l.levels
+}
+
+// Debug implements logger.Logger.
+func (l *Logger) Debug(msg string, fields ...any) {
+	if l.levels.Enabled(l.module, LevelDebug) {
+		l.base.Debug(msg, l.with(fields)...)
+	}
+}
+
+// Info implements logger.Logger.
+func (l *Logger) Info(msg string, fields ...any) {
+	if l.levels.Enabled(l.module, LevelInfo) {
+		l.base.Info(msg, l.with(fields)...)
+	}
+}
+
+// Warn implements logger.Logger.
+func (l *Logger) Warn(msg string, fields ...any) {
+	if l.levels.Enabled(l.module, LevelWarn) {
+		l.base.Warn(msg, l.with(fields)...)
+	}
+}
+
+// Error implements logger.Logger.
+func (l *Logger) Error(msg string, fields ...any) {
+	if l.levels.Enabled(l.module, LevelError) {
+		l.base.Error(msg, l.with(fields)...)
+	}
+}
+
+// Close implements logger.Logger, it closes the base logger.
+func (l *Logger) Close() {
+	l.base.Close()
+}
+
+// with redacts the fields of a message and appends the module field.
+func (l *Logger) with(fields []any) []any {
+	fields = l.redactor.Fields(fields)
+	if l.module == "" {
+		return fields
+	}
+	return append(fields[:len(fields):len(fields)], map[string]interface{}{ModuleField: l.module})
+}
+
+// Discard is a logger.Logger dropping every message, for the components whose logs are not wanted, such as in tests.
+var Discard logger.Logger = discard{}
+
+type discard struct{}
+
+func (discard) Debug(msg string, fields ...any) {}
+func (discard) Info(msg string, fields ...any)  {}
+func (discard) Warn(msg string, fields ...any)  {}
+func (discard) Error(msg string, fields ...any) {}
+func (discard) Close()                          {}
@@ -0,0 +1,246 @@
+package logging_test
+
+import (
+	"live-semantic/src/logging"
+	"testing"
+
+	"github.com/deadelus/go-clean-app/src/application"
+	"github.com/deadelus/go-clean-app/src/logger"
+	"github.com/deadelus/go-clean-app/src/logger/zaplogger"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLevels(t *testing.T) {
+	t.Run("should resolve the override of the closest parent module", func(t *testing.T) {
+		// Given
+		levels := logging.NewLevels(logging.LevelWarn)
+		levels.SetModuleLevel("transport", logging.LevelInfo)
+		levels.SetModuleLevel("transport.websocket", logging.LevelDebug)
+
+		// Then
+		assert.Equal(t, logging.LevelDebug, levels.ModuleLevel("transport.websocket"))
+		assert.Equal(t, logging.LevelDebug, levels.ModuleLevel("transport.websocket.session"))
+		assert.Equal(t, logging.LevelInfo, levels.ModuleLevel("transport.api"))
+		assert.Equal(t, logging.LevelWarn, levels.ModuleLevel("domain.uc"))
+		assert.Equal(t, logging.LevelWarn, levels.ModuleLevel("transports"))
+	})
+
+	t.Run("should apply a snapshot and reject an invalid one as a whole", func(t *testing.T) {
+		// Given
+		levels := logging.NewLevels(logging.LevelInfo)
+
+		// When
+		err := levels.Apply(logging.Snapshot{Level: "error", Modules: map[string]string{"domain.uc": "DEBUG"}})
+		invalidErr := levels.Apply(logging.Snapshot{Level: "debug", Modules: map[string]string{"domain.uc": "loud"}})
+
+		// Then
+		assert.NoError(t, err)
+		assert.Error(t, invalidErr)
+		assert.Equal(t, logging.Snapshot{Level: "error", Modules: map[string]string{"domain.uc": "debug"}}, levels.Snapshot())
+		assert.Equal(t, []string{"domain.uc=debug"}, levels.Snapshot().Entries())
+	})
+}
+
+func TestLevels_Update(t *testing.T) {
+	t.Run("should keep the omitted settings and remove the emptied overrides", func(t *testing.T) {
+		// Given
+		levels := logging.NewLevels(logging.LevelInfo)
+		levels.SetModuleLevel("domain.uc", logging.LevelDebug)
+		levels.SetModuleLevel("transport.api", logging.LevelWarn)
+
+		// When
+		snapshot, err := levels.Update("", map[string]string{"domain.uc": "", "transport.websocket": "debug"})
+
+		// Then
+		assert.NoError(t, err)
+		assert.Equal(t, logging.Snapshot{Level: "info", Modules: map[string]string{
+			"transport.api":       "warn",
+			"transport.websocket": "debug",
+		}}, snapshot)
+	})
+
+	t.Run("should leave the levels untouched on an invalid level", func(t *testing.T) {
+		// Given
+		levels := logging.NewLevels(logging.LevelInfo)
+
+		// When
+		_, err := levels.Update("verbose", map[string]string{"domain.uc": "debug"})
+
+		// Then
+		assert.Error(t, err)
+		assert.Equal(t, logging.Snapshot{Level: "info"}, levels.Snapshot())
+	})
+}
+
+func TestParseModules(t *testing.T) {
+	t.Run("should parse MODULE=LEVEL entries", func(t *testing.T) {
+		// When
+		modules, err := logging.ParseModules([]string{"transport.websocket=debug", " domain.uc = Warn"})
+
+		// Then
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]string{"transport.websocket": "debug", "domain.uc": "warn"}, modules)
+	})
+
+	t.Run("should reject a malformed entry", func(t *testing.T) {
+		for _, entry := range []string{"transport.websocket", "=debug", "transport=loud"} {
+			// When
+			_, err := logging.ParseModules([]string{entry})
+
+			// Then
+			assert.Error(t, err, entry)
+		}
+	})
+}
+
+func TestLogger(t *testing.T) {
+	t.Run("should drop the messages below the module level and name the module", func(t *testing.T) {
+		// Given
+		ctrl := gomock.NewController(t)
+		base := logger.NewMockLogger(ctrl)
+		levels := logging.NewLevels(logging.LevelInfo)
+		levels.SetModuleLevel("transport.websocket", logging.LevelDebug)
+		root := logging.New(base, levels)
+		ws := root.Named("transport").Named("websocket")
+
+		base.EXPECT().Debug("frame", map[string]interface{}{"size": 3}, map[string]interface{}{logging.ModuleField: "transport.websocket"})
+		base.EXPECT().Info("started")
+
+		// When
+		root.Debug("dropped")
+		root.Info("started")
+		ws.Debug("frame", map[string]interface{}{"size": 3})
+
+		// Then
+		assert.Equal(t, "transport.websocket", ws.Module())
+	})
+
+	t.Run("should follow a level changed at runtime", func(t *testing.T) {
+		// Given
+		ctrl := gomock.NewController(t)
+		base := logger.NewMockLogger(ctrl)
+		levels := logging.NewLevels(logging.LevelInfo)
+		log := logging.New(base, levels).Named("domain.uc")
+
+		base.EXPECT().Warn("kept", map[string]interface{}{logging.ModuleField: "domain.uc"})
+
+		// When
+		levels.SetLevel(logging.LevelError)
+		log.Info("dropped")
+		levels.SetModuleLevel("domain", logging.LevelWarn)
+		log.Warn("kept")
+	})
+}
+
+func TestRedactor(t *testing.T) {
+	patterns, err := logging.ParsePatterns([]string{"email", "phone", "token", `\bINV-\d+\b`})
+	if !assert.NoError(t, err) {
+		return
+	}
+	redactor := logging.NewRedactor(
+		logging.WithFields("title", "Description"),
+		logging.WithHashKey([]byte("0123456789abcdef0123456789abcdef")),
+		logging.WithPatterns(patterns...),
+		logging.WithMaxLength(24),
+	)
+
+	t.Run("should hash the sensitive fields at any depth, structs included", func(t *testing.T) {
+		// Given
+		type request struct {
+			Title  string `json:"title"`
+			Source string `json:"source"`
+		}
+
+		// When
+		fields := redactor.Fields([]any{map[string]interface{}{
+			"description": "Jane's flat",
+			"request":     request{Title: "Jane Doe", Source: "cam0"},
+			"count":       3,
+		}})
+
+		// Then
+		assert.Equal(t, []any{map[string]any{
+			"description": redactor.Hash("Jane's flat"),
+			"request":     map[string]any{"title": redactor.Hash("Jane Doe"), "source": "cam0"},
+			"count":       3,
+		}}, fields)
+	})
+
+	t.Run("should hash alike with the same key only", func(t *testing.T) {
+		// Given
+		same := logging.NewRedactor(logging.WithHashKey([]byte("0123456789abcdef0123456789abcdef")))
+		rotated := logging.NewRedactor(logging.WithHashKey([]byte("fedcba9876543210fedcba9876543210")))
+		random := logging.NewRedactor()
+
+		// When
+		hash := redactor.Hash("Jane Doe")
+
+		// Then
+		assert.Regexp(t, `^hmac:[0-9a-f]{16}$`, hash)
+		assert.Equal(t, hash, same.Hash("Jane Doe"))
+		assert.NotEqual(t, hash, rotated.Hash("Jane Doe"))
+		assert.NotEqual(t, hash, random.Hash("Jane Doe"))
+		assert.NotEqual(t, hash, redactor.Hash("John Doe"))
+	})
+
+	t.Run("should mask the patterns and truncate the long values", func(t *testing.T) {
+		// When
+		fields := redactor.Fields([]any{map[string]interface{}{
+			"email":   "contact: jane.doe@example.com",
+			"phone":   "+33 6 12 34 56 78",
+			"auth":    "Bearer lst_abc.def",
+			"invoice": "INV-42",
+			"date":    "2026-10-19",
+			"notes":   "a note far longer than the limit",
+		}})
+
+		// Then
+		assert.Equal(t, []any{map[string]any{
+			"email":   "contact: [email]",
+			"phone":   "[phone]",
+			"auth":    "[token]",
+			"invoice": "[redacted]",
+			"date":    "2026-10-19",
+			"notes":   "a note far longer than t…(8 more)",
+		}}, fields)
+	})
+
+	t.Run("should reject an invalid pattern", func(t *testing.T) {
+		// When
+		_, err := logging.ParsePatterns([]string{"("})
+
+		// Then
+		assert.Error(t, err)
+	})
+
+	t.Run("should redact the fields logged through the logger", func(t *testing.T) {
+		// Given
+		ctrl := gomock.NewController(t)
+		base := logger.NewMockLogger(ctrl)
+		log := logging.New(base, logging.NewLevels(logging.LevelInfo), logging.WithRedactor(redactor))
+
+		base.EXPECT().Info("Handling Task request", map[string]any{"title": redactor.Hash("Jane Doe"), "source": "cli"})
+
+		// When
+		log.Info("Handling Task request", map[string]interface{}{"title": "Jane Doe", "source": "cli"})
+	})
+}
+
+func TestNewEngine(t *testing.T) {
+	t.Run("should give the engine the zap logger of the package", func(t *testing.T) {
+		// Given
+		t.Setenv(application.AppNameEnvName, "live-semantic-test")
+
+		// When
+		engine, err := logging.NewEngine(application.AppNameEnvName, application.SetOptionVersion("1.2.3"))
+
+		// Then
+		if !assert.NoError(t, err) {
+			return
+		}
+		assert.Equal(t, "live-semantic-test", engine.Name())
+		assert.Equal(t, "1.2.3", engine.Version())
+		assert.IsType(t, &zaplogger.ZapLogger{}, engine.Logger())
+	})
+}
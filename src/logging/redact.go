@@ -0,0 +1,231 @@
+package logging
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"go.uber.org/zap"
+)
+
+// Built-in patterns, referenced by name in logging.redaction.patterns.
+var builtinPatterns = map[string]Pattern{
+	"email": {
+		Name: "email",
+		re:   regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`),
+	},
+	// International numbers, French ten-digit numbers and US (555) 123-4567 numbers,
+	// dates and plain IDs are left alone
+	"phone": {
+		Name: "phone",
+		re:   regexp.MustCompile(`\+\d{1,3}(?:[\s.-]?\d{1,4}){3,6}|\b0\d(?:[\s.-]?\d{2}){4}\b|\(\d{3}\)\s?\d{3}-\d{4}`),
+	},
+	// Bearer credentials, the API keys and tokens of this application and JWTs
+	"token": {
+		Name: "token",
+		re:   regexp.MustCompile(`(?i)\bbearer\s+[A-Za-z0-9._~+/=-]+|\bls[kt]_[A-Za-z0-9_.-]+|\beyJ[A-Za-z0-9_-]+\.[A-Za-z0-9_-]+\.[A-Za-z0-9_-]*`),
+	},
+}
+
+// BuiltinPatterns lists the names of the built-in patterns.
+func BuiltinPatterns() []string {
+	names := make([]string, 0, len(builtinPatterns))
+	for name := range builtinPatterns {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// Pattern is a regular expression masked in the string values.
+type Pattern struct {
+	// Name replaces the matches as [name].
+	Name string
+	re   *regexp.Regexp
+}
+
+// ParsePatterns resolves the built-in pattern names and compiles the other entries
+// as regular expressions, whose matches are replaced by [redacted].
+func ParsePatterns(entries []string) ([]Pattern, error) {
+	patterns := make([]Pattern, 0, len(entries))
+	for _, entry := range entries {
+		if pattern, ok := builtinPatterns[entry]; ok {
+			patterns = append(patterns, pattern)
+			continue
+		}
+		re, err := regexp.Compile(entry)
+		if err != nil {
+			return nil, fmt.Errorf("pattern %q: %w", entry, err)
+		}
+		patterns = append(patterns, Pattern{Name: "redacted", re: re})
+	}
+	return patterns, nil
+}
+
+// MinHashKeyLength is the shortest key accepted for the hashes of the sensitive fields.
+const MinHashKeyLength = 32
+
+// Redactor masks the personal data of the log fields before they reach the base logger:
+// the values of the sensitive fields are replaced by a short keyed hash, which still tells
+// equal values apart, the patterns are masked in every string and long strings are truncated.
+// A nil redactor leaves the fields untouched.
+type Redactor struct {
+	fields    map[string]bool
+	hashKey   []byte
+	patterns  []Pattern
+	maxLength int
+}
+
+// RedactOption configures the redactor.
+type RedactOption func(*Redactor)
+
+// WithFields hashes the values of the fields named names, at any depth, case insensitive.
+func WithFields(names ...string) RedactOption {
+	return func(r *Redactor) {
+		for _, name := range names {
+			r.fields[strings.ToLower(name)] = true
+		}
+	}
+}
+
+// WithHashKey sets the HMAC-SHA256 key of the hashes of the sensitive fields. Without the key,
+// a logged hash cannot be matched by hashing candidate values such as names or phone numbers.
+// Processes sharing the key produce the same hashes; without key, NewRedactor draws a random
+// one and the hashes only correlate within the process.
+func WithHashKey(key []byte) RedactOption {
+	return func(r *Redactor) {
+		r.hashKey = key
+	}
+}
+
+// WithPatterns masks the matches of the patterns in every string value.
+func WithPatterns(patterns ...Pattern) RedactOption {
+	return func(r *Redactor) {
+		r.patterns = append(r.patterns, patterns...)
+	}
+}
+
+// WithMaxLength truncates the string values longer than length runes, 0 keeps them whole.
+func WithMaxLength(length int) RedactOption {
+	return func(r *Redactor) {
+		r.maxLength = length
+	}
+}
+
+// NewRedactor creates a redactor.
+func NewRedactor(options ...RedactOption) *Redactor {
+	r := &Redactor{fields: map[string]bool{}}
+	for _, option := range options {
+		option(r)
+	}
+	if len(r.hashKey) == 0 {
+		r.hashKey = make([]byte, MinHashKeyLength)
+		_, _ = rand.Read(r.hashKey)
+	}
+	return r
+}
+
+// Fields returns a redacted copy of the fields of a message: maps are redacted key by key,
+// structs through their JSON form and errors through their message. The caller's values
+// are never modified.
+func (r *Redactor) Fields(fields []any) []any {
+	if r == nil || len(fields) == 0 {
+		return fields
+	}
+	redacted := make([]any, len(fields))
+	for i, field := range fields {
+		redacted[i] = r.value(field)
+	}
+	return redacted
+}
+
+// Hash returns the short keyed hash replacing the value of a sensitive field.
+func (r *Redactor) Hash(value any) string {
+	var data []byte
+	switch v := value.(type) {
+	case string:
+		data = []byte(v)
+	default:
+		data, _ = json.Marshal(v)
+	}
+	mac := hmac.New(sha256.New, r.hashKey)
+	mac.Write(data)
+	return "hmac:" + hex.EncodeToString(mac.Sum(nil)[:8])
+}
+
+// value redacts a field value.
+func (r *Redactor) value(value any) any {
+	switch v := value.(type) {
+	case zap.Field:
+		return v
+	case nil, bool, int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64:
+		return v
+	case string:
+		return r.text(v)
+	case error:
+		return r.text(v.Error())
+	case map[string]any:
+		out := make(map[string]any, len(v))
+		for key, item := range v {
+			out[key] = r.entry(key, item)
+		}
+		return out
+	case []any:
+		out := make([]any, len(v))
+		for i, item := range v {
+			out[i] = r.value(item)
+		}
+		return out
+	case []string:
+		out := make([]string, len(v))
+		for i, item := range v {
+			out[i] = r.text(item)
+		}
+		return out
+	case fmt.Stringer:
+		return r.text(v.String())
+	}
+
+	switch reflect.Indirect(reflect.ValueOf(value)).Kind() {
+	case reflect.Struct, reflect.Map, reflect.Slice, reflect.Array:
+		// Go through the JSON form, so that the fields are named as in the payloads
+		data, err := json.Marshal(value)
+		if err != nil {
+			return r.text(fmt.Sprint(value))
+		}
+		var decoded any
+		if err := json.Unmarshal(data, &decoded); err != nil {
+			return r.text(string(data))
+		}
+		return r.value(decoded)
+	}
+	return value
+}
+
+// entry redacts the value of the key of a map.
+func (r *Redactor) entry(key string, value any) any {
+	if r.fields[strings.ToLower(key)] && value != nil && value != "" {
+		return r.Hash(value)
+	}
+	return r.value(value)
+}
+
+// text masks the patterns of a string and truncates it.
+func (r *Redactor) text(s string) string {
+	for _, pattern := range r.patterns {
+		s = pattern.re.ReplaceAllString(s, "["+pattern.Name+"]")
+	}
+	if r.maxLength > 0 && utf8.RuneCountInString(s) > r.maxLength {
+		runes := []rune(s)
+		s = fmt.Sprintf("%s…(%d more)", string(runes[:r.maxLength]), len(runes)-r.maxLength)
+	}
+	return s
+}
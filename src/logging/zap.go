@@ -0,0 +1,72 @@
+package logging
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+
+	"github.com/deadelus/go-clean-app/src/application"
+	"github.com/deadelus/go-clean-app/src/logger"
+	"github.com/deadelus/go-clean-app/src/logger/zaplogger"
+	"go.uber.org/zap"
+)
+
+// NewZapLogger builds the same development or production logger as application.NewZapLogger,
+// chosen by the loggerModeEnvName variable, but lets the debug messages through so that
+// Levels alone decides what is logged.
+func NewZapLogger(appName, appVersion, loggerModeEnvName string) (*zaplogger.ZapLogger, zaplogger.Gracefull, error) {
+	config := zap.NewDevelopmentConfig()
+	options := []zap.Option{zap.AddStacktrace(zap.PanicLevel), zap.WithCaller(false)}
+	switch os.Getenv(loggerModeEnvName) {
+	case "production", "prod":
+		config = zap.NewProductionConfig()
+		options = options[:1]
+	}
+	config.Level = zap.NewAtomicLevelAt(zap.DebugLevel)
+
+	l, err := config.Build(options...)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create zap Logger: %w", err)
+	}
+	l = l.Named(appName).With(
+		zap.String("app_version", appVersion),
+		zap.String("go_version", runtime.Version()))
+
+	return zaplogger.GetFromExternalLogger(l)
+}
+
+// Engine is an application engine whose logger is built by NewZapLogger.
+type Engine struct {
+	*application.Engine
+	logger logger.Logger
+}
+
+// NewEngine creates the application engine with the logger of NewZapLogger.
+// The engine only builds its own logger through the application.NewZapLogger test hook,
+// so the logger is created by a constructor option and returned by the Engine instead.
+func NewEngine(appNameEnvName string, version application.VersionOption, options ...application.Option) (*Engine, error) {
+	engine := &Engine{}
+	options = append(options, func(e *application.Engine) error {
+		l, closeLogger, err := NewZapLogger(e.Name(), e.Version(), application.LoggerModeEnvName)
+		if err != nil {
+			return err
+		}
+		engine.logger = l
+		if err := e.Gracefull().Register("zaplogger", closeLogger); err != nil {
+			return fmt.Errorf("failed to register zap logger for graceful shutdown: %w", err)
+		}
+		return nil
+	})
+
+	base, err := application.New(appNameEnvName, version, options...)
+	if err != nil {
+		return nil, err
+	}
+	engine.Engine = base
+	return engine, nil
+}
+
+// Logger returns the logger built by NewZapLogger.
+func (e *Engine) Logger() logger.Logger {
+	return e.logger
+}
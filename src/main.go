@@ -2,133 +2,83 @@ package main
 
 import (
 	"fmt"
+	"live-semantic/src/client"
+	"live-semantic/src/config"
 	"live-semantic/src/domain/uc"
-	"live-semantic/src/transport/api"
-	"live-semantic/src/transport/cli"
+	"live-semantic/src/logging"
 	"live-semantic/src/transport/cmd"
-	"live-semantic/src/transport/websocket"
-	"os"
 
 	"github.com/deadelus/go-clean-app/src/application"
-	"github.com/spf13/pflag"
-)
-
-const (
-	defaultWebPort       = 8080
-	defaultWebsocketPort = 8081
 )
 
 func main() {
-	// Define and parse flags first to determine the mode
-	web := pflag.BoolP("web", "s", false, "Start the web server (API mode)")
-	ws := pflag.BoolP("websocket", "w", false, "Start the WebSocket server")
-	interactive := pflag.BoolP("interactive", "i", false, "Start in interactive mode")
-	port := pflag.IntP("port", "p", 0, "Port to use for the server")
-	pflag.Parse()
+	cmd.Execute(bootstrap)
+}
 
-	// Build application options
-	var options = []application.Option{}
+// bootstrap creates the engine and the use cases once cobra knows the chosen subcommand
+func bootstrap(mode cmd.Mode, cfg *config.Config) (application.Application, uc.UseCases, error) {
+	var engine application.Application
+	var err error
 
-	isCliMode := !*web && !*ws && !*interactive
-	if isCliMode {
+	if mode == cmd.ModeCLI {
 		// Use a console-friendly logger for CLI mode
-		options = append(options, application.SetZapLoggerForCLI(), application.WithCLIMode())
+		engine, err = application.New(
+			application.AppNameEnvName,
+			application.SetVersionFromEnv(),
+			application.SetZapLoggerForCLI(),
+			application.WithCLIMode(),
+		)
 	} else {
-		// Use a web-friendly logger for web or websocket mode
-		options = append(options, application.SetZapLogger())
+		// Use a web-friendly logger for server and interactive modes, logging.level
+		// decides what is logged so that it can be lowered to debug at runtime
+		engine, err = logging.NewEngine(
+			application.AppNameEnvName,
+			application.SetVersionFromEnv(),
+		)
 	}
-
-	// Create the engine with the appropriate options
-	engine, err := application.New(
-		application.AppNameEnvName,
-		application.SetVersionFromEnv(),
-		options...,
-	)
 	if err != nil {
-		fmt.Println("Error creating application:", err)
-		return
+		return nil, nil, fmt.Errorf("error creating application: %w", err)
 	}
 
-	engine.Logger().Info(
+	log := cmd.NewLogger(engine.Logger())
+	log.Info(
 		"Application started",
 		map[string]interface{}{
 			"appName":    engine.Name(),
 			"appVersion": engine.Version(),
+			"mode":       string(mode),
+			"profile":    cfg.Profile,
 		},
 	)
 
-	useCases, err := uc.NewUseCase(engine.Logger())
-	if err != nil {
-		engine.Logger().Error("Failed to create use cases", err)
-		return
-	}
-
-	engine.Logger().Info("✅ Use cases initialized")
+	// CLI commands talk to a running server when a remote URL is configured
+	if cfg.Remote.URL != "" && mode != cmd.ModeServer {
+		remote, err := client.New(cfg.Remote.URL, client.WithToken(cfg.Remote.Token), client.WithTimeout(cfg.Remote.Timeout))
+		if err != nil {
+			return nil, nil, fmt.Errorf("%w: %v", config.ErrInvalid, err)
+		}
 
-	// Decide which mode to start based on flags
-	switch {
-	case *web:
-		serverPort := determinePort(*port, defaultWebPort)
-		startWebServer(engine, useCases, serverPort)
-	case *ws:
-		serverPort := determinePort(*port, defaultWebsocketPort)
-		startWebsocketServer(engine, useCases, serverPort)
-	case *interactive:
-		startInteractiveMode(engine, useCases)
-	default:
-		startCLIMode(engine, useCases)
+		log.Info("✅ Remote use cases initialized", map[string]interface{}{
+			"remote": remote.BaseURL(),
+		})
+		return engine, client.NewUseCases(remote), nil
 	}
-}
 
-func determinePort(flagPort, defaultPort int) int {
-	if flagPort != 0 {
-		return flagPort
+	var ucOptions []uc.Option
+	if cfg.Video.Source != "" {
+		ucOptions = append(ucOptions, uc.WithVideoSources(cfg.Video.Source))
 	}
-	return defaultPort
-}
-
-// startInteractiveMode starts the interactive mode
-func startInteractiveMode(engine *application.Engine, useCases uc.UseCases) {
-	engine.Logger().Info("💡 Starting in interactive mode")
-	controller := cli.NewSurveyController(useCases, engine.Logger())
-	if err := controller.Run(); err != nil {
-		engine.Logger().Error("Interactive CLI failed", err)
-		os.Exit(1)
+	if auditor := cmd.Auditor(); auditor != nil {
+		ucOptions = append(ucOptions, uc.WithAuditor(auditor))
 	}
-}
-
-// startCLIMode starts the CLI mode
-func startCLIMode(engine *application.Engine, useCases uc.UseCases) {
-	engine.Logger().Info("💻 Starting in CLI mode")
-	cmd.Execute(useCases, engine.Logger())
-}
-
-// startWebServer starts the web server in API mode
-func startWebServer(engine *application.Engine, useCases uc.UseCases, port int) {
-	engine.Logger().Info("🌐 Starting in Web API mode", map[string]interface{}{
-		"port": port,
-	})
 
-	server := api.NewServer(useCases, engine.Logger(), port)
-	if err := server.Start(); err != nil {
-		engine.Logger().Error("Web server failed", map[string]interface{}{
-			"error": err.Error(),
-		})
-		os.Exit(1)
+	useCases, err := uc.NewUseCase(log.Named("domain.uc"), ucOptions...)
+	if err != nil {
+		log.Error("Failed to create use cases", err)
+		return nil, nil, fmt.Errorf("failed to create use cases: %w", err)
 	}
-}
 
-// startWebsocketServer starts the WebSocket server
-func startWebsocketServer(engine *application.Engine, useCases uc.UseCases, port int) {
-	engine.Logger().Info("🔗 Starting in WebSocket mode", map[string]interface{}{
-		"port": port,
-	})
+	log.Info("✅ Use cases initialized")
 
-	server := websocket.NewServer(useCases, engine.Logger(), port)
-	if err := server.Start(); err != nil {
-		engine.Logger().Error("WebSocket server failed", map[string]interface{}{
-			"error": err.Error(),
-		})
-		os.Exit(1)
-	}
+	return engine, useCases, nil
 }
@@ -0,0 +1,55 @@
+package metrics
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"text/tabwriter"
+	"time"
+)
+
+// ConsoleReporter prints a summary of the registry for the CLI commands.
+type ConsoleReporter struct {
+	registry *Registry
+	out      io.Writer
+}
+
+// NewConsoleReporter creates a reporter writing to out, usually stderr.
+func NewConsoleReporter(registry *Registry, out io.Writer) *ConsoleReporter {
+	return &ConsoleReporter{registry: registry, out: out}
+}
+
+// Report prints one line per series: the value of the counters and gauges,
+// the count and the mean of the histograms.
+func (c *ConsoleReporter) Report() error {
+	samples := c.registry.Snapshot()
+	if len(samples) == 0 {
+		return nil
+	}
+
+	w := tabwriter.NewWriter(c.out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "METRIC\tLABELS\tVALUE")
+	for _, s := range samples {
+		value := formatValue(s.Value)
+		if s.Kind == KindHistogram {
+			value = fmt.Sprintf("count=%d mean=%s", s.Count, time.Duration(s.Value*float64(time.Second)))
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\n", s.Name, s.Labels, value)
+	}
+	return w.Flush()
+}
+
+// Run prints a report every interval until the context ends.
+func (c *ConsoleReporter) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			_ = c.Report()
+		case <-ctx.Done():
+			return
+		}
+	}
+}
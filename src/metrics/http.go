@@ -0,0 +1,44 @@
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Metrics recorded by the HTTP middleware.
+const (
+	HTTPRequests        = "http_requests_total"
+	HTTPRequestDuration = "http_request_duration_seconds"
+)
+
+// unmatchedRoute labels the requests matching no route, so that unknown paths
+// do not create a series each.
+const unmatchedRoute = "unmatched"
+
+// Middleware counts the requests by method, route and status and observes their duration.
+// Routes are labeled with their pattern, e.g. /api/v1/tasks, never with the raw path.
+func Middleware(collector Collector) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			route = unmatchedRoute
+		}
+		method := Label{Name: "method", Value: c.Request.Method}
+		path := Label{Name: "route", Value: route}
+
+		collector.RecordCounter(HTTPRequests, 1, method, path, Label{Name: "status", Value: strconv.Itoa(c.Writer.Status())})
+		collector.RecordLatency(HTTPRequestDuration, time.Since(start), method, path)
+	}
+}
+
+// Handler serves the metrics of the collector when it can expose them, nil otherwise.
+func Handler(collector Collector) http.Handler {
+	handler, _ := collector.(http.Handler)
+	return handler
+}
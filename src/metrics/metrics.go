@@ -0,0 +1,35 @@
+// Package metrics provides the MetricsCollector port and an in-process registry
+// exposed in the Prometheus text format.
+package metrics
+
+import (
+	"time"
+)
+
+// Label is a name and value pair distinguishing the series of a metric.
+type Label struct {
+	Name  string
+	Value string
+}
+
+// Collector is the MetricsCollector port: the transports and the adapters record
+// their measures through it without knowing the backend.
+// Metric names are given in full, e.g. http_requests_total or usecase_duration_seconds.
+type Collector interface {
+	// RecordLatency observes a duration in the histogram named operation.
+	RecordLatency(operation string, duration time.Duration, labels ...Label)
+	// RecordCounter adds value to the counter named metric.
+	RecordCounter(metric string, value int64, labels ...Label)
+	// RecordGauge sets the gauge named metric to value.
+	RecordGauge(metric string, value float64, labels ...Label)
+}
+
+// Discard is a Collector dropping every measure, used when no collector is configured.
+var Discard Collector = discard{}
+
+// discard drops every measure.
+type discard struct{}
+
+func (discard) RecordLatency(string, time.Duration, ...Label) {}
+func (discard) RecordCounter(string, int64, ...Label)         {}
+func (discard) RecordGauge(string, float64, ...Label)         {}
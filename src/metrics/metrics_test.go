@@ -0,0 +1,105 @@
+package metrics_test
+
+import (
+	"bytes"
+	"live-semantic/src/metrics"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegistry_WriteText(t *testing.T) {
+	t.Run("should expose counters, gauges and histograms in the Prometheus text format", func(t *testing.T) {
+		// Given
+		registry := metrics.NewRegistry(metrics.WithNamespace("app"), metrics.WithBuckets(0.1, 1))
+		registry.Describe("jobs_total", "Jobs run.")
+		registry.RecordCounter("jobs_total", 2, metrics.Label{Name: "queue", Value: "b"})
+		registry.RecordCounter("jobs_total", 1, metrics.Label{Name: "queue", Value: "a"})
+		registry.RecordCounter("jobs_total", 1, metrics.Label{Name: "queue", Value: "a"})
+		registry.RecordGauge("workers", 3)
+		registry.RecordGauge("workers", 4)
+		registry.RecordLatency("job_duration_seconds", 50*time.Millisecond)
+		registry.RecordLatency("job_duration_seconds", 2*time.Second)
+		registry.GaugeFunc("queue_depth", func() float64 { return 7 }, metrics.Label{Name: "queue", Value: `say "hi"`})
+
+		// When
+		var out bytes.Buffer
+		err := registry.WriteText(&out)
+
+		// Then
+		assert.NoError(t, err)
+		assert.Equal(t, `# TYPE app_job_duration_seconds histogram
+app_job_duration_seconds_bucket{le="0.1"} 1
+app_job_duration_seconds_bucket{le="1"} 1
+app_job_duration_seconds_bucket{le="+Inf"} 2
+app_job_duration_seconds_sum 2.05
+app_job_duration_seconds_count 2
+# HELP app_jobs_total Jobs run.
+# TYPE app_jobs_total counter
+app_jobs_total{queue="a"} 2
+app_jobs_total{queue="b"} 2
+# TYPE app_queue_depth gauge
+app_queue_depth{queue="say \"hi\""} 7
+# TYPE app_workers gauge
+app_workers 4
+`, out.String())
+	})
+
+	t.Run("should keep the first kind recorded for a name", func(t *testing.T) {
+		// Given
+		registry := metrics.NewRegistry()
+		registry.RecordCounter("requests", 1)
+
+		// When
+		registry.RecordGauge("requests", 10)
+
+		// Then
+		assert.Equal(t, []metrics.Sample{{Name: "requests", Kind: metrics.KindCounter, Value: 1}}, registry.Snapshot())
+	})
+}
+
+func TestMiddleware(t *testing.T) {
+	t.Run("should label the requests with their route pattern and serve the registry", func(t *testing.T) {
+		// Given
+		gin.SetMode(gin.TestMode)
+		registry := metrics.NewRegistry()
+		router := gin.New()
+		router.Use(metrics.Middleware(registry))
+		router.GET("/tasks/:id", func(c *gin.Context) { c.Status(http.StatusOK) })
+		router.GET("/metrics", gin.WrapH(metrics.Handler(registry)))
+
+		// When
+		for _, path := range []string{"/tasks/1", "/tasks/2", "/unknown"} {
+			router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+		}
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+		// Then
+		assert.Equal(t, metrics.ContentType, rec.Header().Get("Content-Type"))
+		assert.Contains(t, rec.Body.String(), `http_requests_total{method="GET",route="/tasks/:id",status="200"} 2`)
+		assert.Contains(t, rec.Body.String(), `http_requests_total{method="GET",route="unmatched",status="404"} 1`)
+		assert.Nil(t, metrics.Handler(metrics.Discard))
+	})
+}
+
+func TestConsoleReporter_Report(t *testing.T) {
+	t.Run("should print the count and the mean of the histograms", func(t *testing.T) {
+		// Given
+		registry := metrics.NewRegistry()
+		registry.RecordLatency("usecase_duration_seconds", 10*time.Millisecond, metrics.Label{Name: "operation", Value: "task.create"})
+		registry.RecordLatency("usecase_duration_seconds", 30*time.Millisecond, metrics.Label{Name: "operation", Value: "task.create"})
+		var out bytes.Buffer
+
+		// When
+		err := metrics.NewConsoleReporter(registry, &out).Report()
+
+		// Then
+		assert.NoError(t, err)
+		assert.Contains(t, out.String(), `usecase_duration_seconds  {operation="task.create"}  count=2 mean=20ms`)
+	})
+}
@@ -0,0 +1,324 @@
+package metrics
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Metric kinds, as written in the TYPE lines of the exposition format.
+const (
+	KindCounter   = "counter"
+	KindGauge     = "gauge"
+	KindHistogram = "histogram"
+)
+
+// DefaultBuckets are the upper bounds in seconds of the latency histograms.
+var DefaultBuckets = []float64{0.001, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// series is the state of one label set of a metric.
+type series struct {
+	labels []Label
+	value  float64
+	counts []uint64
+	count  uint64
+	sum    float64
+}
+
+// valueFunc is a series computed when the metrics are collected.
+type valueFunc struct {
+	labels []Label
+	fn     func() float64
+}
+
+// family groups the series of a metric.
+type family struct {
+	name   string
+	kind   string
+	help   string
+	series map[string]*series
+	funcs  []valueFunc
+}
+
+// Registry is an in-process Collector keeping counters, gauges and histograms in memory.
+// A name keeps the kind it was first recorded with, measures of another kind are ignored.
+type Registry struct {
+	mu        sync.Mutex
+	namespace string
+	buckets   []float64
+	families  map[string]*family
+}
+
+// Option configures a Registry.
+type Option func(*Registry)
+
+// WithNamespace prefixes every metric name, e.g. livesemantic_http_requests_total.
+func WithNamespace(namespace string) Option {
+	return func(r *Registry) {
+		r.namespace = namespace
+	}
+}
+
+// WithBuckets sets the upper bounds in seconds of the latency histograms.
+func WithBuckets(buckets ...float64) Option {
+	return func(r *Registry) {
+		r.buckets = append([]float64(nil), buckets...)
+		sort.Float64s(r.buckets)
+	}
+}
+
+// NewRegistry creates an empty registry.
+func NewRegistry(options ...Option) *Registry {
+	r := &Registry{
+		buckets:  DefaultBuckets,
+		families: map[string]*family{},
+	}
+	for _, option := range options {
+		option(r)
+	}
+	return r
+}
+
+// RecordLatency implements Collector.
+func (r *Registry) RecordLatency(operation string, duration time.Duration, labels ...Label) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	s := r.series(operation, KindHistogram, labels)
+	if s == nil {
+		return
+	}
+	if s.counts == nil {
+		s.counts = make([]uint64, len(r.buckets))
+	}
+
+	seconds := duration.Seconds()
+	for i, bound := range r.buckets {
+		if seconds <= bound {
+			s.counts[i]++
+		}
+	}
+	s.count++
+	s.sum += seconds
+}
+
+// RecordCounter implements Collector, negative values are ignored.
+func (r *Registry) RecordCounter(metric string, value int64, labels ...Label) {
+	if value < 0 {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if s := r.series(metric, KindCounter, labels); s != nil {
+		s.value += float64(value)
+	}
+}
+
+// RecordGauge implements Collector.
+func (r *Registry) RecordGauge(metric string, value float64, labels ...Label) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if s := r.series(metric, KindGauge, labels); s != nil {
+		s.value = value
+	}
+}
+
+// GaugeFunc registers a gauge computed by fn each time the metrics are collected.
+func (r *Registry) GaugeFunc(metric string, fn func() float64, labels ...Label) {
+	r.registerFunc(metric, KindGauge, fn, labels)
+}
+
+// CounterFunc registers a counter read from fn each time the metrics are collected,
+// for the components that already count on their own.
+func (r *Registry) CounterFunc(metric string, fn func() float64, labels ...Label) {
+	r.registerFunc(metric, KindCounter, fn, labels)
+}
+
+// Describe sets the help text of a metric.
+func (r *Registry) Describe(metric, help string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	name := r.name(metric)
+	if f, ok := r.families[name]; ok {
+		f.help = help
+		return
+	}
+	r.families[name] = &family{name: name, help: help, series: map[string]*series{}}
+}
+
+// registerFunc adds a computed series to a metric.
+func (r *Registry) registerFunc(metric, kind string, fn func() float64, labels []Label) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if f := r.family(metric, kind); f != nil {
+		f.funcs = append(f.funcs, valueFunc{labels: sortLabels(labels), fn: fn})
+	}
+}
+
+// family returns the family of a metric, nil when the name has another kind, r.mu must be held.
+func (r *Registry) family(metric, kind string) *family {
+	name := r.name(metric)
+	f, ok := r.families[name]
+	if !ok {
+		f = &family{name: name, series: map[string]*series{}}
+		r.families[name] = f
+	}
+	// A family created by Describe takes the kind of its first measure
+	if f.kind == "" {
+		f.kind = kind
+	}
+	if f.kind != kind {
+		return nil
+	}
+	return f
+}
+
+// series returns the series of a label set, r.mu must be held.
+func (r *Registry) series(metric, kind string, labels []Label) *series {
+	f := r.family(metric, kind)
+	if f == nil {
+		return nil
+	}
+
+	labels = sortLabels(labels)
+	key := labelKey(labels)
+	s, ok := f.series[key]
+	if !ok {
+		s = &series{labels: labels}
+		f.series[key] = s
+	}
+	return s
+}
+
+// name returns the sanitized metric name with the namespace.
+func (r *Registry) name(metric string) string {
+	if r.namespace != "" {
+		metric = r.namespace + "_" + metric
+	}
+	return sanitize(metric)
+}
+
+// sortLabels returns sanitized labels sorted by name.
+func sortLabels(labels []Label) []Label {
+	sorted := make([]Label, len(labels))
+	for i, l := range labels {
+		sorted[i] = Label{Name: sanitize(l.Name), Value: l.Value}
+	}
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Name < sorted[j].Name })
+	return sorted
+}
+
+// labelKey identifies a sorted label set.
+func labelKey(labels []Label) string {
+	var b strings.Builder
+	for _, l := range labels {
+		b.WriteString(l.Name)
+		b.WriteByte(0)
+		b.WriteString(l.Value)
+		b.WriteByte(0)
+	}
+	return b.String()
+}
+
+// sanitize replaces the characters not allowed in a metric or label name.
+func sanitize(name string) string {
+	return strings.Map(func(c rune) rune {
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c == '_', c == ':':
+			return c
+		case c >= '0' && c <= '9':
+			return c
+		default:
+			return '_'
+		}
+	}, name)
+}
+
+// Sample is the current value of a series, as reported by Snapshot.
+type Sample struct {
+	Name   string  `json:"name"`
+	Kind   string  `json:"kind"`
+	Labels string  `json:"labels,omitempty"`
+	Value  float64 `json:"value"`
+	// Count and Sum are set for the histograms, Value is then the mean.
+	Count uint64  `json:"count,omitempty"`
+	Sum   float64 `json:"sum,omitempty"`
+}
+
+// Snapshot returns the current value of every series, sorted by name and labels.
+func (r *Registry) Snapshot() []Sample {
+	var samples []Sample
+	for _, f := range r.collect() {
+		for _, s := range f.series {
+			sample := Sample{Name: f.name, Kind: f.kind, Labels: formatLabels(s.labels), Value: s.value}
+			if f.kind == KindHistogram {
+				sample.Count, sample.Sum = s.count, s.sum
+				sample.Value = 0
+				if s.count > 0 {
+					sample.Value = s.sum / float64(s.count)
+				}
+			}
+			samples = append(samples, sample)
+		}
+	}
+	return samples
+}
+
+// collected is a copy of a family taken under the lock.
+type collected struct {
+	name    string
+	kind    string
+	help    string
+	buckets []float64
+	series  []series
+}
+
+// collect copies the families sorted by name and their series sorted by labels,
+// then evaluates the computed series outside the lock.
+func (r *Registry) collect() []collected {
+	r.mu.Lock()
+	families := make([]collected, 0, len(r.families))
+	funcs := make([][]valueFunc, 0, len(r.families))
+	for _, f := range r.families {
+		if f.kind == "" {
+			continue
+		}
+		c := collected{name: f.name, kind: f.kind, help: f.help, buckets: r.buckets}
+		keys := make([]string, 0, len(f.series))
+		for key := range f.series {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			s := *f.series[key]
+			s.counts = append([]uint64(nil), s.counts...)
+			c.series = append(c.series, s)
+		}
+		families = append(families, c)
+		funcs = append(funcs, append([]valueFunc(nil), f.funcs...))
+	}
+	r.mu.Unlock()
+
+	for i := range families {
+		for _, vf := range funcs[i] {
+			value := vf.fn()
+			if math.IsNaN(value) {
+				continue
+			}
+			families[i].series = append(families[i].series, series{labels: vf.labels, value: value})
+		}
+		if len(funcs[i]) > 0 {
+			s := families[i].series
+			sort.SliceStable(s, func(a, b int) bool { return labelKey(s[a].labels) < labelKey(s[b].labels) })
+		}
+	}
+	sort.Slice(families, func(i, j int) bool { return families[i].name < families[j].name })
+	return families
+}
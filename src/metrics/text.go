@@ -0,0 +1,94 @@
+package metrics
+
+import (
+	"bufio"
+	"io"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// ContentType is the media type of the Prometheus text exposition format.
+const ContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// WriteText writes every metric in the Prometheus text exposition format.
+func (r *Registry) WriteText(w io.Writer) error {
+	out := bufio.NewWriter(w)
+	for _, f := range r.collect() {
+		if len(f.series) == 0 {
+			continue
+		}
+		if f.help != "" {
+			out.WriteString("# HELP " + f.name + " " + escapeHelp(f.help) + "\n")
+		}
+		out.WriteString("# TYPE " + f.name + " " + f.kind + "\n")
+
+		for _, s := range f.series {
+			if f.kind != KindHistogram {
+				out.WriteString(f.name + formatLabels(s.labels) + " " + formatValue(s.value) + "\n")
+				continue
+			}
+
+			for i, bound := range f.buckets {
+				var count uint64
+				if i < len(s.counts) {
+					count = s.counts[i]
+				}
+				le := Label{Name: "le", Value: formatValue(bound)}
+				out.WriteString(f.name + "_bucket" + formatLabels(append(s.labels[:len(s.labels):len(s.labels)], le)) + " " + strconv.FormatUint(count, 10) + "\n")
+			}
+			inf := Label{Name: "le", Value: "+Inf"}
+			out.WriteString(f.name + "_bucket" + formatLabels(append(s.labels[:len(s.labels):len(s.labels)], inf)) + " " + strconv.FormatUint(s.count, 10) + "\n")
+			out.WriteString(f.name + "_sum" + formatLabels(s.labels) + " " + formatValue(s.sum) + "\n")
+			out.WriteString(f.name + "_count" + formatLabels(s.labels) + " " + strconv.FormatUint(s.count, 10) + "\n")
+		}
+	}
+	return out.Flush()
+}
+
+// ServeHTTP serves the metrics in the Prometheus text exposition format.
+func (r *Registry) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	w.Header().Set("Content-Type", ContentType)
+	_ = r.WriteText(w)
+}
+
+// formatLabels writes a label set as {name="value",...}, empty without labels.
+func formatLabels(labels []Label) string {
+	if len(labels) == 0 {
+		return ""
+	}
+
+	var b strings.Builder
+	b.WriteByte('{')
+	for i, l := range labels {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(l.Name + `="` + escapeValue(l.Value) + `"`)
+	}
+	b.WriteByte('}')
+	return b.String()
+}
+
+// formatValue writes a sample value.
+func formatValue(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	default:
+		return strconv.FormatFloat(v, 'g', -1, 64)
+	}
+}
+
+// escapeValue escapes a label value.
+func escapeValue(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(value)
+}
+
+// escapeHelp escapes a help text.
+func escapeHelp(help string) string {
+	return strings.NewReplacer(`\`, `\\`, "\n", `\n`).Replace(help)
+}
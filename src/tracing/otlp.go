@@ -0,0 +1,175 @@
+package tracing
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+)
+
+// ScopeName is the instrumentation scope of the exported spans.
+const ScopeName = "live-semantic"
+
+// OTLPWriter exports each batch as one line of JSON, an OTLP ExportTraceServiceRequest
+// as written by the OpenTelemetry Collector file exporter, so that the file can be
+// replayed into any OTLP backend.
+type OTLPWriter struct {
+	mu       sync.Mutex
+	w        io.Writer
+	closer   io.Closer
+	resource []otlpKeyValue
+}
+
+// NewOTLPWriter exports to w, e.g. os.Stdout, the spans of the service named service.
+func NewOTLPWriter(w io.Writer, service string) *OTLPWriter {
+	return &OTLPWriter{
+		w:        w,
+		resource: []otlpKeyValue{keyValue(Attr("service.name", service))},
+	}
+}
+
+// OpenOTLPFile exports to the file at path, created with its directory and appended to.
+// Shutdown closes the file.
+func OpenOTLPFile(path, service string) (*OTLPWriter, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, fmt.Errorf("create trace directory: %w", err)
+	}
+	// The spans may carry user names
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("open trace file: %w", err)
+	}
+
+	writer := NewOTLPWriter(file, service)
+	writer.closer = file
+	return writer, nil
+}
+
+// Export implements Exporter.
+func (e *OTLPWriter) Export(spans []SpanData) error {
+	request := otlpRequest{ResourceSpans: []otlpResourceSpans{{
+		Resource:   otlpResource{Attributes: e.resource},
+		ScopeSpans: []otlpScopeSpans{{Scope: otlpScope{Name: ScopeName}, Spans: make([]otlpSpan, 0, len(spans))}},
+	}}}
+	scope := &request.ResourceSpans[0].ScopeSpans[0]
+	for _, span := range spans {
+		scope.Spans = append(scope.Spans, encodeSpan(span))
+	}
+
+	line, err := json.Marshal(request)
+	if err != nil {
+		return fmt.Errorf("encode spans: %w", err)
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if _, err := e.w.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("write spans: %w", err)
+	}
+	return nil
+}
+
+// Shutdown implements Exporter, it closes the file opened by OpenOTLPFile.
+func (e *OTLPWriter) Shutdown() error {
+	if e.closer == nil {
+		return nil
+	}
+	return e.closer.Close()
+}
+
+// OTLP JSON encoding: IDs in hex, 64-bit integers as strings and enums as numbers.
+type (
+	otlpRequest struct {
+		ResourceSpans []otlpResourceSpans `json:"resourceSpans"`
+	}
+	otlpResourceSpans struct {
+		Resource   otlpResource     `json:"resource"`
+		ScopeSpans []otlpScopeSpans `json:"scopeSpans"`
+	}
+	otlpResource struct {
+		Attributes []otlpKeyValue `json:"attributes"`
+	}
+	otlpScopeSpans struct {
+		Scope otlpScope  `json:"scope"`
+		Spans []otlpSpan `json:"spans"`
+	}
+	otlpScope struct {
+		Name string `json:"name"`
+	}
+	otlpSpan struct {
+		TraceID           string         `json:"traceId"`
+		SpanID            string         `json:"spanId"`
+		ParentSpanID      string         `json:"parentSpanId,omitempty"`
+		Name              string         `json:"name"`
+		Kind              Kind           `json:"kind"`
+		StartTimeUnixNano string         `json:"startTimeUnixNano"`
+		EndTimeUnixNano   string         `json:"endTimeUnixNano"`
+		Attributes        []otlpKeyValue `json:"attributes,omitempty"`
+		Status            otlpStatus     `json:"status"`
+	}
+	otlpStatus struct {
+		Code    StatusCode `json:"code,omitempty"`
+		Message string     `json:"message,omitempty"`
+	}
+	otlpKeyValue struct {
+		Key   string       `json:"key"`
+		Value otlpAnyValue `json:"value"`
+	}
+	otlpAnyValue struct {
+		StringValue *string  `json:"stringValue,omitempty"`
+		BoolValue   *bool    `json:"boolValue,omitempty"`
+		IntValue    string   `json:"intValue,omitempty"`
+		DoubleValue *float64 `json:"doubleValue,omitempty"`
+	}
+)
+
+// encodeSpan converts an ended span to its OTLP form.
+func encodeSpan(span SpanData) otlpSpan {
+	encoded := otlpSpan{
+		TraceID:           span.TraceID.String(),
+		SpanID:            span.SpanID.String(),
+		Name:              span.Name,
+		Kind:              span.Kind,
+		StartTimeUnixNano: strconv.FormatInt(span.Start.UnixNano(), 10),
+		EndTimeUnixNano:   strconv.FormatInt(span.End.UnixNano(), 10),
+		Status:            otlpStatus{Code: span.Status, Message: span.StatusMessage},
+	}
+	if span.ParentSpanID.IsValid() {
+		encoded.ParentSpanID = span.ParentSpanID.String()
+	}
+	for _, attribute := range span.Attributes {
+		encoded.Attributes = append(encoded.Attributes, keyValue(attribute))
+	}
+	return encoded
+}
+
+// keyValue converts an attribute to its OTLP form.
+func keyValue(attribute Attribute) otlpKeyValue {
+	var value otlpAnyValue
+	switch v := attribute.Value.(type) {
+	case string:
+		value.StringValue = &v
+	case bool:
+		value.BoolValue = &v
+	case int:
+		value.IntValue = strconv.FormatInt(int64(v), 10)
+	case int64:
+		value.IntValue = strconv.FormatInt(v, 10)
+	case int32:
+		value.IntValue = strconv.FormatInt(int64(v), 10)
+	case uint64:
+		value.IntValue = strconv.FormatUint(v, 10)
+	case float64:
+		value.DoubleValue = &v
+	case float32:
+		f := float64(v)
+		value.DoubleValue = &f
+	default:
+		s := fmt.Sprint(v)
+		value.StringValue = &s
+	}
+	return otlpKeyValue{Key: attribute.Key, Value: value}
+}
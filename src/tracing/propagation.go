@@ -0,0 +1,79 @@
+package tracing
+
+import (
+	"context"
+	"encoding/hex"
+	"net/http"
+	"strings"
+)
+
+// TraceparentHeader is the W3C Trace Context header continuing a trace across processes.
+const TraceparentHeader = "traceparent"
+
+// spanContext identifies the parent of a new span.
+type spanContext struct {
+	traceID TraceID
+	spanID  SpanID
+	sampled bool
+}
+
+// remoteKey is the context key of the parent extracted from the request headers.
+type remoteKey struct{}
+
+// Extract returns a copy of ctx continuing the trace of the traceparent header, so that the
+// next Tracer.Start creates a child of the remote span. An absent or invalid header is ignored.
+func Extract(ctx context.Context, header http.Header) context.Context {
+	parent, ok := parseTraceparent(header.Get(TraceparentHeader))
+	if !ok {
+		return ctx
+	}
+	return context.WithValue(ctx, remoteKey{}, parent)
+}
+
+// Inject sets the traceparent header of the current span of ctx, if any.
+func Inject(ctx context.Context, header http.Header) {
+	if span := SpanFrom(ctx); span != nil {
+		header.Set(TraceparentHeader, span.Traceparent())
+	}
+}
+
+// Traceparent returns the traceparent header value identifying the span, empty for a nil span.
+func (s *Span) Traceparent() string {
+	if s == nil {
+		return ""
+	}
+	flags := "00"
+	if s.sampled {
+		flags = "01"
+	}
+	return "00-" + s.data.TraceID.String() + "-" + s.data.SpanID.String() + "-" + flags
+}
+
+// parseTraceparent parses version-traceid-parentid-flags, the fields added by a later
+// version are ignored as required by the specification.
+func parseTraceparent(value string) (spanContext, bool) {
+	parts := strings.Split(strings.TrimSpace(value), "-")
+	if len(parts) < 4 || len(parts[0]) != 2 || parts[0] == "ff" || (parts[0] == "00" && len(parts) != 4) {
+		return spanContext{}, false
+	}
+	if _, err := hex.DecodeString(parts[0]); err != nil {
+		return spanContext{}, false
+	}
+
+	var parent spanContext
+	if len(parts[1]) != 32 || len(parts[2]) != 16 || len(parts[3]) != 2 {
+		return spanContext{}, false
+	}
+	if _, err := hex.Decode(parent.traceID[:], []byte(parts[1])); err != nil || !parent.traceID.IsValid() {
+		return spanContext{}, false
+	}
+	if _, err := hex.Decode(parent.spanID[:], []byte(parts[2])); err != nil || !parent.spanID.IsValid() {
+		return spanContext{}, false
+	}
+	flags, err := hex.DecodeString(parts[3])
+	if err != nil {
+		return spanContext{}, false
+	}
+	parent.sampled = flags[0]&1 == 1
+	return parent, true
+}
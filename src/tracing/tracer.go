@@ -0,0 +1,220 @@
+package tracing
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/binary"
+	"sync"
+	"time"
+)
+
+// DefaultBatchSize is the number of ended spans exported together at most.
+const DefaultBatchSize = 256
+
+// Exporter writes the ended spans to a backend.
+type Exporter interface {
+	// Export writes a batch of ended spans.
+	Export(spans []SpanData) error
+	// Shutdown releases the backend once the last batch is exported.
+	Shutdown() error
+}
+
+// Tracer starts the traces of the transports, samples them and batches their spans
+// to the exporter. A nil tracer traces nothing.
+type Tracer struct {
+	exporter  Exporter
+	ratio     float64
+	batchSize int
+	now       func() time.Time
+	onError   func(error)
+	mu        sync.Mutex
+	pending   []SpanData
+}
+
+// Option configures the tracer.
+type Option func(*Tracer)
+
+// WithExporter sets the exporter of the sampled spans, the spans are dropped without exporter.
+func WithExporter(exporter Exporter) Option {
+	return func(t *Tracer) {
+		t.exporter = exporter
+	}
+}
+
+// WithSampleRatio sets the share of the new traces recorded, between 0 and 1 (default 1).
+// A trace continued from a remote parent follows the parent's decision.
+func WithSampleRatio(ratio float64) Option {
+	return func(t *Tracer) {
+		t.ratio = ratio
+	}
+}
+
+// WithBatchSize sets the number of ended spans exported together at most.
+func WithBatchSize(size int) Option {
+	return func(t *Tracer) {
+		if size > 0 {
+			t.batchSize = size
+		}
+	}
+}
+
+// WithClock replaces the clock timing the spans, used by the tests.
+func WithClock(now func() time.Time) Option {
+	return func(t *Tracer) {
+		t.now = now
+	}
+}
+
+// WithErrorHandler receives the export errors, which are ignored by default.
+func WithErrorHandler(handler func(error)) Option {
+	return func(t *Tracer) {
+		t.onError = handler
+	}
+}
+
+// New creates a tracer.
+func New(options ...Option) *Tracer {
+	t := &Tracer{
+		ratio:     1,
+		batchSize: DefaultBatchSize,
+		now:       time.Now,
+		onError:   func(error) {},
+	}
+	for _, option := range options {
+		option(t)
+	}
+	return t
+}
+
+// Start starts a span, child of the current span of ctx or of the remote parent
+// extracted from the request headers, or the root of a new sampled or dropped trace.
+// The returned context carries the span.
+func (t *Tracer) Start(ctx context.Context, name string, options ...SpanOption) (context.Context, *Span) {
+	if t == nil {
+		return Start(ctx, name, options...)
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	cfg := spanConfig{kind: KindInternal}
+	for _, option := range options {
+		option(&cfg)
+	}
+
+	var parent spanContext
+	local := false
+	if !cfg.root {
+		if span := SpanFrom(ctx); span != nil {
+			parent = spanContext{traceID: span.data.TraceID, spanID: span.data.SpanID, sampled: span.sampled}
+			local = true
+		} else if remote, ok := ctx.Value(remoteKey{}).(spanContext); ok {
+			parent = remote
+		}
+	}
+	if !parent.traceID.IsValid() {
+		parent = spanContext{traceID: newTraceID(), sampled: t.sample()}
+	}
+
+	span := &Span{
+		tracer:  t,
+		sampled: parent.sampled,
+		root:    !local,
+		data: SpanData{
+			TraceID:      parent.traceID,
+			SpanID:       newSpanID(),
+			ParentSpanID: parent.spanID,
+			Name:         name,
+			Kind:         cfg.kind,
+			Start:        t.now(),
+			Attributes:   cfg.attributes,
+		},
+	}
+	return ContextWithSpan(ctx, span), span
+}
+
+// Flush exports the ended spans not exported yet.
+func (t *Tracer) Flush() error {
+	if t == nil {
+		return nil
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.export()
+}
+
+// Shutdown exports the remaining spans and shuts the exporter down.
+// The spans still running are not exported.
+func (t *Tracer) Shutdown() error {
+	if t == nil {
+		return nil
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	err := t.export()
+	if t.exporter != nil {
+		if shutdownErr := t.exporter.Shutdown(); err == nil {
+			err = shutdownErr
+		}
+	}
+	return err
+}
+
+// finish queues an ended span, the end of a root span or a full batch triggers the export
+// so that a trace is usually written in one batch.
+func (t *Tracer) finish(data SpanData, root bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.pending = append(t.pending, data)
+	if root || len(t.pending) >= t.batchSize {
+		if err := t.export(); err != nil {
+			t.onError(err)
+		}
+	}
+}
+
+// export writes the pending spans, the caller holds the lock.
+func (t *Tracer) export() error {
+	if len(t.pending) == 0 {
+		return nil
+	}
+	batch := t.pending
+	t.pending = nil
+	if t.exporter == nil {
+		return nil
+	}
+	return t.exporter.Export(batch)
+}
+
+// sample decides whether a new trace is recorded.
+func (t *Tracer) sample() bool {
+	switch {
+	case t.ratio >= 1:
+		return true
+	case t.ratio <= 0:
+		return false
+	}
+	var b [8]byte
+	_, _ = rand.Read(b[:])
+	return float64(binary.BigEndian.Uint64(b[:])>>11)/(1<<53) < t.ratio
+}
+
+// newTraceID returns a random trace ID.
+func newTraceID() TraceID {
+	var id TraceID
+	for !id.IsValid() {
+		_, _ = rand.Read(id[:])
+	}
+	return id
+}
+
+// newSpanID returns a random span ID.
+func newSpanID() SpanID {
+	var id SpanID
+	for !id.IsValid() {
+		_, _ = rand.Read(id[:])
+	}
+	return id
+}
@@ -0,0 +1,230 @@
+// Package tracing provides lightweight spans carried by the context and their
+// export in the OTLP JSON format.
+package tracing
+
+import (
+	"context"
+	"encoding/hex"
+	"sync"
+	"time"
+)
+
+// TraceID identifies the spans of one trace.
+type TraceID [16]byte
+
+// String returns the lowercase hex form of the ID.
+func (id TraceID) String() string {
+	return hex.EncodeToString(id[:])
+}
+
+// IsValid reports a non-zero ID.
+func (id TraceID) IsValid() bool {
+	return id != TraceID{}
+}
+
+// SpanID identifies a span within its trace.
+type SpanID [8]byte
+
+// String returns the lowercase hex form of the ID.
+func (id SpanID) String() string {
+	return hex.EncodeToString(id[:])
+}
+
+// IsValid reports a non-zero ID.
+func (id SpanID) IsValid() bool {
+	return id != SpanID{}
+}
+
+// Kind is the role of a span, numbered as in OTLP.
+type Kind int
+
+const (
+	// KindInternal is an operation inside the process.
+	KindInternal Kind = 1
+	// KindServer is a request received by a transport.
+	KindServer Kind = 2
+	// KindClient is a request sent to a remote server.
+	KindClient Kind = 3
+)
+
+// StatusCode is the outcome of a span, numbered as in OTLP.
+type StatusCode int
+
+const (
+	// StatusUnset is the status of a span that did not fail.
+	StatusUnset StatusCode = 0
+	// StatusError is the status of a failed span.
+	StatusError StatusCode = 2
+)
+
+// Attribute is a key and value pair describing a span.
+// Values are exported as strings, booleans, integers or floats, other types are formatted.
+type Attribute struct {
+	Key   string
+	Value any
+}
+
+// Attr returns an attribute.
+func Attr(key string, value any) Attribute {
+	return Attribute{Key: key, Value: value}
+}
+
+// SpanData is an ended span handed to the exporter.
+type SpanData struct {
+	TraceID       TraceID
+	SpanID        SpanID
+	ParentSpanID  SpanID
+	Name          string
+	Kind          Kind
+	Start         time.Time
+	End           time.Time
+	Attributes    []Attribute
+	Status        StatusCode
+	StatusMessage string
+}
+
+// Span is an operation being timed. A nil span is valid and records nothing, so callers
+// never check whether tracing is enabled.
+type Span struct {
+	tracer  *Tracer
+	sampled bool
+	// root marks the first span of the trace in this process, its end flushes the batch
+	root  bool
+	mu    sync.Mutex
+	ended bool
+	data  SpanData
+}
+
+// SetAttributes adds attributes to the span.
+func (s *Span) SetAttributes(attributes ...Attribute) {
+	if s == nil || !s.sampled {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.ended {
+		s.data.Attributes = append(s.data.Attributes, attributes...)
+	}
+}
+
+// Fail marks the span as failed with message.
+func (s *Span) Fail(message string) {
+	if s == nil || !s.sampled {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.ended {
+		s.data.Status = StatusError
+		s.data.StatusMessage = message
+	}
+}
+
+// SetError marks the span as failed when err is not nil.
+func (s *Span) SetError(err error) {
+	if err != nil {
+		s.Fail(err.Error())
+	}
+}
+
+// End stops the span and hands it to the exporter when its trace is sampled.
+// Only the first call has an effect.
+func (s *Span) End() {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	if s.ended {
+		s.mu.Unlock()
+		return
+	}
+	s.ended = true
+	s.data.End = s.tracer.now()
+	data := s.data
+	s.mu.Unlock()
+
+	if s.sampled {
+		s.tracer.finish(data, s.root)
+	}
+}
+
+// TraceID returns the ID of the trace of the span, zero for a nil span.
+func (s *Span) TraceID() TraceID {
+	if s == nil {
+		return TraceID{}
+	}
+	return s.data.TraceID
+}
+
+// SpanID returns the ID of the span, zero for a nil span.
+func (s *Span) SpanID() SpanID {
+	if s == nil {
+		return SpanID{}
+	}
+	return s.data.SpanID
+}
+
+// Sampled reports whether the span is recorded and exported.
+func (s *Span) Sampled() bool {
+	return s != nil && s.sampled
+}
+
+// spanKey is the context key of the current span.
+type spanKey struct{}
+
+// ContextWithSpan returns a copy of ctx carrying span as the current span.
+func ContextWithSpan(ctx context.Context, span *Span) context.Context {
+	return context.WithValue(ctx, spanKey{}, span)
+}
+
+// SpanFrom returns the current span of ctx, nil without span.
+func SpanFrom(ctx context.Context) *Span {
+	if ctx == nil {
+		return nil
+	}
+	span, _ := ctx.Value(spanKey{}).(*Span)
+	return span
+}
+
+// SpanOption configures a span.
+type SpanOption func(*spanConfig)
+
+// spanConfig holds the settings of a new span.
+type spanConfig struct {
+	kind       Kind
+	attributes []Attribute
+	root       bool
+}
+
+// WithKind sets the kind of the span, KindInternal by default.
+func WithKind(kind Kind) SpanOption {
+	return func(c *spanConfig) {
+		c.kind = kind
+	}
+}
+
+// WithAttributes sets the initial attributes of the span.
+func WithAttributes(attributes ...Attribute) SpanOption {
+	return func(c *spanConfig) {
+		c.attributes = append(c.attributes, attributes...)
+	}
+}
+
+// NewRoot starts a new trace even when ctx carries a span, e.g. for each message of a
+// long-lived WebSocket session.
+func NewRoot() SpanOption {
+	return func(c *spanConfig) {
+		c.root = true
+	}
+}
+
+// Start starts a child of the current span of ctx with the same tracer.
+// Without current span nothing is traced: it returns ctx and a nil span.
+// The use cases and the adapters use it, the transports start the traces with Tracer.Start.
+func Start(ctx context.Context, name string, options ...SpanOption) (context.Context, *Span) {
+	parent := SpanFrom(ctx)
+	if parent == nil {
+		return ctx, nil
+	}
+	return parent.tracer.Start(ctx, name, options...)
+}
@@ -0,0 +1,158 @@
+package tracing_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"live-semantic/src/tracing"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// recorder keeps the exported batches
+type recorder struct {
+	batches [][]tracing.SpanData
+}
+
+func (r *recorder) Export(spans []tracing.SpanData) error {
+	r.batches = append(r.batches, spans)
+	return nil
+}
+
+func (r *recorder) Shutdown() error { return nil }
+
+func TestTracer_Start(t *testing.T) {
+	t.Run("should export a trace in one batch when its root span ends", func(t *testing.T) {
+		// Given
+		exporter := &recorder{}
+		tracer := tracing.New(tracing.WithExporter(exporter))
+		ctx, root := tracer.Start(context.Background(), "root", tracing.WithKind(tracing.KindServer))
+		childCtx, child := tracing.Start(ctx, "child", tracing.WithAttributes(tracing.Attr("task.id", "1")))
+		_, grandchild := tracing.Start(childCtx, "grandchild")
+
+		// When
+		grandchild.End()
+		child.SetError(errors.New("boom"))
+		child.End()
+		assert.Empty(t, exporter.batches)
+		root.End()
+
+		// Then
+		if assert.Len(t, exporter.batches, 1) && assert.Len(t, exporter.batches[0], 3) {
+			spans := exporter.batches[0]
+			assert.Equal(t, []string{"grandchild", "child", "root"}, []string{spans[0].Name, spans[1].Name, spans[2].Name})
+			assert.Equal(t, root.TraceID(), spans[0].TraceID)
+			assert.Equal(t, root.TraceID(), spans[1].TraceID)
+			assert.Equal(t, child.SpanID(), spans[0].ParentSpanID)
+			assert.Equal(t, root.SpanID(), spans[1].ParentSpanID)
+			assert.False(t, spans[2].ParentSpanID.IsValid())
+			assert.Equal(t, tracing.StatusError, spans[1].Status)
+			assert.Equal(t, "boom", spans[1].StatusMessage)
+			assert.Equal(t, []tracing.Attribute{{Key: "task.id", Value: "1"}}, spans[1].Attributes)
+			assert.Equal(t, tracing.KindServer, spans[2].Kind)
+		}
+	})
+
+	t.Run("should trace nothing without a span in the context", func(t *testing.T) {
+		// Given
+		ctx := context.Background()
+
+		// When
+		got, span := tracing.Start(ctx, "orphan")
+		span.SetAttributes(tracing.Attr("ignored", true))
+		span.End()
+
+		// Then
+		assert.Nil(t, span)
+		assert.Equal(t, ctx, got)
+	})
+
+	t.Run("should drop the unsampled traces and follow the sampled remote parent", func(t *testing.T) {
+		// Given
+		exporter := &recorder{}
+		tracer := tracing.New(tracing.WithExporter(exporter), tracing.WithSampleRatio(0))
+		header := http.Header{}
+		header.Set(tracing.TraceparentHeader, "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+
+		// When
+		ctx, dropped := tracer.Start(context.Background(), "dropped")
+		_, child := tracing.Start(ctx, "dropped child")
+		child.End()
+		dropped.End()
+		_, continued := tracer.Start(tracing.Extract(context.Background(), header), "continued")
+		continued.End()
+
+		// Then
+		assert.False(t, child.Sampled())
+		if assert.Len(t, exporter.batches, 1) {
+			span := exporter.batches[0][0]
+			assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", span.TraceID.String())
+			assert.Equal(t, "00f067aa0ba902b7", span.ParentSpanID.String())
+		}
+		assert.Equal(t, "00-4bf92f3577b34da6a3ce929d0e0e4736-"+continued.SpanID().String()+"-01", continued.Traceparent())
+	})
+}
+
+func TestOTLPWriter_Export(t *testing.T) {
+	t.Run("should write one OTLP JSON request per batch", func(t *testing.T) {
+		// Given
+		var out bytes.Buffer
+		start := time.Unix(1700000000, 0)
+		clock := start
+		tracer := tracing.New(
+			tracing.WithExporter(tracing.NewOTLPWriter(&out, "svc")),
+			tracing.WithClock(func() time.Time { clock = clock.Add(time.Millisecond); return clock }),
+		)
+
+		// When
+		ctx, root := tracer.Start(context.Background(), "GET /tasks", tracing.WithKind(tracing.KindServer))
+		_, child := tracing.Start(ctx, "uc.ListTasks", tracing.WithAttributes(tracing.Attr("count", 2), tracing.Attr("cached", false)))
+		child.End()
+		root.End()
+
+		// Then
+		var request struct {
+			ResourceSpans []struct {
+				Resource struct {
+					Attributes []map[string]any `json:"attributes"`
+				} `json:"resource"`
+				ScopeSpans []struct {
+					Scope struct {
+						Name string `json:"name"`
+					} `json:"scope"`
+					Spans []map[string]any `json:"spans"`
+				} `json:"scopeSpans"`
+			} `json:"resourceSpans"`
+		}
+		assert.Equal(t, 1, bytes.Count(out.Bytes(), []byte("\n")))
+		if assert.NoError(t, json.Unmarshal(out.Bytes(), &request)) {
+			resource := request.ResourceSpans[0]
+			assert.Equal(t, []map[string]any{{"key": "service.name", "value": map[string]any{"stringValue": "svc"}}}, resource.Resource.Attributes)
+			assert.Equal(t, tracing.ScopeName, resource.ScopeSpans[0].Scope.Name)
+
+			spans := resource.ScopeSpans[0].Spans
+			if assert.Len(t, spans, 2) {
+				assert.Equal(t, map[string]any{
+					"traceId":           root.TraceID().String(),
+					"spanId":            child.SpanID().String(),
+					"parentSpanId":      root.SpanID().String(),
+					"name":              "uc.ListTasks",
+					"kind":              float64(tracing.KindInternal),
+					"startTimeUnixNano": "1700000000002000000",
+					"endTimeUnixNano":   "1700000000003000000",
+					"attributes": []any{
+						map[string]any{"key": "count", "value": map[string]any{"intValue": "2"}},
+						map[string]any{"key": "cached", "value": map[string]any{"boolValue": false}},
+					},
+					"status": map[string]any{},
+				}, spans[0])
+				assert.NotContains(t, spans[1], "parentSpanId")
+				assert.Equal(t, float64(tracing.KindServer), spans[1]["kind"])
+			}
+		}
+	})
+}
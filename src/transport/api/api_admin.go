@@ -0,0 +1,289 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"live-semantic/src/auth"
+	"live-semantic/src/config"
+	"live-semantic/src/domain/uc"
+	"live-semantic/src/lifecycle"
+	"live-semantic/src/logging"
+	"net/http"
+	"net/http/pprof"
+	"runtime"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ActionLoggingUpdate action d'audit de la modification des niveaux de log
+const ActionLoggingUpdate = "logging.update"
+
+// logLevelsRequest modifie les niveaux de log
+// Un niveau omis est conservé, un module au niveau vide retrouve le niveau par défaut
+type logLevelsRequest struct {
+	Level   string            `json:"level"`
+	Modules map[string]string `json:"modules"`
+	// Persist écrit les niveaux obtenus dans le fichier de configuration
+	Persist bool `json:"persist"`
+}
+
+// logLevelsResponse niveaux de log en vigueur
+type logLevelsResponse struct {
+	logging.Snapshot
+	Persisted bool `json:"persisted,omitempty"`
+}
+
+// getLogLevels retourne le niveau par défaut et les niveaux par module
+func (s *Server) getLogLevels(c *gin.Context) {
+	snapshot := s.logLevels.Snapshot()
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    logLevelsResponse{Snapshot: snapshot},
+		"source":  "web",
+	})
+}
+
+// setLogLevels modifie les niveaux de log à chaud et les persiste sur demande
+func (s *Server) setLogLevels(c *gin.Context) {
+	var req logLevelsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "Invalid JSON: " + err.Error(),
+			"source":  "web",
+		})
+		return
+	}
+
+	snapshot, err := s.logLevels.Update(req.Level, req.Modules)
+	if err != nil {
+		s.audit(c, ActionLoggingUpdate, "logging", err.Error())
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+			"source":  "web",
+		})
+		return
+	}
+
+	fields := map[string]interface{}{
+		"level":   snapshot.Level,
+		"modules": snapshot.Entries(),
+	}
+	if principal, ok := auth.PrincipalFrom(c.Request.Context()); ok {
+		fields["subject"] = principal.Subject
+	}
+	s.logger.Info("Log levels changed", fields)
+
+	response := logLevelsResponse{Snapshot: snapshot}
+	if req.Persist {
+		if s.persistLogLevels == nil {
+			c.JSON(http.StatusNotImplemented, gin.H{
+				"success": false,
+				"error":   "log levels cannot be persisted by this server",
+				"source":  "web",
+			})
+			return
+		}
+		if err := s.persistLogLevels(snapshot); err != nil {
+			s.audit(c, ActionLoggingUpdate, "logging", "applied but not persisted: "+err.Error())
+			s.logger.Error("Failed to persist log levels", map[string]interface{}{
+				"error": err.Error(),
+			})
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"success": false,
+				"error":   "log levels applied but not persisted: " + err.Error(),
+				"source":  "web",
+			})
+			return
+		}
+		response.Persisted = true
+	}
+	s.audit(c, ActionLoggingUpdate, "logging", "")
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    response,
+		"source":  "web",
+	})
+}
+
+// audit enregistre une opération d'administration sur resource, en échec lorsque reason est renseignée
+func (s *Server) audit(c *gin.Context, action, resource, reason string) {
+	if s.auditor == nil {
+		return
+	}
+
+	ctx := c.Request.Context()
+	request, _ := uc.RequestFrom(ctx)
+	request.Source = "web"
+	ctx = uc.WithRequest(ctx, request)
+
+	record := uc.NewAuditRecord(ctx, action, uc.OutcomeSuccess)
+	record.Resource = resource
+	if reason != "" {
+		record.Outcome = uc.OutcomeFailure
+		record.Reason = reason
+	}
+	s.auditor.Record(ctx, record)
+}
+
+// Actions d'audit des opérations d'administration
+const (
+	ActionComponentDrain    = "component.drain"
+	ActionSessionDisconnect = "session.disconnect"
+)
+
+// processStart instant de démarrage du processus, pour la durée de fonctionnement
+var processStart = time.Now()
+
+// runtimeResponse état du runtime Go du processus
+type runtimeResponse struct {
+	GoVersion  string  `json:"go_version"`
+	Goroutines int     `json:"goroutines"`
+	GOMAXPROCS int     `json:"gomaxprocs"`
+	CPUs       int     `json:"cpus"`
+	HeapAlloc  uint64  `json:"heap_alloc_bytes"`
+	HeapSys    uint64  `json:"heap_sys_bytes"`
+	NumGC      uint32  `json:"gc_cycles"`
+	Uptime     float64 `json:"uptime_seconds"`
+}
+
+// getRuntime retourne le nombre de goroutines, la mémoire et la durée de fonctionnement du processus
+func (s *Server) getRuntime(c *gin.Context) {
+	var mem runtime.MemStats
+	runtime.ReadMemStats(&mem)
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data": runtimeResponse{
+			GoVersion:  runtime.Version(),
+			Goroutines: runtime.NumGoroutine(),
+			GOMAXPROCS: runtime.GOMAXPROCS(0),
+			CPUs:       runtime.NumCPU(),
+			HeapAlloc:  mem.HeapAlloc,
+			HeapSys:    mem.HeapSys,
+			NumGC:      mem.NumGC,
+			Uptime:     time.Since(processStart).Seconds(),
+		},
+		"source": "web",
+	})
+}
+
+// getConfig retourne la configuration effective, rechargements compris, secrets masqués
+func (s *Server) getConfig(c *gin.Context) {
+	data, err := config.Export(*s.currentConfig(), "json")
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   err.Error(),
+			"source":  "web",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    json.RawMessage(data),
+		"source":  "web",
+	})
+}
+
+// listComponents retourne l'état des composants du cycle de vie
+func (s *Server) listComponents(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    s.components.Components(),
+		"source":  "web",
+	})
+}
+
+// drainComponent draine un composant dans la limite du délai de drainage
+// Le composant cesse d'accepter de nouvelles requêtes jusqu'au redémarrage du processus
+func (s *Server) drainComponent(c *gin.Context) {
+	name := c.Param("name")
+	ctx, cancel := context.WithTimeout(c.Request.Context(), s.ShutdownTimeout())
+	defer cancel()
+
+	err := s.components.Drain(ctx, name)
+	if errors.Is(err, lifecycle.ErrNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{
+			"success": false,
+			"error":   err.Error(),
+			"source":  "web",
+		})
+		return
+	}
+	if err != nil {
+		s.audit(c, ActionComponentDrain, name, err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   err.Error(),
+			"source":  "web",
+		})
+		return
+	}
+
+	s.audit(c, ActionComponentDrain, name, "")
+	s.logger.Info("Component drained", map[string]interface{}{
+		"component": name,
+	})
+	state, _ := s.components.State(name)
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    lifecycle.Status{Name: name, State: state},
+		"source":  "web",
+	})
+}
+
+// listSessions retourne les sessions WebSocket ouvertes de tous les composants
+func (s *Server) listSessions(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    s.components.Sessions(),
+		"source":  "web",
+	})
+}
+
+// disconnectSession ferme une session WebSocket
+func (s *Server) disconnectSession(c *gin.Context) {
+	if err := s.components.Disconnect(c.Param("id")); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"success": false,
+			"error":   err.Error(),
+			"source":  "web",
+		})
+		return
+	}
+
+	s.audit(c, ActionSessionDisconnect, c.Param("id"), "")
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"source":  "web",
+	})
+}
+
+// listQueues retourne la profondeur des files des composants et des cas d'usage
+func (s *Server) listQueues(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    s.components.Queues(),
+		"source":  "web",
+	})
+}
+
+// registerProfiles monte les profils pprof sur le groupe fourni
+// pprof.Index ne reconnaît que le préfixe /debug/pprof/, chaque profil est donc routé explicitement
+func registerProfiles(group *gin.RouterGroup) {
+	group.GET("/", gin.WrapF(pprof.Index))
+	group.GET("/cmdline", gin.WrapF(pprof.Cmdline))
+	group.GET("/profile", gin.WrapF(pprof.Profile))
+	group.GET("/symbol", gin.WrapF(pprof.Symbol))
+	group.POST("/symbol", gin.WrapF(pprof.Symbol))
+	group.GET("/trace", gin.WrapF(pprof.Trace))
+	group.GET("/:profile", func(c *gin.Context) {
+		pprof.Handler(c.Param("profile")).ServeHTTP(c.Writer, c.Request)
+	})
+}
@@ -0,0 +1,129 @@
+package api
+
+import (
+	"fmt"
+	"live-semantic/src/domain/dto"
+	"live-semantic/src/transport"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-contrib/sse"
+	"github.com/gin-gonic/gin"
+)
+
+// DefaultHeartbeat intervalle des commentaires gardant le flux ouvert derrière les proxys
+const DefaultHeartbeat = 15 * time.Second
+
+// En-têtes décrivant la reprise du flux
+const (
+	// truncatedHeader signale que des événements suivant Last-Event-ID ne sont plus en mémoire
+	truncatedHeader = "X-Events-Truncated"
+	// replayedHeader nombre d'événements rejoués en tête du flux
+	replayedHeader = "X-Events-Replayed"
+)
+
+// streamEvents diffuse les événements du domaine en Server-Sent Events
+// La reprise utilise l'en-tête Last-Event-ID, ou le paramètre last_event_id pour un premier appel
+func (s *Server) streamEvents(c *gin.Context) {
+	req, err := eventRequest(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+			"source":  "web",
+		})
+		return
+	}
+
+	// Créer le handler de base
+	baseHandler := transport.NewBaseHandler(s.useCases, s.logger, transport.WithMetrics(s.metrics))
+
+	// L'abonnement suit le contexte de la requête
+	response := baseHandler.HandleSubscribeEvents(transport.TransportRequest[dto.EventRequest]{
+		Data:    req,
+		Context: c.Request.Context(),
+		Source:  "web",
+	})
+	if !response.Success {
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	header := c.Writer.Header()
+	header.Set("Content-Type", sse.ContentType)
+	header.Set("Cache-Control", "no-cache")
+	header.Set("Connection", "keep-alive")
+	// Désactiver la mise en tampon des proxys nginx
+	header.Set("X-Accel-Buffering", "no")
+	header.Set(replayedHeader, strconv.Itoa(response.Data.Replayed))
+	if response.Data.Truncated {
+		header.Set(truncatedHeader, "true")
+	}
+	c.Status(http.StatusOK)
+	c.Writer.Flush()
+
+	heartbeat := time.NewTicker(s.heartbeat)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case event, ok := <-response.Data.Events:
+			if !ok {
+				return
+			}
+			err := sse.Encode(c.Writer, sse.Event{
+				Id:    strconv.FormatUint(event.ID, 10),
+				Event: event.Type,
+				Data:  event,
+			})
+			if err != nil {
+				return
+			}
+			c.Writer.Flush()
+		case <-heartbeat.C:
+			if _, err := c.Writer.WriteString(": heartbeat\n\n"); err != nil {
+				return
+			}
+			c.Writer.Flush()
+		case <-s.streamsClosed:
+			return
+		case <-c.Request.Context().Done():
+			return
+		}
+	}
+}
+
+// eventRequest lit les filtres ?type= (répétables ou séparés par des virgules), ?recent= et l'identifiant de reprise
+func eventRequest(c *gin.Context) (dto.EventRequest, error) {
+	var req dto.EventRequest
+	for _, value := range c.QueryArray("type") {
+		for _, eventType := range strings.Split(value, ",") {
+			if eventType = strings.TrimSpace(eventType); eventType != "" {
+				req.Types = append(req.Types, eventType)
+			}
+		}
+	}
+
+	if recent := c.Query("recent"); recent != "" {
+		n, err := strconv.Atoi(recent)
+		if err != nil || n < 0 {
+			return req, fmt.Errorf("Invalid recent: %s", recent)
+		}
+		req.Recent = n
+	}
+
+	lastEventID := c.GetHeader("Last-Event-ID")
+	if lastEventID == "" {
+		lastEventID = c.Query("last_event_id")
+	}
+	if lastEventID != "" {
+		id, err := strconv.ParseUint(lastEventID, 10, 64)
+		if err != nil {
+			return req, fmt.Errorf("Invalid Last-Event-ID: %s", lastEventID)
+		}
+		req.LastEventID = id
+	}
+	return req, nil
+}
@@ -0,0 +1,64 @@
+package api
+
+import (
+	"live-semantic/src/domain/dto"
+	"live-semantic/src/transport"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// listTasks handler pour lister les tâches
+func (s *Server) listTasks(c *gin.Context) {
+	if req, ok := s.listRequest(c); ok {
+		respondList(c, transport.NewBaseHandler(s.useCases, s.logger, transport.WithMetrics(s.metrics)).HandleListTasks(req))
+	}
+}
+
+// listFilters handler pour lister les filtres prédéfinis
+func (s *Server) listFilters(c *gin.Context) {
+	if req, ok := s.listRequest(c); ok {
+		respondList(c, transport.NewBaseHandler(s.useCases, s.logger, transport.WithMetrics(s.metrics)).HandleListFilters(req))
+	}
+}
+
+// listSources handler pour lister les sources vidéo
+func (s *Server) listSources(c *gin.Context) {
+	if req, ok := s.listRequest(c); ok {
+		respondList(c, transport.NewBaseHandler(s.useCases, s.logger, transport.WithMetrics(s.metrics)).HandleListSources(req))
+	}
+}
+
+// listRequest lit les paramètres ?prefix= et ?limit= de la requête
+func (s *Server) listRequest(c *gin.Context) (transport.TransportRequest[dto.ListRequest], bool) {
+	req := dto.ListRequest{Prefix: c.Query("prefix")}
+
+	if limit := c.Query("limit"); limit != "" {
+		n, err := strconv.Atoi(limit)
+		if err != nil || n < 0 {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"success": false,
+				"error":   "Invalid limit: " + limit,
+				"source":  "web",
+			})
+			return transport.TransportRequest[dto.ListRequest]{}, false
+		}
+		req.Limit = n
+	}
+
+	return transport.TransportRequest[dto.ListRequest]{
+		Data:    req,
+		Context: c.Request.Context(),
+		Source:  "web",
+	}, true
+}
+
+// respondList retourne la réponse d'une liste
+func respondList[T any](c *gin.Context, response transport.TransportResponse[T]) {
+	if response.Success {
+		c.JSON(http.StatusOK, response)
+	} else {
+		c.JSON(http.StatusInternalServerError, response)
+	}
+}
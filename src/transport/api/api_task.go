@@ -23,7 +23,7 @@ func (s *Server) createTask(c *gin.Context) {
 	}
 
 	// Créer le handler de base
-	baseHandler := transport.NewBaseHandler(s.useCases, s.logger)
+	baseHandler := transport.NewBaseHandler(s.useCases, s.logger, transport.WithMetrics(s.metrics))
 
 	// Créer la requête transport
 	transportReq := transport.TransportRequest[dto.TaskRequest]{
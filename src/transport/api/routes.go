@@ -1,6 +1,10 @@
 package api
 
 import (
+	"live-semantic/src/auth"
+	"live-semantic/src/health"
+	"live-semantic/src/metrics"
+	"live-semantic/src/transport/authn"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
@@ -8,21 +12,85 @@ import (
 
 // setupRoutes configure les routes
 func (s *Server) setupRoutes() {
-	// Health check
-	s.router.GET("/health", s.healthCheck)
+	// Health, liveness et readiness
+	s.healthEndpoints().Register(s.router)
 
+	s.RegisterRoutes(s.router)
+	// Métriques au format Prometheus, publiques comme la santé
+	if handler := metrics.Handler(s.metrics); handler != nil {
+		s.router.GET("/metrics", gin.WrapH(handler))
+	}
+}
+
+// RegisterRoutes monte les routes de l'API sur le routeur fourni
+// Elle permet d'héberger l'API sur un routeur partagé
+func (s *Server) RegisterRoutes(router gin.IRouter) {
 	// API routes
-	api := s.router.Group("/api/v1")
+	api := router.Group("/api/v1", s.refuseWhileDraining)
 	{
-		api.POST("/createTask", s.createTask)
+		api.POST("/createTask", s.limiter.IPMiddleware("web"), s.require(auth.ScopeTasksWrite), s.limiter.Middleware("web"), s.createTask)
+		api.GET("/tasks", s.limiter.IPMiddleware("web"), s.require(auth.ScopeTasksRead), s.limiter.Middleware("web"), s.listTasks)
+		api.GET("/filters", s.limiter.IPMiddleware("web"), s.require(auth.ScopeTasksRead), s.limiter.Middleware("web"), s.listFilters)
+		api.GET("/sources", s.limiter.IPMiddleware("web"), s.require(auth.ScopeTasksRead), s.limiter.Middleware("web"), s.listSources)
+		// Un flux reste ouvert, seule son ouverture est limitée
+		api.GET("/events", s.limiter.IPMiddleware("web"), s.require(auth.ScopeTasksRead), s.limiter.StreamMiddleware("web"), s.streamEvents)
+	}
+
+	// Administration du processus, servie même pendant le drainage
+	if s.logLevels == nil && s.components == nil && s.currentConfig == nil && !s.profiling {
+		return
+	}
+	// Elle n'est jamais exposée sans authentification
+	if s.authenticator == nil {
+		s.logger.Warn("Admin endpoints not served: authentication is disabled")
+		return
+	}
+	admin := router.Group("/api/v1/admin", s.limiter.IPMiddleware("web"), s.require(auth.ScopeSystemAdmin), s.limiter.Middleware("web"))
+	admin.GET("/runtime", s.getRuntime)
+	if s.logLevels != nil {
+		admin.GET("/logging", s.getLogLevels)
+		admin.PUT("/logging", s.setLogLevels)
+	}
+	if s.currentConfig != nil {
+		admin.GET("/config", s.getConfig)
 	}
+	if s.components != nil {
+		admin.GET("/components", s.listComponents)
+		admin.POST("/components/:name/drain", s.drainComponent)
+		admin.GET("/sessions", s.listSessions)
+		admin.DELETE("/sessions/:id", s.disconnectSession)
+		admin.GET("/queues", s.listQueues)
+	}
+	// Les profils exposent la mémoire du processus, ils restent réservés à system:admin
+	if s.profiling {
+		registerProfiles(admin.Group("/debug/pprof"))
+	}
+}
+
+// refuseWhileDraining répond 503 aux requêtes reçues après le début du drainage
+func (s *Server) refuseWhileDraining(c *gin.Context) {
+	if s.draining.Load() {
+		c.AbortWithStatusJSON(http.StatusServiceUnavailable, gin.H{
+			"success": false,
+			"error":   "server is draining",
+			"source":  "web",
+		})
+		return
+	}
+	c.Next()
+}
+
+// require authentifie la requête et exige le scope, sans effet si l'authentification est désactivée
+func (s *Server) require(scope string) gin.HandlerFunc {
+	return authn.Middleware(s.authenticator, s.auditor, "web", scope)
 }
 
-// healthCheck endpoint de santé
-func (s *Server) healthCheck(c *gin.Context) {
-	c.JSON(http.StatusOK, gin.H{
-		"status":  "ok",
-		"service": "live-semantic",
-		"version": "1.0.0",
-	})
+// healthEndpoints endpoints de santé agrégeant les sondes du registre
+func (s *Server) healthEndpoints() health.Endpoints {
+	return health.Endpoints{
+		Registry: s.health,
+		Service:  "live-semantic",
+		Version:  "1.0.0",
+		Draining: s.draining.Load,
+	}
 }
@@ -1,23 +1,187 @@
 package api
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"live-semantic/src/auth"
+	"live-semantic/src/config"
 	"live-semantic/src/domain/uc"
+	"live-semantic/src/health"
+	"live-semantic/src/lifecycle"
+	"live-semantic/src/logging"
+	"live-semantic/src/metrics"
+	"live-semantic/src/tracing"
+	"live-semantic/src/transport/cors"
+	"live-semantic/src/transport/ratelimit"
+	"live-semantic/src/transport/requestid"
+	"live-semantic/src/transport/traces"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/deadelus/go-clean-app/src/logger"
 	"github.com/gin-gonic/gin"
 )
 
+// DefaultShutdownTimeout délai accordé aux requêtes en cours lors de l'arrêt
+const DefaultShutdownTimeout = 15 * time.Second
+
 // Server représente le serveur web
 type Server struct {
-	useCases uc.UseCases
-	logger   logger.Logger
-	port     int
-	router   *gin.Engine
+	useCases         uc.UseCases
+	logger           logger.Logger
+	host             string
+	trustedProxies   []string
+	port             int
+	router           *gin.Engine
+	httpServer       *http.Server
+	health           *health.Registry
+	authenticator    *auth.Authenticator
+	limiter          *ratelimit.Limiter
+	cors             *cors.Policy
+	metrics          metrics.Collector
+	tracer           *tracing.Tracer
+	logLevels        *logging.Levels
+	persistLogLevels logging.Persister
+	auditor          uc.Auditor
+	components       *lifecycle.Registry
+	currentConfig    func() *config.Config
+	profiling        bool
+	draining         atomic.Bool
+	shutdownTimeout  atomic.Int64
+	heartbeat        time.Duration
+	streamsClosed    chan struct{}
+	closeStreams     sync.Once
+	stopped          chan struct{}
+	stopOnce         sync.Once
+}
+
+// Option configure le serveur web
+type Option func(*Server)
+
+// WithHost définit l'interface d'écoute, vide pour toutes les interfaces
+func WithHost(host string) Option {
+	return func(s *Server) {
+		s.host = host
+	}
+}
+
+// WithTrustedProxies définit les proxies, adresses IP ou CIDR, dont X-Forwarded-For est cru
+// Sans proxy de confiance, le client d'une requête est l'adresse de sa connexion
+func WithTrustedProxies(proxies []string) Option {
+	return func(s *Server) {
+		s.trustedProxies = proxies
+	}
+}
+
+// WithShutdownTimeout définit le délai de drainage des requêtes en cours
+func WithShutdownTimeout(timeout time.Duration) Option {
+	return func(s *Server) {
+		s.shutdownTimeout.Store(int64(timeout))
+	}
+}
+
+// WithHealth définit le registre des sondes agrégées par /health et /readyz
+func WithHealth(registry *health.Registry) Option {
+	return func(s *Server) {
+		s.health = registry
+	}
+}
+
+// WithAuthenticator exige une clé d'API ou un jeton portant le scope de chaque route
+// Les endpoints de santé restent publics
+func WithAuthenticator(authenticator *auth.Authenticator) Option {
+	return func(s *Server) {
+		s.authenticator = authenticator
+	}
+}
+
+// WithRateLimiter limite le débit et les appels simultanés de chaque client
+// Les endpoints de santé ne sont pas limités
+func WithRateLimiter(limiter *ratelimit.Limiter) Option {
+	return func(s *Server) {
+		s.limiter = limiter
+	}
+}
+
+// WithHeartbeat définit l'intervalle des commentaires envoyés sur les flux d'événements
+func WithHeartbeat(interval time.Duration) Option {
+	return func(s *Server) {
+		if interval > 0 {
+			s.heartbeat = interval
+		}
+	}
+}
+
+// WithCORS définit la politique d'origines des navigateurs
+// Sans elle, seules les requêtes de même origine et les clients hors navigateur sont acceptés
+func WithCORS(policy *cors.Policy) Option {
+	return func(s *Server) {
+		s.cors = policy
+	}
+}
+
+// WithMetrics enregistre les métriques des requêtes et des appels de cas d'usage et les expose sur /metrics
+// lorsque le collecteur sait les exposer
+func WithMetrics(collector metrics.Collector) Option {
+	return func(s *Server) {
+		if collector != nil {
+			s.metrics = collector
+		}
+	}
+}
+
+// WithTracer démarre un span serveur pour chaque requête, qui continue la trace de l'en-tête traceparent
+func WithTracer(tracer *tracing.Tracer) Option {
+	return func(s *Server) {
+		s.tracer = tracer
+	}
+}
+
+// WithLogLevels expose les niveaux de log sur /api/v1/admin/logging pour les modifier à chaud
+// persist écrit les niveaux dans la configuration lorsque la requête le demande, nil refuse la persistance
+func WithLogLevels(levels *logging.Levels, persist logging.Persister) Option {
+	return func(s *Server) {
+		s.logLevels = levels
+		s.persistLogLevels = persist
+	}
+}
+
+// WithAuditor enregistre les opérations d'administration et les refus de scope dans la piste d'audit
+func WithAuditor(auditor uc.Auditor) Option {
+	return func(s *Server) {
+		s.auditor = auditor
+	}
+}
+
+// WithComponents expose sur /api/v1/admin les composants du cycle de vie, leurs sessions et leurs files
+// et permet de drainer un composant ou de déconnecter une session
+func WithComponents(registry *lifecycle.Registry) Option {
+	return func(s *Server) {
+		s.components = registry
+	}
+}
+
+// WithConfig expose la configuration effective, secrets masqués, sur /api/v1/admin/config
+func WithConfig(current func() *config.Config) Option {
+	return func(s *Server) {
+		s.currentConfig = current
+	}
+}
+
+// WithProfiling expose les profils pprof sous /api/v1/admin/debug/pprof, derrière le scope system:admin
+func WithProfiling(enabled bool) Option {
+	return func(s *Server) {
+		s.profiling = enabled
+	}
 }
 
 // NewServer crée un nouveau serveur web
-func NewServer(useCases uc.UseCases, logger logger.Logger, port int) *Server {
+func NewServer(useCases uc.UseCases, logger logger.Logger, port int, options ...Option) *Server {
 	gin.SetMode(gin.ReleaseMode)
 	router := gin.New()
 	router.Use(gin.Recovery())
@@ -27,17 +191,123 @@ func NewServer(useCases uc.UseCases, logger logger.Logger, port int) *Server {
 		logger:   logger,
 		port:     port,
 		router:   router,
+		health:   health.NewRegistry(),
+		cors:     cors.New(),
+		metrics:  metrics.Discard,
+		stopped:  make(chan struct{}),
+
+		heartbeat:     DefaultHeartbeat,
+		streamsClosed: make(chan struct{}),
 	}
 
+	server.shutdownTimeout.Store(int64(DefaultShutdownTimeout))
+	for _, option := range options {
+		option(server)
+	}
+	if err := router.SetTrustedProxies(server.trustedProxies); err != nil {
+		logger.Warn("Invalid trusted proxies, forwarded headers ignored", map[string]interface{}{"error": err.Error()})
+		_ = router.SetTrustedProxies(nil)
+	}
+	router.Use(requestid.Middleware(), traces.Middleware(server.tracer), metrics.Middleware(server.metrics), server.cors.Middleware())
+
+	server.httpServer = &http.Server{
+		Addr:    net.JoinHostPort(server.host, strconv.Itoa(port)),
+		Handler: router,
+	}
+	// Les flux d'événements ne se terminent pas d'eux-mêmes
+	server.httpServer.RegisterOnShutdown(server.CloseStreams)
+
 	server.setupRoutes()
 	return server
 }
 
+// Handler retourne le routeur du serveur, utile pour l'héberger ou le tester sans listener
+func (s *Server) Handler() http.Handler {
+	return s.router
+}
+
 // Start démarre le serveur web
+// Il bloque jusqu'à la fin du drainage lorsque Stop est appelé
 func (s *Server) Start() error {
 	s.logger.Info("Starting web server", map[string]interface{}{
 		"port": s.port,
 	})
 
-	return s.router.Run(fmt.Sprintf(":%d", s.port))
+	err := s.httpServer.ListenAndServe()
+	if !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+
+	<-s.stopped
+	return nil
+}
+
+// Stop arrête le serveur en laissant aux requêtes en cours le délai de drainage
+// Elle est destinée à être enregistrée auprès de engine.Gracefull()
+func (s *Server) Stop() error {
+	ctx, cancel := context.WithTimeout(context.Background(), s.ShutdownTimeout())
+	defer cancel()
+
+	return s.Shutdown(ctx)
+}
+
+// ShutdownTimeout retourne le délai de drainage courant
+func (s *Server) ShutdownTimeout() time.Duration {
+	return time.Duration(s.shutdownTimeout.Load())
+}
+
+// SetShutdownTimeout met à jour le délai de drainage, y compris pendant l'exécution
+func (s *Server) SetShutdownTimeout(timeout time.Duration) {
+	s.shutdownTimeout.Store(int64(timeout))
+}
+
+// CloseStreams termine les flux d'événements en cours pour que l'arrêt n'attende pas leurs clients
+// Les clients reprennent le flux sur une autre instance avec Last-Event-ID
+func (s *Server) CloseStreams() {
+	s.closeStreams.Do(func() { close(s.streamsClosed) })
+}
+
+// State implémente lifecycle.Component
+func (s *Server) State() lifecycle.State {
+	select {
+	case <-s.stopped:
+		return lifecycle.StateStopped
+	default:
+	}
+	if s.draining.Load() {
+		return lifecycle.StateDraining
+	}
+	return lifecycle.StateRunning
+}
+
+// Drain refuse les nouvelles requêtes de /api/v1 et termine les flux d'événements
+// Les routes d'administration et de santé restent servies
+func (s *Server) Drain(ctx context.Context) error {
+	s.draining.Store(true)
+	s.CloseStreams()
+
+	s.logger.Info("Web server draining", map[string]interface{}{
+		"timeout": s.ShutdownTimeout().String(),
+	})
+	return nil
+}
+
+// Shutdown arrête le serveur et attend la fin des requêtes en cours ou l'expiration du contexte
+func (s *Server) Shutdown(ctx context.Context) error {
+	defer s.stopOnce.Do(func() { close(s.stopped) })
+
+	s.draining.Store(true)
+
+	s.logger.Info("Draining web server", map[string]interface{}{
+		"timeout": s.ShutdownTimeout().String(),
+	})
+
+	if err := s.httpServer.Shutdown(ctx); err != nil {
+		// Forcer la fermeture des connexions restantes
+		_ = s.httpServer.Close()
+		return fmt.Errorf("web server drain: %w", err)
+	}
+
+	s.logger.Info("Web server stopped")
+	return nil
 }
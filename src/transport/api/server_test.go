@@ -0,0 +1,305 @@
+package api_test
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"live-semantic/src/auth"
+	"live-semantic/src/domain/dto"
+	"live-semantic/src/domain/uc"
+	"live-semantic/src/logging"
+	"live-semantic/src/transport/api"
+	"live-semantic/src/transport/ratelimit"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// slowUseCases répond à ListTasks après un délai, pour garder une requête en cours
+type slowUseCases struct {
+	uc.UseCases
+	delay time.Duration
+}
+
+func (s slowUseCases) ListTasks(ctx context.Context, req dto.ListRequest) (dto.Result[[]dto.TaskResponse], error) {
+	time.Sleep(s.delay)
+	return dto.Success([]dto.TaskResponse{}), nil
+}
+
+func newUseCases(t *testing.T) uc.UseCases {
+	t.Helper()
+
+	useCases, err := uc.NewUseCase(logging.Discard)
+	assert.NoError(t, err)
+	return useCases
+}
+
+// freePort retourne un port libre pour démarrer le serveur sur un vrai listener
+func freePort(t *testing.T) int {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	defer listener.Close()
+	return listener.Addr().(*net.TCPAddr).Port
+}
+
+func TestServer_Drain(t *testing.T) {
+	t.Run("should refuse the API requests and report not ready while draining", func(t *testing.T) {
+		// Given
+		server := api.NewServer(newUseCases(t), logging.Discard, 0)
+		ts := httptest.NewServer(server.Handler())
+		defer ts.Close()
+
+		// When
+		assert.NoError(t, server.Drain(context.Background()))
+		tasks, tasksErr := http.Get(ts.URL + "/api/v1/tasks")
+		ready, readyErr := http.Get(ts.URL + "/readyz")
+		live, liveErr := http.Get(ts.URL + "/livez")
+
+		// Then
+		if assert.NoError(t, tasksErr) {
+			tasks.Body.Close()
+			assert.Equal(t, http.StatusServiceUnavailable, tasks.StatusCode)
+		}
+		if assert.NoError(t, readyErr) {
+			ready.Body.Close()
+			assert.Equal(t, http.StatusServiceUnavailable, ready.StatusCode)
+		}
+		if assert.NoError(t, liveErr) {
+			live.Body.Close()
+			assert.Equal(t, http.StatusOK, live.StatusCode)
+		}
+	})
+
+	t.Run("should end the open event streams", func(t *testing.T) {
+		// Given
+		server := api.NewServer(newUseCases(t), logging.Discard, 0)
+		ts := httptest.NewServer(server.Handler())
+		defer ts.Close()
+		stream, err := http.Get(ts.URL + "/api/v1/events")
+		if !assert.NoError(t, err) {
+			return
+		}
+		defer stream.Body.Close()
+
+		// When
+		assert.NoError(t, server.Drain(context.Background()))
+		done := make(chan error, 1)
+		go func() {
+			_, err := io.ReadAll(stream.Body)
+			done <- err
+		}()
+
+		// Then
+		select {
+		case err := <-done:
+			assert.NoError(t, err)
+		case <-time.After(2 * time.Second):
+			t.Fatal("the event stream was not ended by the drain")
+		}
+	})
+}
+
+func TestServer_Shutdown(t *testing.T) {
+	t.Run("should wait for the in-flight requests", func(t *testing.T) {
+		// Given
+		port := freePort(t)
+		server := api.NewServer(slowUseCases{UseCases: newUseCases(t), delay: 300 * time.Millisecond}, logging.Discard, port)
+		started := make(chan error, 1)
+		go func() { started <- server.Start() }()
+		url := fmt.Sprintf("http://127.0.0.1:%d", port)
+		assert.Eventually(t, func() bool {
+			resp, err := http.Get(url + "/livez")
+			if err != nil {
+				return false
+			}
+			resp.Body.Close()
+			return true
+		}, 2*time.Second, 10*time.Millisecond)
+
+		responses := make(chan int, 1)
+		go func() {
+			resp, err := http.Get(url + "/api/v1/tasks")
+			if err != nil {
+				responses <- 0
+				return
+			}
+			resp.Body.Close()
+			responses <- resp.StatusCode
+		}()
+		time.Sleep(100 * time.Millisecond)
+
+		// When
+		err := server.Stop()
+
+		// Then
+		assert.NoError(t, err)
+		select {
+		case status := <-responses:
+			assert.Equal(t, http.StatusOK, status)
+		default:
+			t.Fatal("Stop returned before the in-flight request ended")
+		}
+		assert.NoError(t, <-started)
+	})
+
+	t.Run("should cut the requests exceeding the shutdown timeout", func(t *testing.T) {
+		// Given
+		port := freePort(t)
+		server := api.NewServer(slowUseCases{UseCases: newUseCases(t), delay: time.Second}, logging.Discard, port, api.WithShutdownTimeout(100*time.Millisecond))
+		go func() { _ = server.Start() }()
+		url := fmt.Sprintf("http://127.0.0.1:%d", port)
+		assert.Eventually(t, func() bool {
+			resp, err := http.Get(url + "/livez")
+			if err != nil {
+				return false
+			}
+			resp.Body.Close()
+			return true
+		}, 2*time.Second, 10*time.Millisecond)
+		go func() { _, _ = http.Get(url + "/api/v1/tasks") }()
+		time.Sleep(100 * time.Millisecond)
+
+		// When
+		err := server.Stop()
+
+		// Then
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+}
+
+func TestServer_RateLimit(t *testing.T) {
+	// get envoie une requête sur /api/v1/tasks depuis l'adresse du proxy de test 192.0.2.1
+	get := func(server *api.Server, forwardedFor string) int {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/tasks", nil)
+		req.Header.Set("X-Forwarded-For", forwardedFor)
+		rec := httptest.NewRecorder()
+		server.Handler().ServeHTTP(rec, req)
+		return rec.Code
+	}
+	limiter := func() *ratelimit.Limiter {
+		return ratelimit.New(ratelimit.WithLimit(ratelimit.Limit{Rate: 0.1, Burst: 1}))
+	}
+	// getWithKey envoie une requête sur /api/v1/tasks avec la clé fournie, depuis la même adresse
+	getWithKey := func(server *api.Server, key string) int {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/tasks", nil)
+		req.Header.Set(auth.KeyHeader, key)
+		rec := httptest.NewRecorder()
+		server.Handler().ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	t.Run("should key a client by its connection address whatever its X-Forwarded-For", func(t *testing.T) {
+		// Given
+		server := api.NewServer(newUseCases(t), logging.Discard, 0, api.WithRateLimiter(limiter()))
+
+		// When
+		first := get(server, "10.0.0.1")
+		spoofed := get(server, "10.0.0.2")
+
+		// Then
+		assert.Equal(t, http.StatusOK, first)
+		assert.Equal(t, http.StatusTooManyRequests, spoofed)
+	})
+
+	t.Run("should key a client by its X-Forwarded-For behind a trusted proxy", func(t *testing.T) {
+		// Given
+		server := api.NewServer(newUseCases(t), logging.Discard, 0, api.WithRateLimiter(limiter()), api.WithTrustedProxies([]string{"192.0.2.0/24"}))
+
+		// When
+		first := get(server, "10.0.0.1")
+		other := get(server, "10.0.0.2")
+
+		// Then
+		assert.Equal(t, http.StatusOK, first)
+		assert.Equal(t, http.StatusOK, other)
+	})
+
+	t.Run("should give each principal its own bucket behind the same address", func(t *testing.T) {
+		// Given
+		first, firstKey, err := auth.NewKey("ci", []string{auth.ScopeTasksRead}, nil)
+		assert.NoError(t, err)
+		second, secondKey, err := auth.NewKey("batch", []string{auth.ScopeTasksRead}, nil)
+		assert.NoError(t, err)
+		server := api.NewServer(newUseCases(t), logging.Discard, 0, api.WithRateLimiter(limiter()), api.WithAuthenticator(auth.New(auth.WithKeys(first, second))))
+
+		// When
+		allowed := getWithKey(server, firstKey)
+		throttled := getWithKey(server, firstKey)
+		other := getWithKey(server, secondKey)
+
+		// Then
+		assert.Equal(t, http.StatusOK, allowed)
+		assert.Equal(t, http.StatusTooManyRequests, throttled)
+		assert.Equal(t, http.StatusOK, other)
+	})
+
+	t.Run("should throttle the requests refused for missing credentials on the address limit", func(t *testing.T) {
+		// Given
+		key, _, err := auth.NewKey("ci", []string{auth.ScopeTasksRead}, nil)
+		assert.NoError(t, err)
+		limiter := ratelimit.New(ratelimit.WithIPLimit(ratelimit.Limit{Rate: 0.1, Burst: 1}))
+		server := api.NewServer(newUseCases(t), logging.Discard, 0, api.WithRateLimiter(limiter), api.WithAuthenticator(auth.New(auth.WithKeys(key))))
+
+		// When
+		first := get(server, "")
+		second := get(server, "")
+
+		// Then
+		assert.Equal(t, http.StatusUnauthorized, first)
+		assert.Equal(t, http.StatusTooManyRequests, second)
+	})
+}
+
+func TestServer_Admin(t *testing.T) {
+	// get envoie une requête sur /api/v1/admin/runtime avec la clé fournie
+	get := func(server *api.Server, key string) int {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/admin/runtime", nil)
+		if key != "" {
+			req.Header.Set(auth.KeyHeader, key)
+		}
+		rec := httptest.NewRecorder()
+		server.Handler().ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	t.Run("should not serve the admin endpoints without authentication", func(t *testing.T) {
+		// Given
+		server := api.NewServer(newUseCases(t), logging.Discard, 0, api.WithLogLevels(logging.NewLevels(logging.LevelInfo), nil))
+
+		// When
+		code := get(server, "")
+
+		// Then
+		assert.Equal(t, http.StatusNotFound, code)
+	})
+
+	t.Run("should serve the admin endpoints to the system:admin scope only", func(t *testing.T) {
+		// Given
+		admin, adminKey, err := auth.NewKey("ops", []string{auth.ScopeSystemAdmin}, nil)
+		assert.NoError(t, err)
+		viewer, viewerKey, err := auth.NewKey("viewer", []string{auth.ScopeTasksRead}, nil)
+		assert.NoError(t, err)
+		server := api.NewServer(newUseCases(t), logging.Discard, 0,
+			api.WithAuthenticator(auth.New(auth.WithKeys(admin, viewer))),
+			api.WithLogLevels(logging.NewLevels(logging.LevelInfo), nil),
+		)
+
+		// When
+		anonymous := get(server, "")
+		forbidden := get(server, viewerKey)
+		allowed := get(server, adminKey)
+
+		// Then
+		assert.Equal(t, http.StatusUnauthorized, anonymous)
+		assert.Equal(t, http.StatusForbidden, forbidden)
+		assert.Equal(t, http.StatusOK, allowed)
+	})
+}
@@ -0,0 +1,61 @@
+// Package authn authentifie les requêtes HTTP des transports réseau et audite les refus de scope
+// Les refus des transports ont lieu avant les cas d'usage, ils sont donc audités ici
+package authn
+
+import (
+	"context"
+	"errors"
+	"live-semantic/src/auth"
+	"live-semantic/src/domain/uc"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Middleware authentifie la requête et exige les scopes
+// Le principal est placé dans le contexte de la requête pour les handlers
+// Un authentificateur nil laisse passer toutes les requêtes, un auditeur nil n'audite pas les refus
+func Middleware(authenticator *auth.Authenticator, auditor uc.Auditor, source string, scopes ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if authenticator == nil {
+			c.Next()
+			return
+		}
+
+		principal, err := authenticator.Authenticate(auth.Credential(c.Request))
+		if err != nil {
+			status := http.StatusInternalServerError
+			if errors.Is(err, auth.ErrUnauthenticated) {
+				status = http.StatusUnauthorized
+				c.Header("WWW-Authenticate", `Bearer realm="live-semantic"`)
+			}
+			c.AbortWithStatusJSON(status, gin.H{"success": false, "error": err.Error(), "source": source})
+			return
+		}
+
+		ctx := auth.WithPrincipal(c.Request.Context(), principal)
+		for _, scope := range scopes {
+			if err := auth.Check(ctx, scope); err != nil {
+				Deny(ctx, auditor, source, c.Request.Method+" "+c.FullPath(), err)
+				c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"success": false, "error": err.Error(), "source": source})
+				return
+			}
+		}
+
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+	}
+}
+
+// Deny audite le refus de l'action au principal de ctx, reçu par le transport source
+func Deny(ctx context.Context, auditor uc.Auditor, source, action string, err error) {
+	if auditor == nil {
+		return
+	}
+	record := uc.NewAuditRecord(ctx, action, uc.OutcomeDenied)
+	if record.Source == "" {
+		record.Source = source
+	}
+	record.Reason = err.Error()
+	auditor.Record(ctx, record)
+}
@@ -0,0 +1,99 @@
+package authn_test
+
+import (
+	"context"
+	"live-semantic/src/auth"
+	"live-semantic/src/domain/uc"
+	"live-semantic/src/transport/authn"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+// recorder garde les enregistrements d'audit reçus
+type recorder struct {
+	records []uc.AuditRecord
+}
+
+func (r *recorder) Record(ctx context.Context, record uc.AuditRecord) {
+	r.records = append(r.records, record)
+}
+
+func TestMiddleware(t *testing.T) {
+	key, plain, _ := auth.NewKey("ci", []string{auth.ScopeTasksRead}, nil)
+
+	serve := func(authenticator *auth.Authenticator, auditor uc.Auditor, method string, header map[string]string) (*httptest.ResponseRecorder, auth.Principal) {
+		gin.SetMode(gin.TestMode)
+		router := gin.New()
+		var principal auth.Principal
+		router.GET("/tasks", authn.Middleware(authenticator, auditor, "web", auth.ScopeTasksRead), func(c *gin.Context) {
+			principal, _ = auth.PrincipalFrom(c.Request.Context())
+			c.Status(http.StatusOK)
+		})
+		router.POST("/tasks", authn.Middleware(authenticator, auditor, "web", auth.ScopeTasksWrite), func(c *gin.Context) {
+			c.Status(http.StatusCreated)
+		})
+
+		req := httptest.NewRequest(method, "/tasks", nil)
+		for name, value := range header {
+			req.Header.Set(name, value)
+		}
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec, principal
+	}
+
+	t.Run("should store the principal of a bearer key in the request context", func(t *testing.T) {
+		// When
+		rec, principal := serve(auth.New(auth.WithKeys(key)), nil, http.MethodGet, map[string]string{"Authorization": "Bearer " + plain})
+
+		// Then
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "ci", principal.Subject)
+	})
+
+	t.Run("should answer 401 without credentials and 403 without the scope", func(t *testing.T) {
+		// When
+		missing, _ := serve(auth.New(auth.WithKeys(key)), nil, http.MethodGet, nil)
+		forbidden, _ := serve(auth.New(auth.WithKeys(key)), nil, http.MethodPost, map[string]string{auth.KeyHeader: plain})
+
+		// Then
+		assert.Equal(t, http.StatusUnauthorized, missing.Code)
+		assert.NotEmpty(t, missing.Header().Get("WWW-Authenticate"))
+		assert.Equal(t, http.StatusForbidden, forbidden.Code)
+		assert.Contains(t, forbidden.Body.String(), "missing scope tasks:write")
+	})
+
+	t.Run("should audit a request denied for a missing scope", func(t *testing.T) {
+		// Given
+		auditor := &recorder{}
+
+		// When
+		_, _ = serve(auth.New(auth.WithKeys(key)), auditor, http.MethodPost, map[string]string{auth.KeyHeader: plain})
+		_, _ = serve(auth.New(auth.WithKeys(key)), auditor, http.MethodGet, map[string]string{auth.KeyHeader: plain})
+
+		// Then
+		if assert.Len(t, auditor.records, 1) {
+			record := auditor.records[0]
+			assert.Equal(t, "ci", record.Principal)
+			assert.Equal(t, "web", record.Source)
+			assert.Equal(t, "POST /tasks", record.Action)
+			assert.Equal(t, uc.OutcomeDenied, record.Outcome)
+			assert.Contains(t, record.Reason, "missing scope tasks:write")
+		}
+	})
+
+	t.Run("should let every request through when authentication is disabled", func(t *testing.T) {
+		// Given
+		var disabled *auth.Authenticator
+
+		// When
+		rec, _ := serve(disabled, nil, http.MethodGet, nil)
+
+		// Then
+		assert.Equal(t, http.StatusOK, rec.Code)
+	})
+}
@@ -2,6 +2,7 @@ package cli
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/AlecAivazis/survey/v2"
 )
@@ -33,18 +34,86 @@ func (s *SurveyController) showSettings() {
 	}
 }
 
+// allModules cible le niveau par défaut plutôt qu'un module
+const allModules = "All modules"
+
 func (s *SurveyController) configureLogLevel() {
-	var level string
-	prompt := &survey.Select{
-		Message: "Select log level:",
-		Options: []string{"DEBUG", "INFO", "WARN", "ERROR"},
-		Default: "INFO",
+	if s.logLevels == nil {
+		fmt.Println("⚠️ Log levels cannot be changed in this session")
+		return
 	}
 
-	if err := survey.AskOne(prompt, &level); err != nil {
+	var answers struct {
+		Module string `survey:"module"`
+		Level  string `survey:"level"`
+	}
+	qs := []*survey.Question{
+		{
+			Name: "module",
+			Prompt: &survey.Input{
+				Message: "Module (e.g. transport.websocket):",
+				Default: allModules,
+			},
+			Validate: survey.Required,
+		},
+		{
+			Name: "level",
+			Prompt: &survey.Select{
+				Message: "Select log level:",
+				Options: []string{"DEBUG", "INFO", "WARN", "ERROR", "DEFAULT"},
+				Default: strings.ToUpper(s.logLevels.Level().String()),
+				Description: func(value string, _ int) string {
+					if value == "DEFAULT" {
+						return "module only: follow the default level again"
+					}
+					return ""
+				},
+			},
+		},
+	}
+	if err := survey.Ask(qs, &answers); err != nil {
 		fmt.Printf("Error: %v\n", err)
 		return
 	}
 
-	fmt.Printf("✅ Log level set to: %s\n", level)
+	level := strings.ToLower(answers.Level)
+	var modules map[string]string
+	if module := strings.TrimSpace(answers.Module); module != allModules {
+		if level == "default" {
+			level = ""
+		}
+		modules = map[string]string{module: level}
+		level = ""
+	} else if level == "default" {
+		fmt.Println("⚠️ DEFAULT only applies to a module")
+		return
+	}
+
+	snapshot, err := s.logLevels.Update(level, modules)
+	if err != nil {
+		fmt.Printf("❌ Error: %v\n", err)
+		return
+	}
+	s.logger.Info("Log levels changed", map[string]interface{}{
+		"level":   snapshot.Level,
+		"modules": snapshot.Entries(),
+	})
+
+	fmt.Printf("✅ Default log level: %s\n", strings.ToUpper(snapshot.Level))
+	for _, entry := range snapshot.Entries() {
+		fmt.Printf("   • %s\n", entry)
+	}
+
+	if s.persistLogLevels == nil {
+		return
+	}
+	persist := false
+	if err := survey.AskOne(&survey.Confirm{Message: "Save to the configuration file?"}, &persist); err != nil || !persist {
+		return
+	}
+	if err := s.persistLogLevels(snapshot); err != nil {
+		fmt.Printf("❌ Error: %v\n", err)
+		return
+	}
+	fmt.Println("💾 Log levels saved")
 }
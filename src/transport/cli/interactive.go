@@ -3,19 +3,29 @@ package cli
 
 import (
 	"live-semantic/src/domain/uc"
+	"live-semantic/src/logging"
 	"live-semantic/src/transport"
 
 	"github.com/deadelus/go-clean-app/src/logger"
 )
 
 type SurveyController struct {
-	handler *transport.BaseHandler
-	logger  logger.Logger
+	handler          *transport.BaseHandler
+	logger           logger.Logger
+	logLevels        *logging.Levels
+	persistLogLevels logging.Persister
 }
 
-func NewSurveyController(useCases uc.UseCases, logger logger.Logger) *SurveyController {
+func NewSurveyController(useCases uc.UseCases, logger logger.Logger, options ...transport.HandlerOption) *SurveyController {
 	return &SurveyController{
-		handler: transport.NewBaseHandler(useCases, logger),
+		handler: transport.NewBaseHandler(useCases, logger, options...),
 		logger:  logger,
 	}
 }
+
+// SetLogLevels permet de modifier les niveaux de log depuis les réglages
+// persist écrit les niveaux dans la configuration lorsque l'utilisateur le demande, nil désactive la question
+func (s *SurveyController) SetLogLevels(levels *logging.Levels, persist logging.Persister) {
+	s.logLevels = levels
+	s.persistLogLevels = persist
+}
@@ -0,0 +1,42 @@
+package cmd
+
+import (
+	"live-semantic/src/config"
+	"live-semantic/src/lifecycle"
+	"live-semantic/src/logging"
+	"live-semantic/src/transport/api"
+)
+
+// components keeps the servers of the serve commands for the admin endpoints
+var components *lifecycle.Registry
+
+// newComponents creates the registry of the lifecycle components, with the queues of the use cases
+func newComponents(ucs any) *lifecycle.Registry {
+	registry := lifecycle.NewRegistry()
+	if queues, ok := ucs.(lifecycle.QueueProvider); ok {
+		registry.RegisterQueues(queues)
+	}
+	return registry
+}
+
+// adminOptions exposes the admin endpoints of the REST API when server.admin.enabled is on.
+// The log levels are persisted by the endpoint only with server.admin.persist_log_levels.
+func adminOptions(cfg *config.Config) []api.Option {
+	settings := cfg.Server.Admin
+	if !settings.Enabled {
+		return nil
+	}
+	if settings.Pprof {
+		appLogger.Warn("pprof profiles exposed under /api/v1/admin/debug/pprof")
+	}
+	var persist logging.Persister
+	if settings.PersistLogLevels {
+		persist = persistLoadedLogLevels
+	}
+	return []api.Option{
+		api.WithLogLevels(LogLevels, persist),
+		api.WithComponents(components),
+		api.WithConfig(watcher.Current),
+		api.WithProfiling(settings.Pprof),
+	}
+}
@@ -0,0 +1,72 @@
+package cmd
+
+import (
+	"context"
+	"live-semantic/src/audit"
+	"live-semantic/src/config"
+	"live-semantic/src/domain/uc"
+	"live-semantic/src/transport/requestid"
+)
+
+// Audited actions of the commands, the use cases audit their own actions
+const (
+	actionAPIKeyCreate = "apikey.create"
+	actionAPIKeyRevoke = "apikey.revoke"
+	actionTokenIssue   = "token.issue"
+)
+
+// auditLog records the mutating operations, nil when audit.enabled is off
+var auditLog *audit.Log
+
+// Auditor returns the audit log handed to the use cases by the bootstrap, nil when it is disabled:
+// the use cases then discard their audit trail.
+func Auditor() uc.Auditor {
+	if auditLog == nil {
+		return nil
+	}
+	return auditLog
+}
+
+// openAuditLog opens the audit log of the configuration before the bootstrap
+func openAuditLog(cfg *config.Config) error {
+	auditLog = nil
+	settings := cfg.Audit
+	if !settings.Enabled {
+		return nil
+	}
+
+	log, err := audit.Open(settings.Path,
+		audit.WithMaxSize(settings.MaxSize),
+		audit.WithMaxAge(settings.MaxAge),
+		audit.WithErrorHandler(func(err error) {
+			appLogger.Error("Failed to write the audit log", map[string]interface{}{
+				"error": err.Error(),
+			})
+		}),
+	)
+	if err != nil {
+		return err
+	}
+	auditLog = log
+	return nil
+}
+
+// auditor returns the auditor of the commands and servers: the audit log, or a discarding auditor
+func auditor() uc.Auditor {
+	if auditLog != nil {
+		return auditLog
+	}
+	return uc.DiscardAuditor
+}
+
+// recordCommand audits an action of a command on resource, a failure when err is set
+func recordCommand(action, resource string, err error) {
+	ctx := uc.WithRequest(context.Background(), uc.Request{ID: requestid.New(), Source: "cli"})
+	record := uc.NewAuditRecord(ctx, action, uc.OutcomeSuccess)
+	record.Resource = resource
+	if err != nil {
+		record.Outcome = uc.OutcomeFailure
+		record.Reason = err.Error()
+	}
+	auditor().Record(ctx, record)
+}
@@ -0,0 +1,265 @@
+package cmd
+
+import (
+	"fmt"
+	"live-semantic/src/auth"
+	"live-semantic/src/config"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// apiKeyView API key as listed, without its hash
+type apiKeyView struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	Scopes    string    `json:"scopes"`
+	Roles     string    `json:"roles"`
+	Origin    string    `json:"origin"`
+	Status    string    `json:"status"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// createdKey result of apikey create, the key is shown only once
+type createdKey struct {
+	ID     string `json:"id"`
+	Name   string `json:"name"`
+	Scopes string `json:"scopes"`
+	Roles  string `json:"roles"`
+	Key    string `json:"key"`
+	// ConfigEntry declares the key in auth.api_keys when it is not stored
+	ConfigEntry string `json:"config_entry,omitempty"`
+}
+
+// apiKeyCmd represents the apikey command
+var apiKeyCmd = &cobra.Command{
+	Use:   "apikey",
+	Short: "🔑 Manage API keys and bearer tokens",
+	Long: fmt.Sprintf(`Manage the API keys and bearer tokens accepted by the network transports when auth.enabled is set.
+
+Keys are stored hashed in auth.keys_file (storage.path/apikeys.json by default), a running
+server applies created and revoked keys without a restart. Available scopes: %s.
+Roles bundle scopes: viewer reads, analyst also creates tasks, operator is granted every scope.`, strings.Join(auth.Scopes, ", ")),
+}
+
+// apiKeyCreateCmd represents the apikey create subcommand
+var apiKeyCreateCmd = &cobra.Command{
+	Use:   "create [name]",
+	Short: "➕ Create an API key",
+	Long: `Create an API key with the given scopes and roles and print it once: only its hash is kept.
+
+With --no-store the key is not written to the key file, the printed config_entry
+declares it in auth.api_keys instead.`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: cobra.NoFileCompletions,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+
+		scopes, _ := cmd.Flags().GetStringSlice("scope")
+		roles, _ := cmd.Flags().GetStringSlice("role")
+		noStore, _ := cmd.Flags().GetBool("no-store")
+
+		key, plain, err := auth.NewKey(args[0], scopes, roles)
+		if err != nil {
+			return err
+		}
+
+		created := createdKey{
+			ID:     key.ID,
+			Name:   key.Name,
+			Scopes: strings.Join(key.Scopes, ","),
+			Roles:  strings.Join(key.Roles, ","),
+			Key:    plain,
+		}
+		if noStore {
+			created.ConfigEntry = key.ConfigEntry()
+		} else {
+			store := keyStore(appConfig)
+			err := store.Add(key)
+			recordCommand(actionAPIKeyCreate, key.ID, err)
+			if err != nil {
+				return err
+			}
+			printer.Success("api key %s stored in %s, copy it now: it is not shown again", key.ID, store.Path())
+		}
+		return printer.Print(created)
+	},
+}
+
+// apiKeyListCmd represents the apikey list subcommand
+var apiKeyListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "📋 List API keys",
+	Long:  `List the keys of the key file and of auth.api_keys, revoked ones included.`,
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+
+		views := []apiKeyView{}
+		for _, entry := range appConfig.Auth.APIKeys {
+			key, err := auth.ParseConfigEntry(entry)
+			if err != nil {
+				return err
+			}
+			views = append(views, newAPIKeyView(key, "config"))
+		}
+
+		stored, err := keyStore(appConfig).Keys()
+		if err != nil {
+			return err
+		}
+		for _, key := range stored {
+			views = append(views, newAPIKeyView(key, "store"))
+		}
+		return printer.Print(views)
+	},
+}
+
+// apiKeyRevokeCmd represents the apikey revoke subcommand
+var apiKeyRevokeCmd = &cobra.Command{
+	Use:               "revoke [id]",
+	Short:             "🚫 Revoke an API key",
+	Long:              `Revoke a key of the key file. Keys of auth.api_keys are revoked by removing them from the configuration.`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeAPIKeyIDs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+
+		for _, entry := range appConfig.Auth.APIKeys {
+			if key, err := auth.ParseConfigEntry(entry); err == nil && key.ID == args[0] {
+				return fmt.Errorf("api key %s is declared in auth.api_keys: remove it from the configuration", key.ID)
+			}
+		}
+
+		key, err := keyStore(appConfig).Revoke(args[0])
+		recordCommand(actionAPIKeyRevoke, args[0], err)
+		if err != nil {
+			return err
+		}
+		printer.Success("api key %s (%s) revoked", key.ID, key.Name)
+		return nil
+	},
+}
+
+// apiKeyTokenCmd represents the apikey token subcommand
+var apiKeyTokenCmd = &cobra.Command{
+	Use:   "token [subject]",
+	Short: "🎫 Issue a bearer token",
+	Long: `Issue a bearer token signed with auth.token_secret for the subject, scopes and roles.
+
+Tokens are not stored: they stay valid until they expire or the secret changes.`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: cobra.NoFileCompletions,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+
+		scopes, _ := cmd.Flags().GetStringSlice("scope")
+		roles, _ := cmd.Flags().GetStringSlice("role")
+		ttl, _ := cmd.Flags().GetDuration("ttl")
+		if ttl == 0 {
+			ttl = appConfig.Auth.TokenTTL
+		}
+		if appConfig.Auth.TokenSecret == "" {
+			return fmt.Errorf("auth.token_secret is not set: tokens are disabled")
+		}
+
+		token, err := auth.IssueToken([]byte(appConfig.Auth.TokenSecret), args[0], scopes, roles, ttl)
+		recordCommand(actionTokenIssue, args[0], err)
+		if err != nil {
+			return err
+		}
+		printer.Success("token valid until %s", time.Now().Add(ttl).Format(time.RFC3339))
+		return printer.Print(token)
+	},
+}
+
+// newAPIKeyView builds the listed view of a key
+func newAPIKeyView(key auth.Key, origin string) apiKeyView {
+	status := "active"
+	if key.Revoked() {
+		status = "revoked"
+	}
+	return apiKeyView{
+		ID:        key.ID,
+		Name:      key.Name,
+		Scopes:    strings.Join(key.Scopes, ","),
+		Roles:     strings.Join(key.Roles, ","),
+		Origin:    origin,
+		Status:    status,
+		CreatedAt: key.CreatedAt,
+	}
+}
+
+// keyStore returns the store of the keys managed by the apikey commands
+func keyStore(cfg *config.Config) *auth.FileStore {
+	path := cfg.Auth.KeysFile
+	if path == "" {
+		path = filepath.Join(cfg.Storage.Path, "apikeys.json")
+	}
+	return auth.NewFileStore(path)
+}
+
+// newAuthenticator builds the authenticator of the servers, nil when authentication is disabled
+func newAuthenticator(cfg *config.Config) (*auth.Authenticator, error) {
+	if !cfg.Auth.Enabled {
+		appLogger.Warn("Authentication disabled: every caller can reach the transports")
+		return nil, nil
+	}
+
+	var keys []auth.Key
+	for _, entry := range cfg.Auth.APIKeys {
+		key, err := auth.ParseConfigEntry(entry)
+		if err != nil {
+			return nil, fmt.Errorf("%w: auth.api_keys: %v", config.ErrInvalid, err)
+		}
+		keys = append(keys, key)
+	}
+
+	options := []auth.Option{auth.WithKeys(keys...), auth.WithStore(keyStore(cfg))}
+	if cfg.Auth.TokenSecret != "" {
+		options = append(options, auth.WithTokenSecret([]byte(cfg.Auth.TokenSecret)))
+	}
+
+	appLogger.Info("🔒 Authentication enabled", map[string]interface{}{
+		"config_keys": len(keys),
+		"keys_file":   keyStore(cfg).Path(),
+		"tokens":      cfg.Auth.TokenSecret != "",
+	})
+	return auth.New(options...), nil
+}
+
+// completeAPIKeyIDs completes the IDs of the stored keys
+func completeAPIKeyIDs(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	// Le bootstrap de la complétion charge aussi la configuration
+	if len(args) > 0 || completionUseCases() == nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	keys, err := keyStore(appConfig).Keys()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+	var completions []string
+	for _, key := range keys {
+		if !key.Revoked() && strings.HasPrefix(key.ID, toComplete) {
+			completions = append(completions, cobra.CompletionWithDesc(key.ID, key.Name))
+		}
+	}
+	return completions, cobra.ShellCompDirectiveNoFileComp
+}
+
+func init() {
+	rootCmd.AddCommand(apiKeyCmd)
+	apiKeyCmd.AddCommand(apiKeyCreateCmd, apiKeyListCmd, apiKeyRevokeCmd, apiKeyTokenCmd)
+
+	for _, c := range []*cobra.Command{apiKeyCreateCmd, apiKeyTokenCmd} {
+		c.Flags().StringSlice("scope", nil, fmt.Sprintf("scope to grant, repeatable %v", auth.Scopes))
+		c.Flags().StringSlice("role", nil, fmt.Sprintf("role to grant, repeatable %v", auth.Roles))
+		registerFlagCompletion(c, "scope", cobra.FixedCompletions(auth.Scopes, cobra.ShellCompDirectiveNoFileComp))
+		registerFlagCompletion(c, "role", cobra.FixedCompletions(auth.Roles, cobra.ShellCompDirectiveNoFileComp))
+	}
+	apiKeyCreateCmd.Flags().Bool("no-store", false, "print an auth.api_keys entry instead of storing the key")
+	apiKeyTokenCmd.Flags().Duration("ttl", 0, "lifetime of the token (0 uses auth.token_ttl)")
+}
@@ -0,0 +1,160 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"live-semantic/src/config"
+	"live-semantic/src/domain/dto"
+	"live-semantic/src/domain/uc"
+	"live-semantic/src/transport/output"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// completionTimeout bounds the use case calls of a completion request
+const completionTimeout = 2 * time.Second
+
+// completionCmd represents the completion command
+var completionCmd = &cobra.Command{
+	Use:   "completion bash|zsh|fish",
+	Short: "⌨️  Generate shell completion scripts",
+	Long: `Generate the completion script of live-semantic for the given shell.
+
+Besides commands and flags, the completion offers the task IDs, the predefined
+filters and the video sources, read from the local use cases or from the server
+given by --remote, and the values of --output, --profile, --log-level and --resolution.
+
+Bash:
+  source <(live-semantic completion bash)
+  # or once for all sessions
+  live-semantic completion bash > /etc/bash_completion.d/live-semantic
+
+Zsh:
+  live-semantic completion zsh > "${fpath[1]}/_live-semantic"
+
+Fish:
+  live-semantic completion fish > ~/.config/fish/completions/live-semantic.fish`,
+	Args:                  cobra.MatchAll(cobra.ExactArgs(1), cobra.OnlyValidArgs),
+	ValidArgs:             []string{"bash", "zsh", "fish"},
+	DisableFlagsInUseLine: true,
+	Annotations:           map[string]string{standaloneAnnotation: "true"},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		out := cmd.OutOrStdout()
+		switch args[0] {
+		case "bash":
+			return rootCmd.GenBashCompletionV2(out, true)
+		case "zsh":
+			return rootCmd.GenZshCompletion(out)
+		default:
+			return rootCmd.GenFishCompletion(out, true)
+		}
+	},
+}
+
+// completionUseCases returns the use cases queried by the completion functions.
+// Completion requests skip the bootstrap, so the use cases are built on first use
+// with the flags of the completed command line, --remote included.
+func completionUseCases() uc.UseCases {
+	if useCases != nil {
+		return useCases
+	}
+
+	cfg, err := config.Load(viper.GetViper(), cfgFile)
+	if err != nil {
+		return nil
+	}
+	if err := configureLogging(cfg); err != nil {
+		return nil
+	}
+	app, ucs, err := bootstrap(ModeCLI, cfg)
+	if err != nil {
+		return nil
+	}
+
+	appConfig = cfg
+	engine = app
+	useCases = ucs
+	appLogger = NewLogger(app.Logger())
+	return useCases
+}
+
+// completeList completes a value from a list use case, each item rendered as "value\tdescription"
+func completeList[T any](
+	list func(uc.UseCases, context.Context, dto.ListRequest) (dto.Result[[]T], error),
+	item func(T) (string, string),
+) cobra.CompletionFunc {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		useCases := completionUseCases()
+		if useCases == nil {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), completionTimeout)
+		defer cancel()
+
+		result, err := list(useCases, ctx, dto.ListRequest{Prefix: toComplete})
+		if err != nil || !result.Success || result.Data == nil {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+
+		completions := make([]string, 0, len(*result.Data))
+		for _, data := range *result.Data {
+			value, description := item(data)
+			completions = append(completions, cobra.CompletionWithDesc(value, description))
+		}
+		return completions, cobra.ShellCompDirectiveNoFileComp
+	}
+}
+
+// completeTaskIDs completes the first argument with the task IDs
+func completeTaskIDs(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	return completeList(uc.UseCases.ListTasks, func(t dto.TaskResponse) (string, string) {
+		return t.ID, t.Title
+	})(cmd, args, toComplete)
+}
+
+// completeFilters completes the predefined filter names
+var completeFilters = completeList(uc.UseCases.ListFilters, func(f dto.FilterResponse) (string, string) {
+	return f.Name, f.Category
+})
+
+// completeSources completes the video source IDs
+var completeSources = completeList(uc.UseCases.ListSources, func(s dto.SourceResponse) (string, string) {
+	return s.ID, s.Kind
+})
+
+// completeOutput completes --output, go-template= is left open for the template
+func completeOutput(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	var completions []string
+	for _, format := range []string{output.FormatTable, output.FormatJSON, output.FormatYAML, output.FormatJSONL, output.FormatTemplate + "="} {
+		if strings.HasPrefix(format, toComplete) {
+			completions = append(completions, format)
+		}
+	}
+
+	directive := cobra.ShellCompDirectiveNoFileComp
+	if strings.HasPrefix(toComplete, output.FormatTemplate+"=") ||
+		(len(completions) == 1 && completions[0] == output.FormatTemplate+"=") {
+		directive |= cobra.ShellCompDirectiveNoSpace
+	}
+	return completions, directive
+}
+
+// registerFlagCompletion registers the completion of a flag, failing at startup on a typo
+func registerFlagCompletion(cmd *cobra.Command, name string, fn cobra.CompletionFunc) {
+	if err := cmd.RegisterFlagCompletionFunc(name, fn); err != nil {
+		panic(fmt.Sprintf("registering completion of --%s: %v", name, err))
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(completionCmd)
+	// The completion command replaces the default one of cobra
+	rootCmd.CompletionOptions.DisableDefaultCmd = true
+}
@@ -0,0 +1,77 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"live-semantic/src/config"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+// exportConfigCmd represents the export-config command
+var exportConfigCmd = &cobra.Command{
+	Use:   "export-config",
+	Short: "📤 Export the effective configuration",
+	Long: `Export the effective configuration merged from defaults, profile, file, environment and flags.
+
+Secret values are redacted. With --template, a commented template of the defaults is
+generated from the configuration field documentation instead.
+
+The configuration is written in --format to stdout, or to --file. A structured global
+--output (json, yaml, jsonl or go-template) renders it through the output printer instead.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+
+		format, _ := cmd.Flags().GetString("format")
+		template, _ := cmd.Flags().GetBool("template")
+		file, _ := cmd.Flags().GetString("file")
+
+		// The global --output renders the exported values, a commented template has none
+		structured := file == "" && printer.Structured()
+		if structured {
+			if template {
+				return fmt.Errorf("--template cannot be rendered with --output %s, use --format", printer.Format())
+			}
+			format = "json"
+		}
+
+		var (
+			data []byte
+			err  error
+		)
+		if template {
+			data, err = config.Template(format)
+		} else {
+			data, err = config.Export(*appConfig, format)
+		}
+		if err != nil {
+			return err
+		}
+
+		if structured {
+			return printer.Print(json.RawMessage(data))
+		}
+		if file == "" {
+			_, err = cmd.OutOrStdout().Write(data)
+			return err
+		}
+
+		if err := os.WriteFile(file, data, 0o600); err != nil {
+			return fmt.Errorf("writing %s: %w", file, err)
+		}
+		printer.Success("Configuration exported to %s", file)
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(exportConfigCmd)
+
+	// Flags pour la commande export-config
+	exportConfigCmd.Flags().String("format", "yaml", fmt.Sprintf("Configuration format %v", config.Formats))
+	exportConfigCmd.Flags().Bool("template", false, "Export a commented template of the defaults")
+	exportConfigCmd.Flags().String("file", "", "Output file path (default stdout)")
+	registerFlagCompletion(exportConfigCmd, "format", cobra.FixedCompletions(config.Formats, cobra.ShellCompDirectiveNoFileComp))
+}
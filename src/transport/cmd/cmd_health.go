@@ -0,0 +1,133 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"live-semantic/src/health"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// healthCheckCmd represents the health-check command
+var healthCheckCmd = &cobra.Command{
+	Use:   "health-check",
+	Short: "🩺 Check system health and dependencies",
+	Long: `Run the health probes of storage, AI provider, video source and alert sinks.
+
+Only a failing storage makes the check fail, other failures degrade the system.
+The probes are the same as the ones aggregated by the /health endpoint of the servers.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+
+		detailed, _ := cmd.Flags().GetBool("detailed")
+		models, _ := cmd.Flags().GetBool("models")
+		fix, _ := cmd.Flags().GetBool("fix")
+		timeout, _ := cmd.Flags().GetDuration("timeout")
+
+		if fix {
+			actions, err := health.Fix(appConfig)
+			for _, action := range actions {
+				printer.Info("🔧 %s", action)
+			}
+			if err != nil {
+				return err
+			}
+		}
+
+		var include []string
+		if models {
+			include = append(include, health.ProbeModels)
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+
+		report := healthRegistry.Check(ctx, include...)
+		if err := printHealthReport(report, detailed); err != nil {
+			return err
+		}
+
+		if !report.Ready() {
+			return fmt.Errorf("health check failed")
+		}
+		return nil
+	},
+}
+
+// healthTable présente les composants d'un rapport sous forme de tableau
+// Sans --detailed, seuls les composants en échec sont listés
+type healthTable struct {
+	report   health.Report
+	detailed bool
+}
+
+// Header implements output.Tabular
+func (t healthTable) Header() []string {
+	header := []string{"COMPONENT", "STATUS", "CRITICAL", "DETAIL"}
+	if t.detailed {
+		header = append(header, "LATENCY")
+	}
+	return header
+}
+
+// Rows implements output.Tabular
+func (t healthTable) Rows() [][]string {
+	var rows [][]string
+	for _, component := range t.report.Components {
+		if !t.detailed && component.Status == health.StatusUp {
+			continue
+		}
+
+		var parts []string
+		if component.Detail != "" {
+			parts = append(parts, component.Detail)
+		}
+		if component.Error != "" {
+			parts = append(parts, component.Error)
+		}
+
+		row := []string{component.Name, string(component.Status), strconv.FormatBool(component.Critical), strings.Join(parts, ": ")}
+		if t.detailed {
+			row = append(row, component.Latency)
+		}
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+// printHealthReport affiche le statut global puis les composants
+// Les formats structurés reçoivent le rapport complet
+func printHealthReport(report health.Report, detailed bool) error {
+	if printer.Structured() {
+		return printer.Print(report)
+	}
+
+	switch report.Status {
+	case health.StatusUp:
+		printer.Success("System healthy")
+	case health.StatusDegraded:
+		printer.Warn("System degraded")
+	default:
+		printer.Info("❌ System unhealthy")
+	}
+
+	table := healthTable{report: report, detailed: detailed}
+	if len(table.Rows()) == 0 {
+		return nil
+	}
+	return printer.Print(table)
+}
+
+func init() {
+	rootCmd.AddCommand(healthCheckCmd)
+
+	// Flags pour la commande health-check
+	healthCheckCmd.Flags().Bool("detailed", false, "Show detailed component status")
+	healthCheckCmd.Flags().Bool("models", false, "Check AI model availability")
+	healthCheckCmd.Flags().Bool("fix", false, "Create the missing storage and model directories")
+	healthCheckCmd.Flags().Duration("timeout", 10*time.Second, "Overall time allowed to the probes")
+}
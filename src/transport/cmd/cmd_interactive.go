@@ -0,0 +1,34 @@
+package cmd
+
+import (
+	"live-semantic/src/transport"
+	"live-semantic/src/transport/cli"
+
+	"github.com/spf13/cobra"
+)
+
+// interactiveCmd represents the interactive command
+var interactiveCmd = &cobra.Command{
+	Use:         "interactive",
+	Aliases:     []string{"i"},
+	Short:       "💡 Start the interactive mode",
+	Long:        `Start the survey-based interactive menu.`,
+	Args:        cobra.NoArgs,
+	Annotations: map[string]string{modeAnnotation: string(ModeInteractive)},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+
+		appLogger.Info("💡 Starting in interactive mode")
+		controller := cli.NewSurveyController(useCases, appLogger.Named("transport.cli"), transport.WithMetrics(metricsRegistry), transport.WithTracer(tracer))
+		controller.SetLogLevels(LogLevels, persistLogLevels)
+		if err := controller.Run(); err != nil {
+			appLogger.Error("Interactive CLI failed", err)
+			return err
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(interactiveCmd)
+}
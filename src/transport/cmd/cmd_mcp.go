@@ -0,0 +1,51 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"live-semantic/src/transport/mcp"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/spf13/cobra"
+)
+
+// mcpCmd represents the mcp command
+var mcpCmd = &cobra.Command{
+	Use:   "mcp",
+	Short: "🤖 Run a Model Context Protocol server over stdio",
+	Long: `Serve the use cases to an MCP client (an AI assistant or an agent) over stdin and stdout.
+
+Each use case is exposed as a tool whose input schema is derived from its request DTO:
+
+  create_task, list_tasks, list_filters, list_sources, recent_events
+
+The tasks are published as the resource livesemantic://tasks. stdout only carries
+protocol messages, logs go to stderr.
+With --remote, the calls are forwarded to a running server.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+
+		maxLineSize, _ := cmd.Flags().GetInt("max-line-size")
+		recent, _ := cmd.Flags().GetInt("recent")
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+
+		server := mcp.New(useCases, appLogger.Named("transport.mcp"), mcp.WithMaxLineSize(maxLineSize), mcp.WithRecent(recent), mcp.WithMetrics(metricsRegistry), mcp.WithTracer(tracer))
+		err := server.Run(ctx, cmd.InOrStdin(), cmd.OutOrStdout())
+		if errors.Is(err, context.Canceled) {
+			return nil
+		}
+		return err
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(mcpCmd)
+
+	mcpCmd.Flags().Int("max-line-size", mcp.DefaultMaxLineSize, "Maximum size of a message in bytes")
+	mcpCmd.Flags().Int("recent", mcp.DefaultRecent, "Number of events returned by recent_events without last_event_id or recent")
+}
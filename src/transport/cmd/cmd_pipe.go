@@ -0,0 +1,53 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"live-semantic/src/transport/pipe"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/spf13/cobra"
+)
+
+// pipeCmd represents the pipe command
+var pipeCmd = &cobra.Command{
+	Use:   "pipe",
+	Short: "🔀 Process JSON Lines requests from stdin",
+	Long: `Read one JSON request per line from stdin and write one JSON response per line to stdout.
+
+Requests use the WebSocket envelope with an optional id, returned as is in the response:
+
+  {"id": 1, "type": "Task", "data": {"title": "Title", "name": "Description"}}
+  {"id": 2, "type": "ListFilters", "data": {"prefix": "person"}}
+
+Supported types are Task, ListTasks, ListFilters and ListSources. Requests are processed
+concurrently, responses are written as they complete. The command exits once stdin is
+closed and every response is written. Logs go to stderr.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+
+		concurrency, _ := cmd.Flags().GetInt("concurrency")
+		maxLineSize, _ := cmd.Flags().GetInt("max-line-size")
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+
+		p := pipe.New(useCases, appLogger.Named("transport.pipe"), pipe.WithConcurrency(concurrency), pipe.WithMaxLineSize(maxLineSize), pipe.WithMetrics(metricsRegistry), pipe.WithTracer(tracer))
+		err := p.Run(ctx, cmd.InOrStdin(), cmd.OutOrStdout())
+		if errors.Is(err, context.Canceled) {
+			// Interrompu par un signal, les requêtes en cours ont répondu
+			return nil
+		}
+		return err
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(pipeCmd)
+
+	pipeCmd.Flags().Int("concurrency", pipe.DefaultConcurrency, "Maximum number of requests processed at once")
+	pipeCmd.Flags().Int("max-line-size", pipe.DefaultMaxLineSize, "Maximum size of a request line in bytes")
+}
@@ -0,0 +1,465 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"live-semantic/src/auth"
+	"live-semantic/src/config"
+	"live-semantic/src/health"
+	"live-semantic/src/metrics"
+	"live-semantic/src/transport/api"
+	"live-semantic/src/transport/cors"
+	"live-semantic/src/transport/gateway"
+	"live-semantic/src/transport/ratelimit"
+	"live-semantic/src/transport/rpc"
+	"live-semantic/src/transport/websocket"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// defaultWebsocketPort port of the standalone WebSocket server
+const defaultWebsocketPort = 8081
+
+// defaultRPCPort port of the standalone JSON-RPC server
+const defaultRPCPort = 8082
+
+// stoppable serveur démarré par la commande serve
+type stoppable interface {
+	Start() error
+	Stop() error
+	SetShutdownTimeout(timeout time.Duration)
+}
+
+// serveCmd represents the serve command
+var serveCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "🌐 Serve REST, WebSocket and health on one listener",
+	Long: `Start the REST API, the WebSocket endpoint, the JSON-RPC endpoints and the health check on a single listener.
+
+Each component can be disabled, or moved to its own listener with --api-port, --ws-port or --rpc-port.
+Use "serve api", "serve ws" or "serve rpc" to run a single component.`,
+	Args:        cobra.NoArgs,
+	Annotations: map[string]string{modeAnnotation: string(ModeServer)},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+
+		servers, err := newServeServers(appConfig)
+		if err != nil {
+			return err
+		}
+		return runServers(servers)
+	},
+}
+
+// serveAPICmd represents the serve api subcommand
+var serveAPICmd = &cobra.Command{
+	Use:   "api",
+	Short: "🌐 Serve the REST API only",
+	Long:  `Start the REST API and its health check on a dedicated listener.`,
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+
+		port := appConfig.Server.API.Port
+		if port == 0 {
+			port = appConfig.Server.Port
+		}
+		appLogger.Info("🌐 Starting in Web API mode", map[string]interface{}{
+			"port": port,
+		})
+
+		t, err := newTransports(appConfig)
+		if err != nil {
+			return err
+		}
+		return runServers(map[string]stoppable{"api-server": t.api(port)})
+	},
+}
+
+// serveWSCmd represents the serve ws subcommand
+var serveWSCmd = &cobra.Command{
+	Use:     "ws",
+	Aliases: []string{"websocket"},
+	Short:   "🔗 Serve the WebSocket endpoint only",
+	Long:    `Start the WebSocket endpoint and its health check on a dedicated listener.`,
+	Args:    cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+
+		port := appConfig.Server.WebSocket.Port
+		if port == 0 {
+			port = defaultWebsocketPort
+		}
+		appLogger.Info("🔗 Starting in WebSocket mode", map[string]interface{}{
+			"port": port,
+		})
+
+		t, err := newTransports(appConfig)
+		if err != nil {
+			return err
+		}
+		return runServers(map[string]stoppable{"websocket-server": t.websocket(port)})
+	},
+}
+
+// serveRPCCmd represents the serve rpc subcommand
+var serveRPCCmd = &cobra.Command{
+	Use:   "rpc",
+	Short: "🔌 Serve the JSON-RPC endpoints only",
+	Long:  `Start the JSON-RPC 2.0 endpoints (POST /rpc and /rpc/ws) and their health check on a dedicated listener.`,
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+
+		port := appConfig.Server.RPC.Port
+		if port == 0 {
+			port = defaultRPCPort
+		}
+		appLogger.Info("🔌 Starting in JSON-RPC mode", map[string]interface{}{
+			"port": port,
+		})
+
+		t, err := newTransports(appConfig)
+		if err != nil {
+			return err
+		}
+		return runServers(map[string]stoppable{"rpc-server": t.rpc(port)})
+	},
+}
+
+// newServeServers builds the servers of the serve command: the gateway, with every enabled
+// component mounted on it unless the component has a listener of its own.
+func newServeServers(cfg *config.Config) (map[string]stoppable, error) {
+	server := cfg.Server
+	if !server.API.Enabled && !server.WebSocket.Enabled && !server.RPC.Enabled {
+		return nil, fmt.Errorf("at least one of --api, --ws or --rpc must be enabled")
+	}
+
+	t, err := newTransports(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	gw := t.gateway(server.Port)
+	servers := map[string]stoppable{"gateway": gw}
+	// mount monte le composant sur la passerelle, sauf s'il a son propre port
+	mount := func(name string, port int, component mountable) {
+		if port == 0 || port == server.Port {
+			gw.Mount(name, component)
+			return
+		}
+		servers[name+"-server"] = component
+	}
+
+	if server.API.Enabled {
+		mount("api", server.API.Port, t.api(server.API.Port))
+	}
+	if server.WebSocket.Enabled {
+		mount("websocket", server.WebSocket.Port, t.websocket(server.WebSocket.Port))
+	}
+	if server.RPC.Enabled {
+		mount("rpc", server.RPC.Port, t.rpc(server.RPC.Port))
+	}
+	return servers, nil
+}
+
+// mountable is a server that can also be mounted on the gateway.
+type mountable interface {
+	stoppable
+	gateway.Component
+}
+
+// transports holds the dependencies shared by the servers of a serve command. They are built
+// once so that the limits of a client apply across every transport of the process.
+type transports struct {
+	cfg           *config.Config
+	authenticator *auth.Authenticator
+	limiter       *ratelimit.Limiter
+	policy        *cors.Policy
+	collector     metrics.Collector
+}
+
+// newTransports builds the authenticator, the rate limiter and the origin policy of the servers.
+func newTransports(cfg *config.Config) (*transports, error) {
+	authenticator, err := newAuthenticator(cfg)
+	if err != nil {
+		return nil, err
+	}
+	// Un seul limiteur pour que les limites d'un client valent sur tous les transports
+	limiter, err := newRateLimiter(cfg)
+	if err != nil {
+		return nil, err
+	}
+	policy, err := newCORSPolicy(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return &transports{
+		cfg:           cfg,
+		authenticator: authenticator,
+		limiter:       limiter,
+		policy:        policy,
+		collector:     metricsCollector(cfg),
+	}, nil
+}
+
+// gateway creates the shared listener and registers it as a component.
+func (t *transports) gateway(port int) *gateway.Server {
+	server := t.cfg.Server
+	gw := gateway.NewServer(appLogger.Named("transport.gateway"), port,
+		gateway.WithHost(server.Host),
+		gateway.WithTrustedProxies(server.TrustedProxies),
+		gateway.WithShutdownTimeout(server.ShutdownTimeout),
+		gateway.WithHealth(healthRegistry),
+		gateway.WithCORS(t.policy),
+		gateway.WithMetrics(t.collector),
+		gateway.WithTracer(tracer),
+	)
+	components.Register("gateway", gw)
+	return gw
+}
+
+// api creates the REST API server, with the admin endpoints when they are enabled.
+func (t *transports) api(port int) *api.Server {
+	server := t.cfg.Server
+	options := append([]api.Option{
+		api.WithHost(server.Host),
+		api.WithTrustedProxies(server.TrustedProxies),
+		api.WithShutdownTimeout(server.ShutdownTimeout),
+		api.WithHealth(healthRegistry),
+		api.WithAuthenticator(t.authenticator),
+		api.WithAuditor(auditor()),
+		api.WithRateLimiter(t.limiter),
+		api.WithCORS(t.policy),
+		api.WithMetrics(t.collector),
+		api.WithTracer(tracer),
+	}, adminOptions(t.cfg)...)
+	apiServer := api.NewServer(useCases, appLogger.Named("transport.api"), port, options...)
+	components.Register("api", apiServer)
+	return apiServer
+}
+
+// websocket creates the WebSocket server.
+func (t *transports) websocket(port int) *websocket.Server {
+	server := t.cfg.Server
+	wsServer := websocket.NewServer(useCases, appLogger.Named("transport.websocket"), port,
+		websocket.WithHost(server.Host),
+		websocket.WithTrustedProxies(server.TrustedProxies),
+		websocket.WithShutdownTimeout(server.ShutdownTimeout),
+		websocket.WithHealth(healthRegistry),
+		websocket.WithAuthenticator(t.authenticator),
+		websocket.WithAuditor(auditor()),
+		websocket.WithRateLimiter(t.limiter),
+		websocket.WithCORS(t.policy),
+		websocket.WithMetrics(t.collector),
+		websocket.WithTracer(tracer),
+	)
+	components.Register("websocket", wsServer)
+	return wsServer
+}
+
+// rpc creates the JSON-RPC server.
+func (t *transports) rpc(port int) *rpc.Server {
+	server := t.cfg.Server
+	rpcServer := rpc.NewServer(useCases, appLogger.Named("transport.rpc"), port,
+		rpc.WithHost(server.Host),
+		rpc.WithTrustedProxies(server.TrustedProxies),
+		rpc.WithShutdownTimeout(server.ShutdownTimeout),
+		rpc.WithHealth(healthRegistry),
+		rpc.WithAuthenticator(t.authenticator),
+		rpc.WithAuditor(auditor()),
+		rpc.WithRateLimiter(t.limiter),
+		rpc.WithCORS(t.policy),
+		rpc.WithMetrics(t.collector),
+		rpc.WithTracer(tracer),
+	)
+	components.Register("rpc", rpcServer)
+	return rpcServer
+}
+
+// Metrics of the rate limiter
+const (
+	rateLimitClients   = "ratelimit_clients"
+	rateLimitInFlight  = "ratelimit_in_flight"
+	rateLimitDecisions = "ratelimit_decisions_total"
+)
+
+// newRateLimiter builds the per-client limiter of the network transports, nil when disabled.
+// Its state is reported by the rate-limit health probe and the ratelimit_* metrics.
+func newRateLimiter(cfg *config.Config) (*ratelimit.Limiter, error) {
+	settings := cfg.Server.RateLimit
+	if !settings.Enabled {
+		appLogger.Warn("Rate limiting disabled: clients are not throttled")
+		return nil, nil
+	}
+
+	options, err := rateLimitOptions(settings)
+	if err != nil {
+		return nil, err
+	}
+	limiter := ratelimit.New(options...)
+
+	// Les limites suivent le fichier de configuration, seule l'activation exige un redémarrage
+	watcher.Subscribe("server", "rate-limit", func(cfg *config.Config) error {
+		options, err := rateLimitOptions(cfg.Server.RateLimit)
+		if err != nil {
+			return err
+		}
+		limiter.Reconfigure(options...)
+		return nil
+	})
+
+	// Le limiteur compte lui-même, ses compteurs sont lus à chaque collecte
+	metricsRegistry.GaugeFunc(rateLimitClients, func() float64 { return float64(limiter.Stats().Clients) })
+	metricsRegistry.GaugeFunc(rateLimitInFlight, func() float64 { return float64(limiter.Stats().InFlight) })
+	for outcome, count := range map[string]func(ratelimit.Stats) uint64{
+		"allowed":   func(s ratelimit.Stats) uint64 { return s.Allowed },
+		"throttled": func(s ratelimit.Stats) uint64 { return s.Throttled },
+		"rejected":  func(s ratelimit.Stats) uint64 { return s.Rejected },
+	} {
+		metricsRegistry.CounterFunc(rateLimitDecisions, func() float64 { return float64(count(limiter.Stats())) }, metrics.Label{Name: "outcome", Value: outcome})
+	}
+
+	healthRegistry.Register("rate-limit", func(ctx context.Context) (string, error) {
+		stats := limiter.Stats()
+		return fmt.Sprintf("%d clients, %d in flight, %d allowed, %d throttled, %d rejected",
+			stats.Clients, stats.InFlight, stats.Allowed, stats.Throttled, stats.Rejected), nil
+	}, health.NonCritical())
+
+	appLogger.Info("🚦 Rate limiting enabled", map[string]interface{}{
+		"rate":          settings.Rate,
+		"burst":         settings.Burst,
+		"max_in_flight": settings.MaxInFlight,
+		"ip_rate":       settings.IPRate,
+		"ip_burst":      settings.IPBurst,
+		"overrides":     len(settings.Overrides),
+	})
+	return limiter, nil
+}
+
+// rateLimitOptions converts the rate limit settings into limiter options.
+func rateLimitOptions(settings config.RateLimitConfig) ([]ratelimit.Option, error) {
+	options := []ratelimit.Option{
+		ratelimit.WithLimit(ratelimit.Limit{Rate: settings.Rate, Burst: settings.Burst}),
+		ratelimit.WithIPLimit(ratelimit.Limit{Rate: settings.IPRate, Burst: settings.IPBurst}),
+		ratelimit.WithMaxInFlight(settings.MaxInFlight),
+	}
+	for _, override := range settings.Overrides {
+		key, limit, err := ratelimit.ParseOverride(override)
+		if err != nil {
+			return nil, fmt.Errorf("%w: server.rate_limit.overrides: %v", config.ErrInvalid, err)
+		}
+		options = append(options, ratelimit.WithOverride(key, limit))
+	}
+	return options, nil
+}
+
+// newCORSPolicy builds the browser origin policy of the REST API and of the WebSocket upgrades.
+func newCORSPolicy(cfg *config.Config) (*cors.Policy, error) {
+	settings := cfg.Server.CORS
+	for _, origin := range settings.AllowedOrigins {
+		if err := cors.ValidateOrigin(origin); err != nil {
+			return nil, fmt.Errorf("%w: server.cors.allowed_origins: %v", config.ErrInvalid, err)
+		}
+		if origin == cors.AnyOrigin && settings.AllowCredentials {
+			return nil, fmt.Errorf("%w: server.cors.allow_credentials: not allowed with the * origin", config.ErrInvalid)
+		}
+	}
+
+	if len(settings.AllowedOrigins) == 0 {
+		appLogger.Info("🌍 Cross-origin requests disabled: browsers must be served from the same origin")
+	} else {
+		appLogger.Info("🌍 Cross-origin requests allowed", map[string]interface{}{
+			"origins":     settings.AllowedOrigins,
+			"credentials": settings.AllowCredentials,
+		})
+	}
+
+	return cors.New(
+		cors.WithOrigins(settings.AllowedOrigins...),
+		cors.WithCredentials(settings.AllowCredentials),
+		cors.WithMaxAge(settings.MaxAge),
+		cors.WithTrustedProxies(cfg.Server.TrustedProxies),
+	), nil
+}
+
+// runServers enregistre chaque serveur auprès du cycle de vie puis les démarre
+// Elle retourne à la première erreur ou lorsque tous les serveurs sont arrêtés
+func runServers(servers map[string]stoppable) error {
+	// Le délai de drainage suit les rechargements de la configuration
+	watcher.Subscribe("server", "shutdown-timeout", func(cfg *config.Config) error {
+		for _, server := range servers {
+			server.SetShutdownTimeout(cfg.Server.ShutdownTimeout)
+		}
+		return nil
+	})
+
+	for name, server := range servers {
+		if err := engine.Gracefull().Register(name, server.Stop); err != nil {
+			return fmt.Errorf("failed to register %s for graceful shutdown: %w", name, err)
+		}
+	}
+
+	errs := make(chan error, len(servers))
+	for name, server := range servers {
+		go func(name string, server stoppable) {
+			if err := server.Start(); err != nil {
+				errs <- fmt.Errorf("%s failed: %w", name, err)
+				return
+			}
+			errs <- nil
+		}(name, server)
+	}
+
+	for range servers {
+		if err := <-errs; err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func init() {
+	rootCmd.AddCommand(serveCmd)
+	serveCmd.AddCommand(serveAPICmd)
+	serveCmd.AddCommand(serveWSCmd)
+	serveCmd.AddCommand(serveRPCCmd)
+
+	defaults := config.Default().Server
+
+	// Flags pour la commande serve
+	serveCmd.Flags().IntP("port", "p", defaults.Port, "Port of the shared listener")
+	serveCmd.Flags().Bool("api", defaults.API.Enabled, "Enable the REST API")
+	serveCmd.Flags().Bool("ws", defaults.WebSocket.Enabled, "Enable the WebSocket endpoint")
+	serveCmd.Flags().Int("api-port", 0, "Serve the REST API on its own listener (0 shares --port)")
+	serveCmd.Flags().Int("ws-port", 0, "Serve the WebSocket endpoint on its own listener (0 shares --port)")
+	serveCmd.Flags().Bool("rpc", defaults.RPC.Enabled, "Enable the JSON-RPC endpoints")
+	serveCmd.Flags().Int("rpc-port", 0, "Serve the JSON-RPC endpoints on their own listener (0 shares --port)")
+	serveCmd.Flags().Duration("shutdown-timeout", defaults.ShutdownTimeout, "Time allowed to drain in-flight requests and sockets on shutdown")
+	bindConfigFlag(serveCmd, "port", "server.port")
+	bindConfigFlag(serveCmd, "api", "server.api.enabled")
+	bindConfigFlag(serveCmd, "ws", "server.websocket.enabled")
+	bindConfigFlag(serveCmd, "api-port", "server.api.port")
+	bindConfigFlag(serveCmd, "ws-port", "server.websocket.port")
+	bindConfigFlag(serveCmd, "rpc", "server.rpc.enabled")
+	bindConfigFlag(serveCmd, "rpc-port", "server.rpc.port")
+	bindConfigFlag(serveCmd, "shutdown-timeout", "server.shutdown_timeout")
+
+	// Flags pour les sous-commandes serve api et serve ws
+	serveAPICmd.Flags().IntP("port", "p", 0, "Port to use for the server (0 uses server.port)")
+	serveAPICmd.Flags().Duration("shutdown-timeout", defaults.ShutdownTimeout, "Time allowed to drain in-flight requests on shutdown")
+	bindConfigFlag(serveAPICmd, "port", "server.api.port")
+	bindConfigFlag(serveAPICmd, "shutdown-timeout", "server.shutdown_timeout")
+
+	serveWSCmd.Flags().IntP("port", "p", 0, fmt.Sprintf("Port to use for the server (0 uses %d)", defaultWebsocketPort))
+	serveWSCmd.Flags().Duration("shutdown-timeout", defaults.ShutdownTimeout, "Time allowed to drain open sockets on shutdown")
+	bindConfigFlag(serveWSCmd, "port", "server.websocket.port")
+	bindConfigFlag(serveWSCmd, "shutdown-timeout", "server.shutdown_timeout")
+
+	serveRPCCmd.Flags().IntP("port", "p", 0, fmt.Sprintf("Port to use for the server (0 uses %d)", defaultRPCPort))
+	serveRPCCmd.Flags().Duration("shutdown-timeout", defaults.ShutdownTimeout, "Time allowed to drain in-flight calls and sockets on shutdown")
+	bindConfigFlag(serveRPCCmd, "port", "server.rpc.port")
+	bindConfigFlag(serveRPCCmd, "shutdown-timeout", "server.shutdown_timeout")
+}
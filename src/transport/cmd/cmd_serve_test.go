@@ -0,0 +1,88 @@
+package cmd
+
+import (
+	"live-semantic/src/config"
+	"live-semantic/src/domain/uc"
+	"live-semantic/src/health"
+	"live-semantic/src/logging"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// setupServe prepares the globals set by the root command before a serve command runs
+func setupServe(t *testing.T) *config.Config {
+	t.Helper()
+
+	cfg := config.Default()
+	// The limiter registers process-wide metrics, it is covered by the ratelimit package
+	cfg.Server.RateLimit.Enabled = false
+
+	ucs, err := uc.NewUseCase(logging.Discard)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	appLogger = logging.New(logging.Discard, logging.NewLevels(logging.LevelInfo))
+	useCases = ucs
+	healthRegistry = health.NewRegistry()
+	components = newComponents(ucs)
+	return &cfg
+}
+
+// names returns the sorted keys of the servers
+func names(servers map[string]stoppable) []string {
+	keys := make([]string, 0, len(servers))
+	for name := range servers {
+		keys = append(keys, name)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func TestNewServeServers(t *testing.T) {
+	t.Run("should mount every component on the gateway and register them", func(t *testing.T) {
+		// Given
+		cfg := setupServe(t)
+
+		// When
+		servers, err := newServeServers(cfg)
+
+		// Then
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"gateway"}, names(servers))
+		var registered []string
+		for _, status := range components.Components() {
+			registered = append(registered, status.Name)
+		}
+		assert.Equal(t, []string{"gateway", "api", "websocket", "rpc"}, registered)
+	})
+
+	t.Run("should serve a component with a port of its own on its own listener", func(t *testing.T) {
+		// Given
+		cfg := setupServe(t)
+		cfg.Server.WebSocket.Port = cfg.Server.Port + 1
+		cfg.Server.RPC.Enabled = false
+
+		// When
+		servers, err := newServeServers(cfg)
+
+		// Then
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"gateway", "websocket-server"}, names(servers))
+	})
+
+	t.Run("should refuse to serve without any component", func(t *testing.T) {
+		// Given
+		cfg := setupServe(t)
+		cfg.Server.API.Enabled = false
+		cfg.Server.WebSocket.Enabled = false
+		cfg.Server.RPC.Enabled = false
+
+		// When
+		_, err := newServeServers(cfg)
+
+		// Then
+		assert.Error(t, err)
+	})
+}
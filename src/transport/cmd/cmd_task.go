@@ -2,9 +2,12 @@ package cmd
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"live-semantic/src/config"
 	"live-semantic/src/domain/dto"
 	"live-semantic/src/transport"
+	"slices"
 
 	"github.com/spf13/cobra"
 )
@@ -22,18 +25,32 @@ var createCmd = &cobra.Command{
 	Short: "➕ Create task",
 	Long:  `Create an task with the specified title and description.`,
 	Args:  cobra.ExactArgs(2),
-	Run: func(cmd *cobra.Command, args []string) {
+	// Le titre et la description sont libres
+	ValidArgsFunction: cobra.NoFileCompletions,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+
 		title := args[0]
 		description := args[1]
+		source, _ := cmd.Flags().GetString("source")
+		filters, _ := cmd.Flags().GetStringArray("filter")
+		resolution, _ := cmd.Flags().GetString("resolution")
+
+		if resolution != "" && !slices.Contains(config.Resolutions, resolution) {
+			return fmt.Errorf("invalid resolution %q (available: %v)", resolution, config.Resolutions)
+		}
 
 		// Créer le handler de base
-		baseHandler := transport.NewBaseHandler(useCases, appLogger)
+		baseHandler := transport.NewBaseHandler(useCases, appLogger.Named("transport.cli"), transport.WithMetrics(metricsRegistry), transport.WithTracer(tracer))
 
 		// Créer la requête transport
 		req := transport.TransportRequest[dto.TaskRequest]{
 			Data: dto.TaskRequest{
 				Title:       title,
 				Description: description,
+				Source:      source,
+				Filters:     filters,
+				Resolution:  resolution,
 			},
 			Context: context.Background(),
 			Source:  "cli",
@@ -41,25 +58,90 @@ var createCmd = &cobra.Command{
 
 		// Exécuter le handler
 		response := baseHandler.HandleTask(req)
+		if !response.Success {
+			return errors.New(response.Error)
+		}
+
+		// Afficher le résultat dans le format demandé
+		printer.Success("task created successfully!")
+		return printer.Print(response.Data)
+	},
+}
+
+// listCmd represents the list subcommand
+var listCmd = &cobra.Command{
+	Use:   "list",
+	Short: "📋 List tasks",
+	Long: `List the tasks, most recent first.
+
+Tasks are kept in memory by the process that created them: use --remote to list
+the tasks of a running server.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+
+		prefix, _ := cmd.Flags().GetString("prefix")
+		limit, _ := cmd.Flags().GetInt("limit")
 
-		// Afficher le résultat
-		if response.Success {
-			fmt.Printf("✅ task created successfully!\n")
-			fmt.Printf("   ID: %s\n", response.Data.ID)
-			fmt.Printf("   Title: %s\n", response.Data.Title)
-			fmt.Printf("   Description: %s\n", response.Data.Description)
-			fmt.Printf("   Created At: %s\n", response.Data.CreatedAt.Format("2006-01-02 15:04:05"))
-		} else {
-			fmt.Printf("❌ Error: %s\n", response.Error)
+		tasks, err := listTasks(dto.ListRequest{Prefix: prefix, Limit: limit})
+		if err != nil {
+			return err
 		}
+		return printer.Print(tasks)
 	},
 }
 
+// getCmd represents the get subcommand
+var getCmd = &cobra.Command{
+	Use:               "get [id]",
+	Short:             "🔎 Show a task",
+	Long:              `Show the task with the specified ID.`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeTaskIDs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+
+		tasks, err := listTasks(dto.ListRequest{Prefix: args[0]})
+		if err != nil {
+			return err
+		}
+		for _, task := range tasks {
+			if task.ID == args[0] {
+				return printer.Print(task)
+			}
+		}
+		return fmt.Errorf("task %q not found", args[0])
+	},
+}
+
+// listTasks liste les tâches via le handler de base
+func listTasks(data dto.ListRequest) ([]dto.TaskResponse, error) {
+	baseHandler := transport.NewBaseHandler(useCases, appLogger.Named("transport.cli"), transport.WithMetrics(metricsRegistry), transport.WithTracer(tracer))
+
+	response := baseHandler.HandleListTasks(transport.TransportRequest[dto.ListRequest]{
+		Data:    data,
+		Context: context.Background(),
+		Source:  "cli",
+	})
+	if !response.Success {
+		return nil, errors.New(response.Error)
+	}
+	return *response.Data, nil
+}
+
 // Execute executes the root command
 func init() {
 	rootCmd.AddCommand(taskCmd)
-	taskCmd.AddCommand(createCmd)
+	taskCmd.AddCommand(createCmd, listCmd, getCmd)
+
+	createCmd.Flags().String("source", "", "video source of the task, e.g. cam0")
+	createCmd.Flags().StringArray("filter", nil, "semantic filter to apply, repeatable")
+	createCmd.Flags().String("resolution", "", fmt.Sprintf("capture resolution %v", config.Resolutions))
+	registerFlagCompletion(createCmd, "source", completeSources)
+	registerFlagCompletion(createCmd, "filter", completeFilters)
+	registerFlagCompletion(createCmd, "resolution", cobra.FixedCompletions(config.Resolutions, cobra.ShellCompDirectiveNoFileComp))
 
-	// Flags pour la commande create
-	createCmd.Flags().Bool("verbose", false, "Verbose output")
+	listCmd.Flags().String("prefix", "", "only list the task IDs starting with the prefix")
+	listCmd.Flags().Int("limit", 0, "maximum number of tasks, 0 for all")
+	registerFlagCompletion(listCmd, "prefix", completeTaskIDs)
 }
@@ -0,0 +1,81 @@
+package cmd
+
+import (
+	"errors"
+	"live-semantic/src/config"
+	"live-semantic/src/logging"
+
+	"github.com/deadelus/go-clean-app/src/logger"
+	"github.com/spf13/viper"
+)
+
+// LogLevels holds the log levels of the process, set from logging.level and logging.modules,
+// then adjusted by the config hot reload, the interactive settings and the admin endpoint.
+var LogLevels = logging.NewLevels(logging.LevelInfo)
+
+// logRedactor masks the personal data of the log fields, nil when logging.redaction is disabled
+var logRedactor *logging.Redactor
+
+// NewLogger wraps the engine logger with the log levels and the redaction of the process.
+// The bootstrap uses it for the loggers it hands to the use cases.
+func NewLogger(base logger.Logger) *logging.Logger {
+	return logging.New(base, LogLevels, logging.WithRedactor(logRedactor))
+}
+
+// configureLogging applies the log levels and builds the redactor before the bootstrap
+func configureLogging(cfg *config.Config) error {
+	if err := applyLogLevels(cfg); err != nil {
+		return err
+	}
+
+	logRedactor = nil
+	settings := cfg.Logging.Redaction
+	if !settings.Enabled {
+		return nil
+	}
+	patterns, err := logging.ParsePatterns(settings.Patterns)
+	if err != nil {
+		return err
+	}
+	logRedactor = logging.NewRedactor(
+		logging.WithFields(settings.Fields...),
+		logging.WithPatterns(patterns...),
+		logging.WithMaxLength(settings.MaxLength),
+		logging.WithHashKey([]byte(settings.HashKey)),
+	)
+	return nil
+}
+
+// applyLogLevels sets the log levels of the configuration
+func applyLogLevels(cfg *config.Config) error {
+	modules, err := logging.ParseModules(cfg.Logging.Modules)
+	if err != nil {
+		return err
+	}
+	return LogLevels.Apply(logging.Snapshot{Level: cfg.Logging.Level, Modules: modules})
+}
+
+// persistLogLevels writes the log levels to the config file so that they survive a restart
+func persistLogLevels(snapshot logging.Snapshot) error {
+	path, err := config.Persist(viper.GetViper(), map[string]any{
+		"logging.level":   snapshot.Level,
+		"logging.modules": snapshot.Entries(),
+	})
+	if err != nil {
+		return err
+	}
+
+	appLogger.Info("Log levels persisted", map[string]interface{}{
+		"file": path,
+	})
+	return nil
+}
+
+// persistLoadedLogLevels persists the log levels only to the config file in use, so that a
+// remote admin cannot create a config file that the process did not load.
+func persistLoadedLogLevels(snapshot logging.Snapshot) error {
+	if viper.ConfigFileUsed() == "" {
+		return errors.New("no config file in use")
+	}
+	return persistLogLevels(snapshot)
+}
@@ -0,0 +1,66 @@
+package cmd
+
+import (
+	"context"
+	"io"
+	"live-semantic/src/config"
+	"live-semantic/src/metrics"
+	"live-semantic/src/transport"
+	"live-semantic/src/transport/websocket"
+)
+
+// metricsNamespace prefixes the exposed metric names
+const metricsNamespace = "livesemantic"
+
+var (
+	// metricsRegistry collects the measures of every component of the process
+	metricsRegistry = newMetricsRegistry()
+	// stopReporter stops the console reporter and prints its last report
+	stopReporter func()
+)
+
+// newMetricsRegistry creates the registry shared by the components of the process
+func newMetricsRegistry() *metrics.Registry {
+	registry := metrics.NewRegistry(metrics.WithNamespace(metricsNamespace))
+	for name, help := range map[string]string{
+		transport.UseCaseCalls:      "Use case calls by operation, source and outcome.",
+		transport.UseCaseDuration:   "Use case call duration by operation and source.",
+		metrics.HTTPRequests:        "HTTP requests by method, route and status.",
+		metrics.HTTPRequestDuration: "HTTP request duration by method and route.",
+		websocket.WebSocketSessions: "Open WebSocket sessions by server.",
+		websocket.WebSocketMessages: "WebSocket messages received by type.",
+		rateLimitClients:            "Clients tracked by the rate limiter.",
+		rateLimitInFlight:           "Use case calls in flight under the concurrency limit.",
+		rateLimitDecisions:          "Rate limiter decisions by outcome.",
+	} {
+		registry.Describe(name, help)
+	}
+	return registry
+}
+
+// metricsCollector returns the collector of the servers, which discards the measures when metrics are disabled
+func metricsCollector(cfg *config.Config) metrics.Collector {
+	if !cfg.Metrics.Enabled {
+		return metrics.Discard
+	}
+	return metricsRegistry
+}
+
+// startConsoleReporter prints the metrics to out while the command runs when metrics.console is set.
+// The returned function stops the periodic reports and prints the final one.
+func startConsoleReporter(cfg *config.Config, out io.Writer) func() {
+	if !cfg.Metrics.Console {
+		return func() {}
+	}
+
+	reporter := metrics.NewConsoleReporter(metricsRegistry, out)
+	ctx, cancel := context.WithCancel(context.Background())
+	if interval := cfg.Metrics.ConsoleInterval; interval > 0 {
+		go reporter.Run(ctx, interval)
+	}
+
+	return func() {
+		cancel()
+		_ = reporter.Report()
+	}
+}
@@ -1,22 +1,68 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
+	"live-semantic/src/config"
 	"live-semantic/src/domain/uc"
+	"live-semantic/src/health"
+	"live-semantic/src/logging"
+	"live-semantic/src/transport/output"
 	"os"
 
-	"github.com/deadelus/go-clean-app/src/logger"
+	"github.com/deadelus/go-clean-app/src/application"
+	"github.com/mattn/go-isatty"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 )
 
+// Mode determines which logger the engine is built with
+type Mode string
+
+const (
+	// ModeCLI console-friendly logger for one-shot commands
+	ModeCLI Mode = "cli"
+	// ModeServer structured logger for long-running servers
+	ModeServer Mode = "server"
+	// ModeInteractive logger for the interactive survey
+	ModeInteractive Mode = "interactive"
+)
+
+const (
+	// exitError general error exit code
+	exitError = 1
+	// exitConfigError configuration error exit code
+	exitConfigError = 2
+)
+
+// modeAnnotation annotation key used by subcommands to declare their mode
+const modeAnnotation = "mode"
+
+// standaloneAnnotation annotation key of the commands that run without configuration nor use cases
+const standaloneAnnotation = "standalone"
+
+// Bootstrap builds the engine and the use cases for the chosen mode
+type Bootstrap func(mode Mode, cfg *config.Config) (application.Application, uc.UseCases, error)
+
 var (
-	cfgFile   string
-	useCases  uc.UseCases
-	appLogger logger.Logger
-	verbose   bool
+	cfgFile        string
+	bootstrap      Bootstrap
+	engine         application.Application
+	appConfig      *config.Config
+	watcher        *config.Watcher
+	healthRegistry *health.Registry
+	useCases       uc.UseCases
+	appLogger      *logging.Logger
+	verbose        bool
+	outputFormat   string
+	quiet          bool
+	noColor        bool
+	printer        *output.Printer
 )
 
+// flagBindings maps each command's flags to configuration keys
+var flagBindings = map[*cobra.Command]map[string]string{}
+
 // rootCmd represents the base command
 var rootCmd = &cobra.Command{
 	Use:   "live-semantic",
@@ -26,49 +72,169 @@ var rootCmd = &cobra.Command{
 Built with ❤️  using Go, Cobra, and Clean Architecture principles.
 Supports CLI, Web API, and WebSocket modes.`,
 	Version: "1.0.0",
+	// Execute prints the returned error once
+	SilenceErrors: true,
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		p, err := newPrinter(cmd)
+		if err != nil {
+			return err
+		}
+		printer = p
+
+		// Completion requests build the use cases on demand, see completionUseCases
+		if standalone(cmd) {
+			return nil
+		}
+
+		// Share the executing command's flags with the viper config
+		for name, key := range flagBindings[cmd] {
+			if err := viper.BindPFlag(key, cmd.Flags().Lookup(name)); err != nil {
+				return fmt.Errorf("error binding %s flag: %w", name, err)
+			}
+		}
+
+		cfg, err := config.Load(viper.GetViper(), cfgFile)
+		if err != nil {
+			cmd.SilenceUsage = true
+			return err
+		}
+		if viper.ConfigFileUsed() != "" && !quiet {
+			fmt.Fprintln(os.Stderr, "Using config file:", viper.ConfigFileUsed())
+		}
+
+		// The levels and the redaction apply to the loggers built by the bootstrap
+		if err := configureLogging(cfg); err != nil {
+			return err
+		}
+		// The use cases record their mutating operations in the audit log
+		if err := openAuditLog(cfg); err != nil {
+			return err
+		}
+
+		mode := commandMode(cmd)
+		app, uc, err := bootstrap(mode, cfg)
+		if err != nil {
+			return err
+		}
+
+		appConfig = cfg
+		engine = app
+		useCases = uc
+		appLogger = NewLogger(app.Logger())
+
+		// Long-running modes pick up config file changes without a restart
+		watcher = config.NewWatcher(viper.GetViper(), cfg, appLogger.Named("config"))
+		watcher.Subscribe("logging", "log-levels", applyLogLevels)
+		if mode != ModeCLI {
+			watcher.Start()
+		}
+
+		healthRegistry = health.NewRegistry()
+		health.RegisterProbes(healthRegistry, watcher.Current)
+		components = newComponents(uc)
+
+		tracer, err = newTracer(cfg, traceOutput(cmd))
+		if err != nil {
+			return err
+		}
+
+		// Servers expose their metrics on /metrics, the other modes can print them on exit
+		if mode != ModeServer {
+			stopReporter = startConsoleReporter(cfg, cmd.ErrOrStderr())
+		}
+		return nil
+	},
 }
 
 // Execute executes the root command
-func Execute(uc uc.UseCases, logger logger.Logger) {
-	useCases = uc
-	appLogger = logger
+func Execute(b Bootstrap) {
+	bootstrap = b
+
+	err := rootCmd.Execute()
+	if stopReporter != nil {
+		stopReporter()
+	}
+	stopTracer()
 
-	if err := rootCmd.Execute(); err != nil {
-		fmt.Println(err)
-		os.Exit(1)
+	if err != nil {
+		if printer != nil {
+			printer.Error(err)
+		} else {
+			fmt.Fprintln(os.Stderr, "Error:", err)
+		}
+		if errors.Is(err, config.ErrInvalid) {
+			os.Exit(exitConfigError)
+		}
+		os.Exit(exitError)
 	}
 }
 
-// Initialize the root command
-func init() {
-	cobra.OnInitialize(initConfig)
+// newPrinter creates the printer of the global output flags
+// Colors are disabled by --no-color, $NO_COLOR or when stdout is not a terminal
+func newPrinter(cmd *cobra.Command) (*output.Printer, error) {
+	color := !noColor && os.Getenv("NO_COLOR") == "" && isatty.IsTerminal(os.Stdout.Fd())
+	return output.New(cmd.OutOrStdout(), cmd.ErrOrStderr(), outputFormat,
+		output.WithQuiet(quiet),
+		output.WithColor(color),
+	)
+}
 
-	// Global flags
-	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default is $HOME/.live-semantic.yaml)")
-	rootCmd.PersistentFlags().BoolVarP(&verbose, "verbose", "v", false, "verbose output")
+// bindConfigFlag binds a command flag to a configuration key when the command runs
+func bindConfigFlag(cmd *cobra.Command, name, key string) {
+	if flagBindings[cmd] == nil {
+		flagBindings[cmd] = map[string]string{}
+	}
+	flagBindings[cmd][name] = key
+}
 
-	// Bind flags to viper
-	if err := viper.BindPFlag("verbose", rootCmd.PersistentFlags().Lookup("verbose")); err != nil {
-		fmt.Println("Error binding verbose flag:", err)
-		os.Exit(1)
+// standalone reports a command that skips the bootstrap
+func standalone(cmd *cobra.Command) bool {
+	switch cmd.Name() {
+	case cobra.ShellCompRequestCmd, cobra.ShellCompNoDescRequestCmd:
+		return true
 	}
+	return cmd.Annotations[standaloneAnnotation] == "true"
 }
 
-// initConfig reads in config file and ENV variables if set.
-func initConfig() {
-	if cfgFile != "" {
-		viper.SetConfigFile(cfgFile)
-	} else {
-		home, err := os.UserHomeDir()
-		cobra.CheckErr(err)
-		viper.AddConfigPath(home)
-		viper.SetConfigType("yaml")
-		viper.SetConfigName(".live-semantic")
+// commandMode returns the mode declared by the command or its closest parent
+func commandMode(cmd *cobra.Command) Mode {
+	for c := cmd; c != nil; c = c.Parent() {
+		if mode, ok := c.Annotations[modeAnnotation]; ok {
+			return Mode(mode)
+		}
 	}
+	return ModeCLI
+}
 
-	viper.AutomaticEnv()
+// Initialize the root command
+func init() {
+	// Global flags
+	rootCmd.PersistentFlags().StringVarP(&cfgFile, "config", "c", "", "config file (default is $LIVESEMANTIC_CONFIG or $HOME/.live-semantic.yaml)")
+	rootCmd.PersistentFlags().BoolVarP(&verbose, "verbose", "v", false, "verbose output")
+	rootCmd.PersistentFlags().String("profile", config.ProfileLocal, fmt.Sprintf("configuration profile %v", config.Profiles()))
+	rootCmd.PersistentFlags().String("log-level", "", "log level (debug|info|warn|error)")
+	rootCmd.PersistentFlags().StringVarP(&outputFormat, "output", "o", output.FormatTable, fmt.Sprintf("output format %v", output.Formats))
+	rootCmd.PersistentFlags().BoolVarP(&quiet, "quiet", "q", false, "suppress non-essential output")
+	rootCmd.PersistentFlags().BoolVar(&noColor, "no-color", false, "disable colored output")
+	rootCmd.PersistentFlags().String("remote", "", "run commands against a running server, e.g. http://host:8080")
+	rootCmd.PersistentFlags().String("token", "", "bearer token sent to the remote server")
+	rootCmd.PersistentFlags().Bool("metrics", false, "print a metrics summary to stderr when the command ends")
+	registerFlagCompletion(rootCmd, "output", completeOutput)
+	registerFlagCompletion(rootCmd, "profile", cobra.FixedCompletions(config.Profiles(), cobra.ShellCompDirectiveNoFileComp))
+	registerFlagCompletion(rootCmd, "log-level", cobra.FixedCompletions(config.LogLevels, cobra.ShellCompDirectiveNoFileComp))
 
-	if err := viper.ReadInConfig(); err == nil {
-		fmt.Println("Using config file:", viper.ConfigFileUsed())
+	// Bind flags to viper
+	for name, key := range map[string]string{
+		"verbose":   "verbose",
+		"profile":   "profile",
+		"log-level": "logging.level",
+		"remote":    "remote.url",
+		"token":     "remote.token",
+		"metrics":   "metrics.console",
+	} {
+		if err := viper.BindPFlag(key, rootCmd.PersistentFlags().Lookup(name)); err != nil {
+			fmt.Printf("Error binding %s flag: %v\n", name, err)
+			os.Exit(exitError)
+		}
 	}
 }
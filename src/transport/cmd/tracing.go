@@ -0,0 +1,63 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"live-semantic/src/config"
+	"live-semantic/src/tracing"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+// serviceName names the process in the exported traces
+const serviceName = "live-semantic"
+
+// tracer records the traces of the running command, nil when tracing is disabled
+var tracer *tracing.Tracer
+
+// newTracer builds the tracer of the process, nil when tracing is disabled.
+// The stdout exporter writes to out, the file exporter appends to tracing.path.
+func newTracer(cfg *config.Config, out io.Writer) (*tracing.Tracer, error) {
+	settings := cfg.Tracing
+	if !settings.Enabled {
+		return nil, nil
+	}
+
+	var exporter tracing.Exporter
+	if settings.Exporter == "stdout" {
+		exporter = tracing.NewOTLPWriter(out, serviceName)
+	} else {
+		writer, err := tracing.OpenOTLPFile(settings.Path, serviceName)
+		if err != nil {
+			return nil, fmt.Errorf("tracing: %w", err)
+		}
+		exporter = writer
+	}
+
+	return tracing.New(
+		tracing.WithExporter(exporter),
+		tracing.WithSampleRatio(settings.SampleRatio),
+		tracing.WithErrorHandler(func(err error) {
+			appLogger.Warn("Failed to export spans", map[string]interface{}{
+				"error": err.Error(),
+			})
+		}),
+	), nil
+}
+
+// traceOutput returns the destination of the stdout exporter: stderr for the commands
+// whose stdout carries protocol messages
+func traceOutput(cmd *cobra.Command) io.Writer {
+	if cmd == pipeCmd || cmd == mcpCmd {
+		return cmd.ErrOrStderr()
+	}
+	return cmd.OutOrStdout()
+}
+
+// stopTracer exports the last spans and closes the trace file
+func stopTracer() {
+	if err := tracer.Shutdown(); err != nil {
+		fmt.Fprintln(os.Stderr, "Warning: failed to export spans:", err)
+	}
+}
@@ -0,0 +1,233 @@
+// Package cors applique la politique d'origines des transports réseau : en-têtes CORS de l'API
+// et vérification de l'origine à l'ouverture des WebSockets
+package cors
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AnyOrigin autorise toutes les origines
+const AnyOrigin = "*"
+
+// DefaultMaxAge durée de mise en cache des réponses de prévol par défaut
+const DefaultMaxAge = 10 * time.Minute
+
+// En-têtes des requêtes et réponses cross-origin
+var (
+	allowedMethods = []string{http.MethodGet, http.MethodPost, http.MethodOptions}
+	allowedHeaders = []string{"Authorization", "Content-Type", "X-API-Key", "Last-Event-ID", "X-Request-ID"}
+	exposedHeaders = []string{"Retry-After", "X-Events-Replayed", "X-Events-Truncated", "X-Request-ID"}
+)
+
+// Policy politique d'origines
+// Sans origine autorisée, seules les requêtes de même origine et les clients hors navigateur sont acceptés.
+type Policy struct {
+	origins     []string
+	anyOrigin   bool
+	credentials bool
+	maxAge      time.Duration
+	// proxies sont crus pour le schéma transmis dans X-Forwarded-Proto
+	proxies []*net.IPNet
+}
+
+// Option configure la politique
+type Option func(*Policy)
+
+// WithOrigins autorise des origines, AnyOrigin ou des motifs comme https://*.example.com et http://localhost:*
+func WithOrigins(origins ...string) Option {
+	return func(p *Policy) {
+		for _, origin := range origins {
+			origin = strings.ToLower(strings.TrimSuffix(strings.TrimSpace(origin), "/"))
+			if origin == AnyOrigin {
+				p.anyOrigin = true
+				continue
+			}
+			p.origins = append(p.origins, origin)
+		}
+	}
+}
+
+// WithCredentials autorise l'envoi des cookies et de l'en-tête Authorization par le navigateur
+func WithCredentials(credentials bool) Option {
+	return func(p *Policy) {
+		p.credentials = credentials
+	}
+}
+
+// WithMaxAge définit la durée de mise en cache des réponses de prévol
+func WithMaxAge(maxAge time.Duration) Option {
+	return func(p *Policy) {
+		p.maxAge = maxAge
+	}
+}
+
+// WithTrustedProxies définit les proxies, adresses IP ou CIDR, dont X-Forwarded-Proto est cru
+// pour reconnaître une requête de même origine terminée en HTTPS par le proxy. Les entrées invalides sont ignorées.
+func WithTrustedProxies(proxies []string) Option {
+	return func(p *Policy) {
+		for _, proxy := range proxies {
+			if _, network, err := net.ParseCIDR(proxy); err == nil {
+				p.proxies = append(p.proxies, network)
+				continue
+			}
+			if ip := net.ParseIP(proxy); ip != nil {
+				bits := 8 * len(ip.To16())
+				if ip.To4() != nil {
+					ip, bits = ip.To4(), 32
+				}
+				p.proxies = append(p.proxies, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+			}
+		}
+	}
+}
+
+// New crée une politique, stricte sans WithOrigins
+func New(options ...Option) *Policy {
+	p := &Policy{maxAge: DefaultMaxAge}
+	for _, option := range options {
+		option(p)
+	}
+	return p
+}
+
+// ValidateOrigin vérifie une origine ou un motif d'origine
+func ValidateOrigin(origin string) error {
+	if origin == AnyOrigin {
+		return nil
+	}
+	if _, err := path.Match(origin, ""); err != nil {
+		return fmt.Errorf("%q is not a valid origin pattern", origin)
+	}
+	// Les jokers sont remplacés par un chiffre, valide dans un hôte comme dans un port
+	u, err := url.Parse(strings.ReplaceAll(origin, "*", "0"))
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" || (u.Path != "" && u.Path != "/") || u.RawQuery != "" {
+		return fmt.Errorf("%q must be *, or scheme://host[:port] with optional * wildcards", origin)
+	}
+	return nil
+}
+
+// Allowed indique si l'origine figure dans la liste des origines autorisées
+func (p *Policy) Allowed(origin string) bool {
+	if p == nil || origin == "" {
+		return false
+	}
+	if p.anyOrigin {
+		return true
+	}
+
+	origin = strings.ToLower(origin)
+	for _, pattern := range p.origins {
+		if ok, _ := path.Match(pattern, origin); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// CheckOrigin vérifie l'origine d'une ouverture de WebSocket, destinée à websocket.Upgrader
+// Une requête sans origine vient d'un client hors navigateur, une requête de même origine est toujours acceptée.
+func (p *Policy) CheckOrigin(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" || p.sameOrigin(r, origin) {
+		return true
+	}
+	return p.Allowed(origin)
+}
+
+// Middleware ajoute les en-têtes CORS aux réponses des origines autorisées et répond aux prévols
+// Une origine refusée ne reçoit aucun en-tête CORS, le navigateur bloque alors la réponse.
+func (p *Policy) Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		origin := c.GetHeader("Origin")
+		if origin == "" {
+			c.Next()
+			return
+		}
+
+		header := c.Writer.Header()
+		header.Add("Vary", "Origin")
+		preflight := c.Request.Method == http.MethodOptions && c.GetHeader("Access-Control-Request-Method") != ""
+
+		if !p.Allowed(origin) {
+			if preflight {
+				c.AbortWithStatus(http.StatusForbidden)
+				return
+			}
+			c.Next()
+			return
+		}
+
+		if p.anyOrigin && !p.credentials {
+			header.Set("Access-Control-Allow-Origin", AnyOrigin)
+		} else {
+			header.Set("Access-Control-Allow-Origin", origin)
+		}
+		if p.credentials {
+			header.Set("Access-Control-Allow-Credentials", "true")
+		}
+
+		if !preflight {
+			header.Set("Access-Control-Expose-Headers", strings.Join(exposedHeaders, ", "))
+			c.Next()
+			return
+		}
+
+		header.Add("Vary", "Access-Control-Request-Method")
+		header.Add("Vary", "Access-Control-Request-Headers")
+		header.Set("Access-Control-Allow-Methods", strings.Join(allowedMethods, ", "))
+		header.Set("Access-Control-Allow-Headers", strings.Join(allowedHeaders, ", "))
+		if p.maxAge > 0 {
+			header.Set("Access-Control-Max-Age", strconv.Itoa(int(p.maxAge.Seconds())))
+		}
+		c.AbortWithStatus(http.StatusNoContent)
+	}
+}
+
+// sameOrigin indique si l'origine désigne le schéma et l'hôte de la requête
+func (p *Policy) sameOrigin(r *http.Request, origin string) bool {
+	u, err := url.Parse(origin)
+	if err != nil {
+		return false
+	}
+	return strings.EqualFold(u.Scheme, p.scheme(r)) && strings.EqualFold(u.Host, r.Host)
+}
+
+// scheme retourne le schéma de la requête, celui de X-Forwarded-Proto lorsqu'elle vient d'un proxy de confiance
+func (p *Policy) scheme(r *http.Request) string {
+	if p != nil && p.trusted(r.RemoteAddr) {
+		if proto := strings.TrimSpace(strings.Split(r.Header.Get("X-Forwarded-Proto"), ",")[0]); proto != "" {
+			return proto
+		}
+	}
+	if r.TLS != nil {
+		return "https"
+	}
+	return "http"
+}
+
+// trusted indique si l'adresse distante est celle d'un proxy de confiance
+func (p *Policy) trusted(remoteAddr string) bool {
+	host, _, err := net.SplitHostPort(remoteAddr)
+	if err != nil {
+		host = remoteAddr
+	}
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return false
+	}
+	for _, network := range p.proxies {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
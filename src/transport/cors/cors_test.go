@@ -0,0 +1,147 @@
+package cors_test
+
+import (
+	"live-semantic/src/transport/cors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPolicy_Allowed(t *testing.T) {
+	t.Run("should match exact origins and wildcard patterns", func(t *testing.T) {
+		// Given
+		policy := cors.New(cors.WithOrigins("https://app.example.com", "https://*.example.org", "http://localhost:*"))
+
+		// Then
+		assert.True(t, policy.Allowed("https://app.example.com"))
+		assert.True(t, policy.Allowed("HTTPS://App.Example.com"))
+		assert.True(t, policy.Allowed("https://console.example.org"))
+		assert.True(t, policy.Allowed("http://localhost:3000"))
+		assert.False(t, policy.Allowed("https://example.org"))
+		assert.False(t, policy.Allowed("http://app.example.com"))
+		assert.False(t, policy.Allowed("https://evil.com"))
+	})
+
+	t.Run("should allow no cross origin when strict", func(t *testing.T) {
+		// Given
+		var disabled *cors.Policy
+
+		// Then
+		assert.False(t, cors.New().Allowed("https://app.example.com"))
+		assert.False(t, disabled.Allowed("https://app.example.com"))
+		assert.True(t, cors.New(cors.WithOrigins(cors.AnyOrigin)).Allowed("https://app.example.com"))
+	})
+}
+
+func TestPolicy_CheckOrigin(t *testing.T) {
+	t.Run("should accept non-browser and same-origin upgrades with a strict policy", func(t *testing.T) {
+		// Given
+		policy := cors.New()
+		request := func(origin string) *http.Request {
+			r := httptest.NewRequest(http.MethodGet, "http://api.example.com/ws", nil)
+			if origin != "" {
+				r.Header.Set("Origin", origin)
+			}
+			return r
+		}
+
+		// Then
+		assert.True(t, policy.CheckOrigin(request("")))
+		assert.True(t, policy.CheckOrigin(request("http://api.example.com")))
+		assert.False(t, policy.CheckOrigin(request("https://evil.com")))
+	})
+
+	t.Run("should compare the scheme of a same-host origin", func(t *testing.T) {
+		// Given
+		policy := cors.New(cors.WithTrustedProxies([]string{"10.0.0.0/8"}))
+		request := func(url, remote, forwardedProto string) *http.Request {
+			r := httptest.NewRequest(http.MethodGet, url, nil)
+			r.RemoteAddr = remote
+			r.Header.Set("Origin", "https://api.example.com")
+			if forwardedProto != "" {
+				r.Header.Set("X-Forwarded-Proto", forwardedProto)
+			}
+			return r
+		}
+
+		// Then
+		assert.False(t, policy.CheckOrigin(request("http://api.example.com/ws", "192.0.2.1:1234", "")))
+		assert.True(t, policy.CheckOrigin(request("https://api.example.com/ws", "192.0.2.1:1234", "")))
+		assert.True(t, policy.CheckOrigin(request("http://api.example.com/ws", "10.0.0.5:1234", "https")))
+		assert.False(t, policy.CheckOrigin(request("http://api.example.com/ws", "192.0.2.1:1234", "https")))
+	})
+}
+
+func TestPolicy_Middleware(t *testing.T) {
+	serve := func(policy *cors.Policy, method, origin string) *httptest.ResponseRecorder {
+		gin.SetMode(gin.TestMode)
+		router := gin.New()
+		router.Use(policy.Middleware())
+		router.GET("/api/v1/tasks", func(c *gin.Context) {
+			c.Status(http.StatusOK)
+		})
+
+		req := httptest.NewRequest(method, "/api/v1/tasks", nil)
+		req.Header.Set("Origin", origin)
+		if method == http.MethodOptions {
+			req.Header.Set("Access-Control-Request-Method", http.MethodGet)
+		}
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec
+	}
+
+	t.Run("should answer the preflight of an allowed origin with the cached policy", func(t *testing.T) {
+		// Given
+		policy := cors.New(cors.WithOrigins("https://app.example.com"), cors.WithCredentials(true), cors.WithMaxAge(time.Hour))
+
+		// When
+		rec := serve(policy, http.MethodOptions, "https://app.example.com")
+
+		// Then
+		assert.Equal(t, http.StatusNoContent, rec.Code)
+		assert.Equal(t, "https://app.example.com", rec.Header().Get("Access-Control-Allow-Origin"))
+		assert.Equal(t, "true", rec.Header().Get("Access-Control-Allow-Credentials"))
+		assert.Equal(t, "3600", rec.Header().Get("Access-Control-Max-Age"))
+		assert.Contains(t, rec.Header().Get("Access-Control-Allow-Headers"), "Authorization")
+	})
+
+	t.Run("should refuse the preflight and omit the headers for another origin", func(t *testing.T) {
+		// Given
+		policy := cors.New(cors.WithOrigins("https://app.example.com"))
+
+		// When
+		preflight := serve(policy, http.MethodOptions, "https://evil.com")
+		simple := serve(policy, http.MethodGet, "https://evil.com")
+
+		// Then
+		assert.Equal(t, http.StatusForbidden, preflight.Code)
+		assert.Equal(t, http.StatusOK, simple.Code)
+		assert.Empty(t, simple.Header().Get("Access-Control-Allow-Origin"))
+	})
+
+	t.Run("should answer any origin with a wildcard when credentials are not allowed", func(t *testing.T) {
+		// When
+		rec := serve(cors.New(cors.WithOrigins(cors.AnyOrigin)), http.MethodGet, "https://app.example.com")
+
+		// Then
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "*", rec.Header().Get("Access-Control-Allow-Origin"))
+		assert.Contains(t, rec.Header().Get("Access-Control-Expose-Headers"), "Retry-After")
+	})
+}
+
+func TestValidateOrigin(t *testing.T) {
+	t.Run("should accept origins and patterns and reject anything else", func(t *testing.T) {
+		for _, origin := range []string{"*", "https://app.example.com", "https://*.example.com", "http://localhost:*"} {
+			assert.NoError(t, cors.ValidateOrigin(origin), origin)
+		}
+		for _, origin := range []string{"app.example.com", "ftp://host", "https://host/path", "https://[host"} {
+			assert.Error(t, cors.ValidateOrigin(origin), origin)
+		}
+	})
+}
@@ -0,0 +1,163 @@
+package gateway_test
+
+import (
+	"context"
+	"io"
+	"live-semantic/src/domain/uc"
+	"live-semantic/src/logging"
+	"live-semantic/src/transport/api"
+	"live-semantic/src/transport/gateway"
+	"live-semantic/src/transport/rpc"
+	ws "live-semantic/src/transport/websocket"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+)
+
+// freePort retourne un port libre pour démarrer la passerelle sur un vrai listener
+func freePort(t *testing.T) int {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	defer listener.Close()
+	return listener.Addr().(*net.TCPAddr).Port
+}
+
+// drains garde l'ordre des drainages et l'état du listener à chacun d'eux
+type drains struct {
+	mu       sync.Mutex
+	names    []string
+	listened []bool
+}
+
+// recorded enregistre le drainage du composant avant de le lui transmettre
+type recorded struct {
+	gateway.Component
+	name   string
+	addr   *string
+	drains *drains
+}
+
+func (r recorded) Drain(ctx context.Context) error {
+	conn, err := net.Dial("tcp", *r.addr)
+	if err == nil {
+		conn.Close()
+	}
+	r.drains.mu.Lock()
+	r.drains.names = append(r.drains.names, r.name)
+	r.drains.listened = append(r.drains.listened, err == nil)
+	r.drains.mu.Unlock()
+	return r.Component.(gateway.Drainer).Drain(ctx)
+}
+
+// startGateway monte l'API, le WebSocket et le JSON-RPC sur une passerelle démarrée
+// wrap permet d'envelopper chaque composant avant son montage
+func startGateway(t *testing.T, wrap func(name string, component gateway.Component) gateway.Component) (*gateway.Server, string, chan error) {
+	t.Helper()
+
+	useCases, err := uc.NewUseCase(logging.Discard)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	port := freePort(t)
+	gw := gateway.NewServer(logging.Discard, port, gateway.WithHost("127.0.0.1"))
+	gw.Mount("api", wrap("api", api.NewServer(useCases, logging.Discard, 0)))
+	gw.Mount("websocket", wrap("websocket", ws.NewServer(useCases, logging.Discard, 0)))
+	gw.Mount("rpc", wrap("rpc", rpc.NewServer(useCases, logging.Discard, 0)))
+
+	started := make(chan error, 1)
+	go func() { started <- gw.Start() }()
+
+	addr := net.JoinHostPort("127.0.0.1", strconv.Itoa(port))
+	assert.Eventually(t, func() bool {
+		conn, err := net.Dial("tcp", addr)
+		if err == nil {
+			conn.Close()
+		}
+		return err == nil
+	}, time.Second, 10*time.Millisecond)
+	return gw, addr, started
+}
+
+func TestServer_Mount(t *testing.T) {
+	t.Run("should serve the routes of every mounted transport on one listener", func(t *testing.T) {
+		// Given
+		gw, addr, _ := startGateway(t, func(name string, component gateway.Component) gateway.Component { return component })
+		defer gw.Stop()
+		base := "http://" + addr
+
+		// When
+		tasks, tasksErr := http.Get(base + "/api/v1/tasks")
+		call, callErr := http.Post(base+"/rpc", "application/json", strings.NewReader(`{"jsonrpc":"2.0","method":"task.list","id":1}`))
+		conn, _, wsErr := websocket.DefaultDialer.Dial("ws://"+addr+"/ws", nil)
+		rpcConn, _, rpcWSErr := websocket.DefaultDialer.Dial("ws://"+addr+"/rpc/ws", nil)
+		health, healthErr := http.Get(base + "/health")
+
+		// Then
+		if assert.NoError(t, tasksErr) {
+			tasks.Body.Close()
+			assert.Equal(t, http.StatusOK, tasks.StatusCode)
+		}
+		if assert.NoError(t, callErr) {
+			body, _ := io.ReadAll(call.Body)
+			call.Body.Close()
+			assert.Equal(t, http.StatusOK, call.StatusCode)
+			assert.Contains(t, string(body), `"result"`)
+		}
+		if assert.NoError(t, wsErr) {
+			conn.Close()
+		}
+		if assert.NoError(t, rpcWSErr) {
+			rpcConn.Close()
+		}
+		if assert.NoError(t, healthErr) {
+			body, _ := io.ReadAll(health.Body)
+			health.Body.Close()
+			assert.Contains(t, string(body), `"transports":["api","websocket","rpc"]`)
+		}
+	})
+}
+
+func TestServer_Shutdown(t *testing.T) {
+	t.Run("should stop listening, then drain the components in mount order", func(t *testing.T) {
+		// Given
+		recorder := &drains{}
+		var addr string
+		gw, addr, started := startGateway(t, func(name string, component gateway.Component) gateway.Component {
+			return recorded{Component: component, name: name, addr: &addr, drains: recorder}
+		})
+		conn, _, err := websocket.DefaultDialer.Dial("ws://"+addr+"/ws", nil)
+		if !assert.NoError(t, err) {
+			return
+		}
+		defer conn.Close()
+		// Le client répond à la trame de fermeture, comme un client conforme
+		closed := make(chan error, 1)
+		go func() {
+			_, _, err := conn.ReadMessage()
+			closed <- err
+		}()
+
+		// When
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		defer cancel()
+		err = gw.Shutdown(ctx)
+
+		// Then
+		assert.NoError(t, err)
+		assert.NoError(t, <-started)
+		assert.Equal(t, []string{"api", "websocket", "rpc"}, recorder.names)
+		assert.Equal(t, []bool{false, false, false}, recorder.listened)
+		assert.True(t, websocket.IsCloseError(<-closed, websocket.CloseGoingAway))
+	})
+}
@@ -0,0 +1,31 @@
+package gateway
+
+import (
+	"live-semantic/src/health"
+	"live-semantic/src/metrics"
+
+	"github.com/gin-gonic/gin"
+)
+
+// setupRoutes configure les routes propres à la passerelle
+func (s *Server) setupRoutes() {
+	s.healthEndpoints().Register(s.router)
+	// Métriques au format Prometheus, publiques comme la santé
+	if handler := metrics.Handler(s.metrics); handler != nil {
+		s.router.GET("/metrics", gin.WrapH(handler))
+	}
+}
+
+// healthEndpoints endpoints de santé agrégeant les sondes du registre
+// La réponse de /health liste aussi les transports montés
+func (s *Server) healthEndpoints() health.Endpoints {
+	return health.Endpoints{
+		Registry: s.health,
+		Service:  "live-semantic",
+		Version:  "1.0.0",
+		Draining: s.draining.Load,
+		Extra: func() gin.H {
+			return gin.H{"transports": s.componentNames()}
+		},
+	}
+}
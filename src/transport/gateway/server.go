@@ -0,0 +1,276 @@
+// Package gateway héberge plusieurs transports réseau sur un seul listener
+package gateway
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"live-semantic/src/health"
+	"live-semantic/src/lifecycle"
+	"live-semantic/src/metrics"
+	"live-semantic/src/tracing"
+	"live-semantic/src/transport/cors"
+	"live-semantic/src/transport/requestid"
+	"live-semantic/src/transport/traces"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/deadelus/go-clean-app/src/logger"
+	"github.com/gin-gonic/gin"
+)
+
+// DefaultShutdownTimeout délai accordé aux requêtes et sessions lors de l'arrêt
+const DefaultShutdownTimeout = 15 * time.Second
+
+// Component composant de transport monté sur le routeur partagé
+type Component interface {
+	RegisterRoutes(router gin.IRouter)
+}
+
+// Drainer composant possédant des connexions longues à drainer à l'arrêt
+type Drainer interface {
+	Drain(ctx context.Context) error
+}
+
+// Streamer composant possédant des réponses en flux à terminer dès le début de l'arrêt
+// Sans cela, l'arrêt du listener attendrait la fin de ces réponses
+type Streamer interface {
+	CloseStreams()
+}
+
+// mounted composant monté et son nom
+type mounted struct {
+	name      string
+	component Component
+}
+
+// Server héberge les composants montés sur un seul routeur
+type Server struct {
+	logger          logger.Logger
+	host            string
+	trustedProxies  []string
+	port            int
+	router          *gin.Engine
+	httpServer      *http.Server
+	components      []mounted
+	health          *health.Registry
+	cors            *cors.Policy
+	metrics         metrics.Collector
+	tracer          *tracing.Tracer
+	shutdownTimeout atomic.Int64
+	draining        atomic.Bool
+	stopped         chan struct{}
+	stopOnce        sync.Once
+}
+
+// Option configure la passerelle
+type Option func(*Server)
+
+// WithHost définit l'interface d'écoute, vide pour toutes les interfaces
+func WithHost(host string) Option {
+	return func(s *Server) {
+		s.host = host
+	}
+}
+
+// WithTrustedProxies définit les proxies, adresses IP ou CIDR, dont X-Forwarded-For est cru
+// Sans proxy de confiance, le client d'une requête est l'adresse de sa connexion
+func WithTrustedProxies(proxies []string) Option {
+	return func(s *Server) {
+		s.trustedProxies = proxies
+	}
+}
+
+// WithShutdownTimeout définit le délai de drainage des requêtes et sessions
+func WithShutdownTimeout(timeout time.Duration) Option {
+	return func(s *Server) {
+		s.shutdownTimeout.Store(int64(timeout))
+	}
+}
+
+// WithHealth définit le registre des sondes agrégées par /health et /readyz
+func WithHealth(registry *health.Registry) Option {
+	return func(s *Server) {
+		s.health = registry
+	}
+}
+
+// WithCORS définit la politique d'origines des navigateurs
+// Elle s'applique aux routes de tous les composants montés
+func WithCORS(policy *cors.Policy) Option {
+	return func(s *Server) {
+		s.cors = policy
+	}
+}
+
+// WithMetrics enregistre les métriques des requêtes de tous les composants montés et les expose sur /metrics
+// lorsque le collecteur sait les exposer
+func WithMetrics(collector metrics.Collector) Option {
+	return func(s *Server) {
+		if collector != nil {
+			s.metrics = collector
+		}
+	}
+}
+
+// WithTracer démarre un span serveur pour chaque requête des composants montés
+func WithTracer(tracer *tracing.Tracer) Option {
+	return func(s *Server) {
+		s.tracer = tracer
+	}
+}
+
+// NewServer crée une nouvelle passerelle
+func NewServer(logger logger.Logger, port int, options ...Option) *Server {
+	gin.SetMode(gin.ReleaseMode)
+	router := gin.New()
+	router.Use(gin.Recovery())
+
+	server := &Server{
+		logger:  logger,
+		port:    port,
+		router:  router,
+		health:  health.NewRegistry(),
+		cors:    cors.New(),
+		metrics: metrics.Discard,
+		stopped: make(chan struct{}),
+	}
+
+	server.shutdownTimeout.Store(int64(DefaultShutdownTimeout))
+	for _, option := range options {
+		option(server)
+	}
+	if err := router.SetTrustedProxies(server.trustedProxies); err != nil {
+		logger.Warn("Invalid trusted proxies, forwarded headers ignored", map[string]interface{}{"error": err.Error()})
+		_ = router.SetTrustedProxies(nil)
+	}
+	router.Use(requestid.Middleware(), traces.Middleware(server.tracer), metrics.Middleware(server.metrics), server.cors.Middleware())
+
+	server.httpServer = &http.Server{
+		Addr:    net.JoinHostPort(server.host, strconv.Itoa(port)),
+		Handler: router,
+	}
+
+	server.setupRoutes()
+	return server
+}
+
+// Mount monte les routes d'un composant sur le routeur partagé
+func (s *Server) Mount(name string, component Component) {
+	component.RegisterRoutes(s.router)
+	if streamer, ok := component.(Streamer); ok {
+		s.httpServer.RegisterOnShutdown(streamer.CloseStreams)
+	}
+	s.components = append(s.components, mounted{name: name, component: component})
+}
+
+// componentNames retourne les noms des composants montés
+func (s *Server) componentNames() []string {
+	names := make([]string, 0, len(s.components))
+	for _, m := range s.components {
+		names = append(names, m.name)
+	}
+	return names
+}
+
+// Start démarre la passerelle
+// Il bloque jusqu'à la fin du drainage lorsque Stop est appelé
+func (s *Server) Start() error {
+	s.logger.Info("Starting gateway server", map[string]interface{}{
+		"port":       s.port,
+		"components": s.componentNames(),
+	})
+
+	err := s.httpServer.ListenAndServe()
+	if !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+
+	<-s.stopped
+	return nil
+}
+
+// Stop arrête la passerelle en laissant aux composants le délai de drainage
+// Elle est destinée à être enregistrée auprès de engine.Gracefull()
+func (s *Server) Stop() error {
+	ctx, cancel := context.WithTimeout(context.Background(), s.ShutdownTimeout())
+	defer cancel()
+
+	return s.Shutdown(ctx)
+}
+
+// ShutdownTimeout retourne le délai de drainage courant
+func (s *Server) ShutdownTimeout() time.Duration {
+	return time.Duration(s.shutdownTimeout.Load())
+}
+
+// SetShutdownTimeout met à jour le délai de drainage, y compris pendant l'exécution
+func (s *Server) SetShutdownTimeout(timeout time.Duration) {
+	s.shutdownTimeout.Store(int64(timeout))
+}
+
+// State implémente lifecycle.Component
+func (s *Server) State() lifecycle.State {
+	select {
+	case <-s.stopped:
+		return lifecycle.StateStopped
+	default:
+	}
+	if s.draining.Load() {
+		return lifecycle.StateDraining
+	}
+	return lifecycle.StateRunning
+}
+
+// Drain signale la passerelle comme non prête puis draine chaque composant monté, sans arrêter l'écoute
+func (s *Server) Drain(ctx context.Context) error {
+	s.draining.Store(true)
+	return s.drainComponents(ctx)
+}
+
+// drainComponents draine les composants montés qui possèdent des connexions longues
+func (s *Server) drainComponents(ctx context.Context) error {
+	var errs []error
+	for _, m := range s.components {
+		drainer, ok := m.component.(Drainer)
+		if !ok {
+			continue
+		}
+		if err := drainer.Drain(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", m.name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Shutdown arrête l'écoute puis draine chaque composant
+func (s *Server) Shutdown(ctx context.Context) error {
+	defer s.stopOnce.Do(func() { close(s.stopped) })
+
+	s.draining.Store(true)
+
+	s.logger.Info("Draining gateway server", map[string]interface{}{
+		"timeout": s.ShutdownTimeout().String(),
+	})
+
+	var errs []error
+	if err := s.httpServer.Shutdown(ctx); err != nil {
+		_ = s.httpServer.Close()
+		errs = append(errs, fmt.Errorf("gateway drain: %w", err))
+	}
+
+	if err := s.drainComponents(ctx); err != nil {
+		errs = append(errs, err)
+	}
+
+	if len(errs) > 0 {
+		return errors.Join(errs...)
+	}
+
+	s.logger.Info("Gateway server stopped")
+	return nil
+}
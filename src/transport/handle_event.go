@@ -0,0 +1,29 @@
+package transport
+
+import (
+	"live-semantic/src/domain/dto"
+	"live-semantic/src/domain/uc"
+)
+
+// HandleSubscribeEvents handles a subscription to the domain events
+// The subscription lasts until the end of the request context
+func (h *BaseHandler) HandleSubscribeEvents(req TransportRequest[dto.EventRequest]) (response TransportResponse[dto.EventSubscription]) {
+	ctx, done := h.begin(req.Context, uc.ActionEventSubscribe, req.Source)
+	defer func() { done(response.Success, response.Error) }()
+
+	h.logger.Info("Handling event subscription", map[string]interface{}{
+		"source":        req.Source,
+		"types":         req.Data.Types,
+		"last_event_id": req.Data.LastEventID,
+		"recent":        req.Data.Recent,
+	})
+
+	result, err := h.useCases.SubscribeEvents(ctx, req.Data)
+	if err != nil {
+		return TransportResponse[dto.EventSubscription]{Success: false, Error: err.Error(), Source: req.Source}
+	}
+	if !result.Success {
+		return TransportResponse[dto.EventSubscription]{Success: false, Error: result.Error, Source: req.Source}
+	}
+	return TransportResponse[dto.EventSubscription]{Success: true, Data: result.Data, Source: req.Source}
+}
@@ -0,0 +1,48 @@
+package transport
+
+import (
+	"context"
+	"live-semantic/src/domain/dto"
+	"live-semantic/src/domain/uc"
+)
+
+// HandleListTasks handles a request listing the tasks
+func (h *BaseHandler) HandleListTasks(req TransportRequest[dto.ListRequest]) TransportResponse[[]dto.TaskResponse] {
+	return handleList(h, req, "tasks", uc.ActionTaskList, h.useCases.ListTasks)
+}
+
+// HandleListFilters handles a request listing the predefined filters
+func (h *BaseHandler) HandleListFilters(req TransportRequest[dto.ListRequest]) TransportResponse[[]dto.FilterResponse] {
+	return handleList(h, req, "filters", uc.ActionFilterList, h.useCases.ListFilters)
+}
+
+// HandleListSources handles a request listing the video sources
+func (h *BaseHandler) HandleListSources(req TransportRequest[dto.ListRequest]) TransportResponse[[]dto.SourceResponse] {
+	return handleList(h, req, "sources", uc.ActionSourceList, h.useCases.ListSources)
+}
+
+// handleList calls a list use case and converts its result to a TransportResponse
+func handleList[T any](
+	h *BaseHandler,
+	req TransportRequest[dto.ListRequest],
+	collection, operation string,
+	list func(context.Context, dto.ListRequest) (dto.Result[[]T], error),
+) (response TransportResponse[[]T]) {
+	ctx, done := h.begin(req.Context, operation, req.Source)
+	defer func() { done(response.Success, response.Error) }()
+
+	h.logger.Debug("Handling List request", map[string]interface{}{
+		"source":     req.Source,
+		"collection": collection,
+		"prefix":     req.Data.Prefix,
+	})
+
+	result, err := list(ctx, req.Data)
+	if err != nil {
+		return TransportResponse[[]T]{Success: false, Error: err.Error(), Source: req.Source}
+	}
+	if !result.Success {
+		return TransportResponse[[]T]{Success: false, Error: result.Error, Source: req.Source}
+	}
+	return TransportResponse[[]T]{Success: true, Data: result.Data, Source: req.Source}
+}
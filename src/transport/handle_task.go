@@ -1,20 +1,26 @@
 package transport
 
-import "live-semantic/src/domain/dto"
+import (
+	"live-semantic/src/domain/dto"
+	"live-semantic/src/domain/uc"
+)
 
 // HandleTask handles a Task request
 // It takes a TransportRequest with dto.TaskRequest data and returns a TransportResponse with dto
-func (h *BaseHandler) HandleTask(req TransportRequest[dto.TaskRequest]) TransportResponse[dto.TaskResponse] {
+func (h *BaseHandler) HandleTask(req TransportRequest[dto.TaskRequest]) (response TransportResponse[dto.TaskResponse]) {
+	ctx, done := h.begin(req.Context, uc.ActionTaskCreate, req.Source)
+	defer func() { done(response.Success, response.Error) }()
+
 	// Log the request details
-	// This is where you would typically log the request for debugging or monitoring purposes
+	// The title and the description may hold personal data, only their lengths are logged
 	h.logger.Info("Handling Task request", map[string]interface{}{
-		"source":      req.Source,
-		"title":       req.Data.Title,
-		"description": req.Data.Description,
+		"source":             req.Source,
+		"title_length":       len(req.Data.Title),
+		"description_length": len(req.Data.Description),
 	})
 
 	// Call the use case with the request data
-	result, err := h.useCases.CreateTask(req.Context, req.Data)
+	result, err := h.useCases.CreateTask(ctx, req.Data)
 
 	// Handle errors and convert to TransportResponse
 	if err != nil {
@@ -1,21 +1,99 @@
 package transport
 
 import (
+	"context"
 	"live-semantic/src/domain/uc"
+	"live-semantic/src/metrics"
+	"live-semantic/src/tracing"
+	"live-semantic/src/transport/requestid"
+	"time"
 
 	"github.com/deadelus/go-clean-app/src/logger"
 )
 
+// Métriques des appels de cas d'usage, par opération, source et issue
+const (
+	UseCaseCalls    = "usecase_calls_total"
+	UseCaseDuration = "usecase_duration_seconds"
+)
+
 // BaseHandler handler générique réutilisable
 type BaseHandler struct {
 	useCases uc.UseCases
 	logger   logger.Logger
+	metrics  metrics.Collector
+	tracer   *tracing.Tracer
+}
+
+// HandlerOption configure le handler de base
+type HandlerOption func(*BaseHandler)
+
+// WithMetrics mesure le nombre, la durée et l'issue des appels de cas d'usage
+func WithMetrics(collector metrics.Collector) HandlerOption {
+	return func(h *BaseHandler) {
+		if collector != nil {
+			h.metrics = collector
+		}
+	}
+}
+
+// WithTracer démarre une trace pour chaque appel qui n'en porte pas déjà une
+// Sans traceur, les appels ne sont tracés que sous le span d'un transport
+func WithTracer(tracer *tracing.Tracer) HandlerOption {
+	return func(h *BaseHandler) {
+		h.tracer = tracer
+	}
 }
 
 // NewBaseHandler crée un handler de base
-func NewBaseHandler(useCases uc.UseCases, logger logger.Logger) *BaseHandler {
-	return &BaseHandler{
+func NewBaseHandler(useCases uc.UseCases, logger logger.Logger, options ...HandlerOption) *BaseHandler {
+	h := &BaseHandler{
 		useCases: useCases,
 		logger:   logger,
+		metrics:  metrics.Discard,
+	}
+	for _, option := range options {
+		option(h)
 	}
+	return h
+}
+
+// begin démarre le span d'un appel de cas d'usage et retourne son contexte, qui porte la requête
+// à l'origine de l'appel : l'identifiant posé par le transport est conservé, sinon un nouveau est généré
+// La fonction retournée enregistre l'appel terminé et clôt le span
+func (h *BaseHandler) begin(ctx context.Context, operation, source string) (context.Context, func(success bool, failure string)) {
+	start := time.Now()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	request, _ := uc.RequestFrom(ctx)
+	if request.ID == "" {
+		request.ID = requestid.New()
+	}
+	request.Source = source
+	ctx = uc.WithRequest(ctx, request)
+	ctx, span := h.tracer.Start(ctx, "handler "+operation, tracing.WithAttributes(
+		tracing.Attr("operation", operation),
+		tracing.Attr("source", source),
+	))
+
+	return ctx, func(success bool, failure string) {
+		if !success {
+			span.Fail(failure)
+		}
+		span.End()
+		h.observe(operation, source, start, success)
+	}
+}
+
+// observe enregistre un appel de cas d'usage terminé
+func (h *BaseHandler) observe(operation, source string, start time.Time, success bool) {
+	outcome := "success"
+	if !success {
+		outcome = "failure"
+	}
+
+	labels := []metrics.Label{{Name: "operation", Value: operation}, {Name: "source", Value: source}}
+	h.metrics.RecordCounter(UseCaseCalls, 1, append(labels, metrics.Label{Name: "outcome", Value: outcome})...)
+	h.metrics.RecordLatency(UseCaseDuration, time.Since(start), labels...)
 }
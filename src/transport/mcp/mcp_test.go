@@ -0,0 +1,205 @@
+package mcp_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"live-semantic/src/domain/dto"
+	"live-semantic/src/domain/uc"
+	"live-semantic/src/logging"
+	"live-semantic/src/transport/mcp"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type response struct {
+	Result json.RawMessage `json:"result"`
+	Error  *struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	} `json:"error"`
+	ID json.RawMessage `json:"id"`
+}
+
+type toolResult struct {
+	Content []struct {
+		Type string `json:"type"`
+		Text string `json:"text"`
+	} `json:"content"`
+	IsError bool `json:"isError"`
+}
+
+// rejectingUseCases refuse la création des tâches
+type rejectingUseCases struct {
+	uc.UseCases
+}
+
+func (rejectingUseCases) CreateTask(context.Context, dto.TaskRequest) (dto.Result[dto.TaskResponse], error) {
+	return dto.Failure[dto.TaskResponse]("title is required"), nil
+}
+
+func newUseCases(t *testing.T) uc.UseCases {
+	t.Helper()
+
+	useCases, err := uc.NewUseCase(logging.Discard)
+	assert.NoError(t, err)
+	return useCases
+}
+
+// run envoie les messages au serveur et retourne les réponses par id
+func run(t *testing.T, server *mcp.Server, messages ...string) map[string]response {
+	t.Helper()
+
+	var out bytes.Buffer
+	err := server.Run(context.Background(), strings.NewReader(strings.Join(messages, "\n")), &out)
+	assert.NoError(t, err)
+
+	responses := map[string]response{}
+	for _, line := range strings.Split(strings.TrimSpace(out.String()), "\n") {
+		if line == "" {
+			continue
+		}
+		var r response
+		assert.NoError(t, json.Unmarshal([]byte(line), &r))
+		responses[string(r.ID)] = r
+	}
+	return responses
+}
+
+func TestServer_Run(t *testing.T) {
+	t.Run("should initialize and ignore the notifications", func(t *testing.T) {
+		// Given
+		server := mcp.New(newUseCases(t), logging.Discard)
+
+		// When
+		responses := run(t, server,
+			`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2024-11-05","capabilities":{}}}`,
+			`{"jsonrpc":"2.0","method":"notifications/initialized"}`,
+			`{"jsonrpc":"2.0","id":2,"method":"ping"}`,
+		)
+
+		// Then
+		assert.Len(t, responses, 2)
+		assert.Contains(t, string(responses["1"].Result), `"protocolVersion":"2024-11-05"`)
+		assert.Contains(t, string(responses["1"].Result), `"tools":{}`)
+		assert.JSONEq(t, `{}`, string(responses["2"].Result))
+	})
+
+	t.Run("should list the tools with schemas derived from the dto", func(t *testing.T) {
+		// Given
+		server := mcp.New(newUseCases(t), logging.Discard)
+
+		// When
+		responses := run(t, server, `{"jsonrpc":"2.0","id":1,"method":"tools/list"}`)
+
+		// Then
+		var result struct {
+			Tools []struct {
+				Name        string         `json:"name"`
+				InputSchema map[string]any `json:"inputSchema"`
+			} `json:"tools"`
+		}
+		assert.NoError(t, json.Unmarshal(responses["1"].Result, &result))
+		assert.Len(t, result.Tools, 5)
+		assert.Equal(t, mcp.ToolCreateTask, result.Tools[0].Name)
+		assert.Equal(t, []any{"title", "name"}, result.Tools[0].InputSchema["required"])
+		filters := result.Tools[0].InputSchema["properties"].(map[string]any)["filters"].(map[string]any)
+		assert.Equal(t, "array", filters["type"])
+		assert.NotEmpty(t, filters["description"])
+	})
+
+	t.Run("should call a tool through the base handler", func(t *testing.T) {
+		// Given
+		server := mcp.New(newUseCases(t), logging.Discard)
+
+		// When
+		responses := run(t, server,
+			`{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"create_task","arguments":{"title":"Lobby","name":"Night watch"}}}`,
+			`{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"list_filters","arguments":{"prefix":"person","limit":1}}}`,
+		)
+
+		// Then
+		var created toolResult
+		assert.NoError(t, json.Unmarshal(responses["1"].Result, &created))
+		assert.False(t, created.IsError)
+		assert.Contains(t, created.Content[0].Text, `"id":"12345"`)
+
+		var filters toolResult
+		assert.NoError(t, json.Unmarshal(responses["2"].Result, &filters))
+		var items []dto.FilterResponse
+		assert.NoError(t, json.Unmarshal([]byte(filters.Content[0].Text), &items))
+		assert.Len(t, items, 1)
+	})
+
+	t.Run("should return a use case failure as a tool error", func(t *testing.T) {
+		// Given
+		server := mcp.New(rejectingUseCases{newUseCases(t)}, logging.Discard)
+
+		// When
+		responses := run(t, server, `{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"create_task","arguments":{}}}`)
+
+		// Then
+		var result toolResult
+		assert.Nil(t, responses["1"].Error)
+		assert.NoError(t, json.Unmarshal(responses["1"].Result, &result))
+		assert.True(t, result.IsError)
+		assert.Equal(t, "title is required", result.Content[0].Text)
+	})
+
+	t.Run("should reject an unknown tool or invalid arguments", func(t *testing.T) {
+		// Given
+		server := mcp.New(newUseCases(t), logging.Discard)
+
+		// When
+		responses := run(t, server,
+			`{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"unknown"}}`,
+			`{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"list_tasks","arguments":{"limit":"ten"}}}`,
+			`{"jsonrpc":"2.0","id":3,"method":"unknown"}`,
+		)
+
+		// Then
+		assert.Equal(t, -32602, responses["1"].Error.Code)
+		assert.Equal(t, -32602, responses["2"].Error.Code)
+		assert.Equal(t, -32601, responses["3"].Error.Code)
+	})
+
+	t.Run("should read the tasks", func(t *testing.T) {
+		// Given
+		server := mcp.New(newUseCases(t), logging.Discard)
+
+		// When
+		responses := run(t, server,
+			`{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"create_task","arguments":{"title":"Lobby","name":"Night watch"}}}`,
+			`{"jsonrpc":"2.0","id":2,"method":"resources/list"}`,
+			`{"jsonrpc":"2.0","id":3,"method":"resources/read","params":{"uri":"livesemantic://tasks"}}`,
+			`{"jsonrpc":"2.0","id":4,"method":"resources/read","params":{"uri":"livesemantic://unknown"}}`,
+		)
+
+		// Then
+		assert.Contains(t, string(responses["2"].Result), mcp.ResourceTasks)
+		assert.Contains(t, string(responses["3"].Result), `\"id\":\"12345\"`)
+		assert.Equal(t, mcp.CodeResourceNotFound, responses["4"].Error.Code)
+	})
+
+	t.Run("should return the recent events", func(t *testing.T) {
+		// Given
+		server := mcp.New(newUseCases(t), logging.Discard, mcp.WithRecent(1))
+
+		// When
+		responses := run(t, server,
+			`{"jsonrpc":"2.0","id":0,"method":"tools/call","params":{"name":"create_task","arguments":{"title":"Hall","name":"Day watch"}}}`,
+			`{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"create_task","arguments":{"title":"Lobby","name":"Night watch"}}}`,
+			`{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"recent_events","arguments":{"types":["task.created"]}}}`,
+		)
+
+		// Then
+		var result toolResult
+		assert.NoError(t, json.Unmarshal(responses["2"].Result, &result))
+		var events []dto.EventResponse
+		assert.NoError(t, json.Unmarshal([]byte(result.Content[0].Text), &events))
+		assert.Len(t, events, 1)
+		assert.Equal(t, dto.EventTaskCreated, events[0].Type)
+	})
+}
@@ -0,0 +1,89 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"live-semantic/src/domain/dto"
+	"live-semantic/src/transport"
+	"live-semantic/src/transport/rpc"
+)
+
+// ResourceTasks ressource des tâches
+const ResourceTasks = "livesemantic://tasks"
+
+// mimeType type des ressources exposées
+const mimeType = "application/json"
+
+// resource ressource exposée au client
+type resource struct {
+	URI         string `json:"uri"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	MimeType    string `json:"mimeType"`
+	read        func(ctx context.Context) (any, string)
+}
+
+// ResourceContents contenu d'une ressource lue
+type ResourceContents struct {
+	URI      string `json:"uri"`
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text"`
+}
+
+// resourceRead paramètres de resources/read
+type resourceRead struct {
+	URI string `json:"uri"`
+}
+
+// newResources construit les ressources sur le handler de base
+func (s *Server) newResources() []resource {
+	return []resource{
+		{
+			URI:         ResourceTasks,
+			Name:        "tasks",
+			Description: "Tasks created on the server.",
+			MimeType:    mimeType,
+			read: func(ctx context.Context) (any, string) {
+				return read(ctx, s.baseHandler.HandleListTasks, dto.ListRequest{})
+			},
+		},
+	}
+}
+
+// read exécute une opération du handler de base, le message est vide en cas de succès
+func read[Req, Resp any](ctx context.Context, handle func(transport.TransportRequest[Req]) transport.TransportResponse[Resp], req Req) (any, string) {
+	response := handle(transport.TransportRequest[Req]{
+		Data:    req,
+		Context: ctx,
+		Source:  source,
+	})
+	if !response.Success {
+		return nil, response.Error
+	}
+	return response.Data, ""
+}
+
+// readResource exécute resources/read
+func (s *Server) readResource(ctx context.Context, params json.RawMessage) (any, *rpc.Error) {
+	var req resourceRead
+	if err := decodeParams(params, &req); err != nil {
+		return nil, err
+	}
+
+	for _, r := range s.resources {
+		if r.URI != req.URI {
+			continue
+		}
+
+		data, message := r.read(ctx)
+		if message != "" {
+			return nil, &rpc.Error{Code: rpc.CodeDomainError, Message: "Domain error", Data: rpc.DomainError{Error: message, Source: source}}
+		}
+		text, err := json.Marshal(data)
+		if err != nil {
+			return nil, &rpc.Error{Code: rpc.CodeInternalError, Message: "Internal error: " + err.Error()}
+		}
+		return map[string]any{"contents": []ResourceContents{{URI: r.URI, MimeType: r.MimeType, Text: string(text)}}}, nil
+	}
+	return nil, &rpc.Error{Code: CodeResourceNotFound, Message: "Resource not found: " + req.URI}
+}
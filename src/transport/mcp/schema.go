@@ -0,0 +1,89 @@
+package mcp
+
+import (
+	"reflect"
+	"strings"
+	"time"
+)
+
+// Schema schéma JSON d'un type
+type Schema map[string]any
+
+// schemaOf dérive le schéma JSON d'un type dto
+// Les noms suivent les tags json, les champs sans omitempty sont requis et le tag doc fournit la description.
+func schemaOf(t reflect.Type) Schema {
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	switch {
+	case t == reflect.TypeOf(time.Time{}):
+		return Schema{"type": "string", "format": "date-time"}
+	case t.Kind() == reflect.Struct:
+		properties := Schema{}
+		required := []string{}
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			name, omitempty, ok := jsonName(f)
+			if !ok {
+				continue
+			}
+
+			property := schemaOf(f.Type)
+			if doc := f.Tag.Get("doc"); doc != "" {
+				property["description"] = doc
+			}
+			properties[name] = property
+			if !omitempty {
+				required = append(required, name)
+			}
+		}
+
+		schema := Schema{"type": "object", "properties": properties}
+		if len(required) > 0 {
+			schema["required"] = required
+		}
+		return schema
+	case t.Kind() == reflect.Slice || t.Kind() == reflect.Array:
+		return Schema{"type": "array", "items": schemaOf(t.Elem())}
+	case t.Kind() == reflect.Map:
+		return Schema{"type": "object", "additionalProperties": schemaOf(t.Elem())}
+	case t.Kind() == reflect.String:
+		return Schema{"type": "string"}
+	case t.Kind() == reflect.Bool:
+		return Schema{"type": "boolean"}
+	case t.Kind() >= reflect.Int && t.Kind() <= reflect.Uint64:
+		schema := Schema{"type": "integer"}
+		if t.Kind() >= reflect.Uint {
+			schema["minimum"] = 0
+		}
+		return schema
+	case t.Kind() == reflect.Float32 || t.Kind() == reflect.Float64:
+		return Schema{"type": "number"}
+	default:
+		// Interface ou type libre
+		return Schema{}
+	}
+}
+
+// jsonName retourne le nom JSON d'un champ exporté et son option omitempty
+func jsonName(f reflect.StructField) (name string, omitempty, ok bool) {
+	if !f.IsExported() {
+		return "", false, false
+	}
+	tag := f.Tag.Get("json")
+	if tag == "-" {
+		return "", false, false
+	}
+
+	name, options, _ := strings.Cut(tag, ",")
+	if name == "" {
+		name = f.Name
+	}
+	for _, option := range strings.Split(options, ",") {
+		if option == "omitempty" {
+			omitempty = true
+		}
+	}
+	return name, omitempty, true
+}
@@ -0,0 +1,244 @@
+// Package mcp expose les cas d'usage en serveur Model Context Protocol sur l'entrée et la sortie standard
+package mcp
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"live-semantic/src/domain/uc"
+	"live-semantic/src/metrics"
+	"live-semantic/src/tracing"
+	"live-semantic/src/transport"
+	"live-semantic/src/transport/rpc"
+
+	"github.com/deadelus/go-clean-app/src/logger"
+)
+
+// ProtocolVersion version du protocole MCP implémentée
+const ProtocolVersion = "2024-11-05"
+
+// DefaultMaxLineSize taille maximale d'un message par défaut
+const DefaultMaxLineSize = 1 << 20
+
+// DefaultRecent nombre d'événements récents retournés par défaut par l'outil recent_events
+const DefaultRecent = 50
+
+// CodeResourceNotFound erreur retournée pour une ressource inconnue
+const CodeResourceNotFound = -32002
+
+// source identifie le transport dans les requêtes transmises au handler de base
+const source = "mcp"
+
+// Méthodes du protocole
+const (
+	MethodInitialize    = "initialize"
+	MethodPing          = "ping"
+	MethodToolsList     = "tools/list"
+	MethodToolsCall     = "tools/call"
+	MethodResourcesList = "resources/list"
+	MethodResourcesRead = "resources/read"
+)
+
+// serverInfo identifie le serveur auprès du client
+var serverInfo = map[string]string{"name": "live-semantic", "version": "1.0.0"}
+
+// Server traite les messages JSON-RPC d'un client MCP, un message par ligne
+type Server struct {
+	baseHandler *transport.BaseHandler
+	logger      logger.Logger
+	tools       []tool
+	resources   []resource
+	maxLineSize int
+	recent      int
+	metrics     metrics.Collector
+	tracer      *tracing.Tracer
+}
+
+// Option configure le serveur MCP
+type Option func(*Server)
+
+// WithMaxLineSize borne la taille d'un message
+func WithMaxLineSize(size int) Option {
+	return func(s *Server) {
+		if size > 0 {
+			s.maxLineSize = size
+		}
+	}
+}
+
+// WithRecent définit le nombre d'événements récents retournés par l'outil recent_events sans last_event_id ni recent
+func WithRecent(recent int) Option {
+	return func(s *Server) {
+		if recent > 0 {
+			s.recent = recent
+		}
+	}
+}
+
+// WithMetrics mesure les appels de cas d'usage des outils et des ressources
+func WithMetrics(collector metrics.Collector) Option {
+	return func(s *Server) {
+		s.metrics = collector
+	}
+}
+
+// WithTracer démarre une trace pour chaque appel d'outil et chaque lecture de ressource
+func WithTracer(tracer *tracing.Tracer) Option {
+	return func(s *Server) {
+		s.tracer = tracer
+	}
+}
+
+// New crée un serveur MCP
+func New(useCases uc.UseCases, logger logger.Logger, options ...Option) *Server {
+	s := &Server{
+		logger:      logger,
+		maxLineSize: DefaultMaxLineSize,
+		recent:      DefaultRecent,
+		metrics:     metrics.Discard,
+	}
+	for _, option := range options {
+		option(s)
+	}
+	s.baseHandler = transport.NewBaseHandler(useCases, logger, transport.WithMetrics(s.metrics), transport.WithTracer(s.tracer))
+
+	s.tools = s.newTools()
+	s.resources = s.newResources()
+	return s
+}
+
+// Run lit les messages de in et écrit les réponses sur out, dans l'ordre des requêtes
+// Run retourne nil à la fin de in et l'erreur du contexte s'il est annulé.
+func (s *Server) Run(ctx context.Context, in io.Reader, out io.Writer) error {
+	lines := make(chan []byte)
+	readErr := make(chan error, 1)
+
+	go func() {
+		defer close(lines)
+
+		scanner := bufio.NewScanner(in)
+		// La capacité initiale compte dans la limite du scanner
+		scanner.Buffer(make([]byte, 0, min(64*1024, s.maxLineSize)), s.maxLineSize)
+		for scanner.Scan() {
+			// Le scanner réutilise son tampon
+			line := append([]byte(nil), scanner.Bytes()...)
+			select {
+			case lines <- line:
+			case <-ctx.Done():
+				readErr <- nil
+				return
+			}
+		}
+		readErr <- scanner.Err()
+	}()
+
+	for {
+		select {
+		case line, ok := <-lines:
+			if !ok {
+				if err := <-readErr; err != nil {
+					return fmt.Errorf("reading messages: %w", err)
+				}
+				return nil
+			}
+			if len(line) == 0 {
+				continue
+			}
+
+			reply := s.Handle(ctx, line)
+			if reply == nil {
+				continue
+			}
+			if _, err := out.Write(append(reply, '\n')); err != nil {
+				return fmt.Errorf("writing response: %w", err)
+			}
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// Handle traite un message et retourne la réponse encodée
+// Il retourne nil pour une notification, qui ne reçoit pas de réponse
+func (s *Server) Handle(ctx context.Context, payload []byte) []byte {
+	response, ok := s.call(ctx, payload)
+	if !ok {
+		return nil
+	}
+
+	data, err := json.Marshal(response)
+	if err != nil {
+		data, _ = json.Marshal(failure(response.ID, rpc.CodeInternalError, "Internal error: "+err.Error()))
+	}
+	return data
+}
+
+// call exécute une requête, ok est faux pour une notification
+func (s *Server) call(ctx context.Context, payload []byte) (response rpc.Response, ok bool) {
+	var req rpc.Request
+	if err := json.Unmarshal(payload, &req); err != nil {
+		return failure(nil, rpc.CodeParseError, "Parse error: "+err.Error()), true
+	}
+	if req.JSONRPC != rpc.Version || req.Method == "" {
+		return failure(req.ID, rpc.CodeInvalidRequest, "Invalid Request"), true
+	}
+
+	// Les notifications du client (notifications/initialized, notifications/cancelled) n'appellent pas de réponse
+	if len(req.ID) == 0 {
+		return rpc.Response{}, false
+	}
+
+	var (
+		result any
+		rpcErr *rpc.Error
+	)
+	switch req.Method {
+	case MethodInitialize:
+		result = map[string]any{
+			"protocolVersion": ProtocolVersion,
+			"capabilities": map[string]any{
+				"tools":     map[string]any{},
+				"resources": map[string]any{},
+			},
+			"serverInfo": serverInfo,
+		}
+	case MethodPing:
+		result = map[string]any{}
+	case MethodToolsList:
+		result = map[string]any{"tools": s.tools}
+	case MethodToolsCall:
+		result, rpcErr = s.callTool(ctx, req.Params)
+	case MethodResourcesList:
+		result = map[string]any{"resources": s.resources}
+	case MethodResourcesRead:
+		result, rpcErr = s.readResource(ctx, req.Params)
+	default:
+		rpcErr = &rpc.Error{Code: rpc.CodeMethodNotFound, Message: "Method not found: " + req.Method}
+	}
+
+	if rpcErr != nil {
+		return rpc.Response{JSONRPC: rpc.Version, Error: rpcErr, ID: req.ID}, true
+	}
+	return rpc.Response{JSONRPC: rpc.Version, Result: result, ID: req.ID}, true
+}
+
+// decodeParams décode les paramètres d'une requête
+func decodeParams(params json.RawMessage, v any) *rpc.Error {
+	if len(params) == 0 || string(params) == "null" {
+		return nil
+	}
+	if err := json.Unmarshal(params, v); err != nil {
+		return &rpc.Error{Code: rpc.CodeInvalidParams, Message: "Invalid params: " + err.Error()}
+	}
+	return nil
+}
+
+// failure construit une réponse d'erreur
+func failure(id json.RawMessage, code int, message string) rpc.Response {
+	if len(id) == 0 {
+		id = json.RawMessage("null")
+	}
+	return rpc.Response{JSONRPC: rpc.Version, Error: &rpc.Error{Code: code, Message: message}, ID: id}
+}
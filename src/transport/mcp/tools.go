@@ -0,0 +1,132 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"live-semantic/src/domain/dto"
+	"live-semantic/src/transport"
+	"live-semantic/src/transport/rpc"
+	"reflect"
+)
+
+// Outils exposés, un par opération des cas d'usage
+const (
+	ToolCreateTask   = "create_task"
+	ToolListTasks    = "list_tasks"
+	ToolListFilters  = "list_filters"
+	ToolListSources  = "list_sources"
+	ToolRecentEvents = "recent_events"
+)
+
+// tool outil exposé au client, son schéma d'entrée est dérivé du dto de la requête
+type tool struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	InputSchema Schema `json:"inputSchema"`
+	call        func(ctx context.Context, arguments json.RawMessage) (ToolResult, *rpc.Error)
+}
+
+// Content bloc de contenu d'un résultat
+type Content struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+// ToolResult résultat d'un appel d'outil
+// Une erreur d'un cas d'usage est un résultat avec IsError, pour que le modèle puisse la lire
+type ToolResult struct {
+	Content []Content `json:"content"`
+	IsError bool      `json:"isError"`
+}
+
+// toolCall paramètres de tools/call
+type toolCall struct {
+	Name      string          `json:"name"`
+	Arguments json.RawMessage `json:"arguments"`
+}
+
+// newTools construit les outils sur le handler de base
+func (s *Server) newTools() []tool {
+	return []tool{
+		newTool(ToolCreateTask, "Create a semantic analysis task on a video source.", s.baseHandler.HandleTask),
+		newTool(ToolListTasks, "List the tasks, optionally filtered by ID prefix.", s.baseHandler.HandleListTasks),
+		newTool(ToolListFilters, "List the semantic filters a task can detect.", s.baseHandler.HandleListFilters),
+		newTool(ToolListSources, "List the configured video sources.", s.baseHandler.HandleListSources),
+		newTool(ToolRecentEvents, "Return the recent domain events, such as task.created.", s.recentEvents),
+	}
+}
+
+// newTool associe un outil à une opération du handler de base
+func newTool[Req, Resp any](name, description string, handle func(transport.TransportRequest[Req]) transport.TransportResponse[Resp]) tool {
+	return tool{
+		Name:        name,
+		Description: description,
+		InputSchema: schemaOf(reflect.TypeFor[Req]()),
+		call: func(ctx context.Context, arguments json.RawMessage) (ToolResult, *rpc.Error) {
+			var req Req
+			if err := decodeParams(arguments, &req); err != nil {
+				return ToolResult{}, err
+			}
+
+			response := handle(transport.TransportRequest[Req]{
+				Data:    req,
+				Context: ctx,
+				Source:  source,
+			})
+			if !response.Success {
+				return ToolResult{Content: []Content{{Type: "text", Text: response.Error}}, IsError: true}, nil
+			}
+
+			text, err := json.Marshal(response.Data)
+			if err != nil {
+				return ToolResult{}, &rpc.Error{Code: rpc.CodeInternalError, Message: "Internal error: " + err.Error()}
+			}
+			return ToolResult{Content: []Content{{Type: "text", Text: string(text)}}}, nil
+		},
+	}
+}
+
+// callTool exécute tools/call
+func (s *Server) callTool(ctx context.Context, params json.RawMessage) (any, *rpc.Error) {
+	var call toolCall
+	if err := decodeParams(params, &call); err != nil {
+		return nil, err
+	}
+
+	for _, t := range s.tools {
+		if t.Name == call.Name {
+			return t.call(ctx, call.Arguments)
+		}
+	}
+	return nil, &rpc.Error{Code: rpc.CodeInvalidParams, Message: "Unknown tool: " + call.Name}
+}
+
+// recentEvents lit les événements rejoués par un abonnement puis y met fin
+// Sans LastEventID ni Recent, les derniers événements sont retournés, au plus le nombre défini par WithRecent.
+func (s *Server) recentEvents(req transport.TransportRequest[dto.EventRequest]) transport.TransportResponse[[]dto.EventResponse] {
+	if req.Data.LastEventID == 0 && req.Data.Recent == 0 {
+		req.Data.Recent = s.recent
+	}
+
+	ctx, cancel := context.WithCancel(req.Context)
+	defer cancel()
+
+	response := s.baseHandler.HandleSubscribeEvents(transport.TransportRequest[dto.EventRequest]{
+		Data:    req.Data,
+		Context: ctx,
+		Source:  req.Source,
+	})
+	if !response.Success {
+		return transport.TransportResponse[[]dto.EventResponse]{Success: false, Error: response.Error, Source: req.Source}
+	}
+
+	events := make([]dto.EventResponse, 0, response.Data.Replayed)
+	for len(events) < response.Data.Replayed {
+		event, ok := <-response.Data.Events
+		if !ok {
+			break
+		}
+		events = append(events, event)
+	}
+	return transport.TransportResponse[[]dto.EventResponse]{Success: true, Data: &events, Source: req.Source}
+}
@@ -0,0 +1,406 @@
+// Package output renders command results as a table, JSON, YAML, JSON lines or a Go template.
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"text/tabwriter"
+	"text/template"
+	"time"
+	"unicode"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Supported formats, a go-template format is written go-template=TEMPLATE.
+const (
+	FormatTable    = "table"
+	FormatJSON     = "json"
+	FormatYAML     = "yaml"
+	FormatJSONL    = "jsonl"
+	FormatTemplate = "go-template"
+)
+
+// Formats lists the values accepted by --output.
+var Formats = []string{FormatTable, FormatJSON, FormatYAML, FormatJSONL, FormatTemplate + "=..."}
+
+// timeLayout formats the dates of the table output.
+const timeLayout = "2006-01-02 15:04:05"
+
+// ANSI escape codes used when color is enabled.
+const (
+	colorReset  = "\x1b[0m"
+	colorBold   = "\x1b[1m"
+	colorRed    = "\x1b[31m"
+	colorGreen  = "\x1b[32m"
+	colorYellow = "\x1b[33m"
+)
+
+// Tabular is implemented by the results with a dedicated table layout.
+type Tabular interface {
+	Header() []string
+	Rows() [][]string
+}
+
+// Printer renders the results of a command.
+// Data goes to out, messages go to out in table format and to errOut otherwise,
+// so structured output stays parseable.
+type Printer struct {
+	out      io.Writer
+	errOut   io.Writer
+	format   string
+	template *template.Template
+	quiet    bool
+	color    bool
+}
+
+// Option configures a printer.
+type Option func(*Printer)
+
+// WithQuiet drops the informational messages.
+func WithQuiet(quiet bool) Option {
+	return func(p *Printer) {
+		p.quiet = quiet
+	}
+}
+
+// WithColor enables the ANSI colors of messages and table headers.
+func WithColor(color bool) Option {
+	return func(p *Printer) {
+		p.color = color
+	}
+}
+
+// New creates a printer for an --output value.
+func New(out, errOut io.Writer, spec string, options ...Option) (*Printer, error) {
+	p := &Printer{out: out, errOut: errOut, format: spec}
+
+	switch {
+	case spec == "" || spec == FormatTable:
+		p.format = FormatTable
+	case spec == FormatJSON, spec == FormatYAML, spec == FormatJSONL:
+	case strings.HasPrefix(spec, FormatTemplate+"="):
+		tmpl, err := template.New("output").Parse(strings.TrimPrefix(spec, FormatTemplate+"="))
+		if err != nil {
+			return nil, fmt.Errorf("invalid output template: %w", err)
+		}
+		p.format = FormatTemplate
+		p.template = tmpl
+	default:
+		return nil, fmt.Errorf("unsupported output format %q (available: %s)", spec, strings.Join(Formats, ", "))
+	}
+
+	for _, option := range options {
+		option(p)
+	}
+	return p, nil
+}
+
+// Format returns the output format, without the template of go-template.
+func (p *Printer) Format() string {
+	return p.format
+}
+
+// Structured reports a machine readable format.
+func (p *Printer) Structured() bool {
+	return p.format != FormatTable
+}
+
+// Quiet reports whether informational messages are dropped.
+func (p *Printer) Quiet() bool {
+	return p.quiet
+}
+
+// Print renders a result in the output format.
+func (p *Printer) Print(v any) error {
+	switch p.format {
+	case FormatJSON:
+		data, err := json.MarshalIndent(v, "", "  ")
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintf(p.out, "%s\n", data)
+		return err
+	case FormatJSONL:
+		return p.printLines(v)
+	case FormatYAML:
+		node, err := toYAML(v)
+		if err != nil {
+			return err
+		}
+		encoder := yaml.NewEncoder(p.out)
+		encoder.SetIndent(2)
+		if err := encoder.Encode(node); err != nil {
+			return err
+		}
+		return encoder.Close()
+	case FormatTemplate:
+		generic, err := toGeneric(v)
+		if err != nil {
+			return err
+		}
+		var buf bytes.Buffer
+		if err := p.template.Execute(&buf, generic); err != nil {
+			return fmt.Errorf("executing output template: %w", err)
+		}
+		if !bytes.HasSuffix(buf.Bytes(), []byte("\n")) {
+			buf.WriteByte('\n')
+		}
+		_, err = p.out.Write(buf.Bytes())
+		return err
+	default:
+		return p.printTable(v)
+	}
+}
+
+// Success prints a success message unless quiet.
+func (p *Printer) Success(format string, args ...any) {
+	p.message("✅ ", colorGreen, format, args...)
+}
+
+// Info prints an informational message unless quiet.
+func (p *Printer) Info(format string, args ...any) {
+	p.message("", "", format, args...)
+}
+
+// Warn prints a warning unless quiet.
+func (p *Printer) Warn(format string, args ...any) {
+	p.message("⚠️  ", colorYellow, format, args...)
+}
+
+// Error prints an error to errOut, even when quiet.
+func (p *Printer) Error(err error) {
+	fmt.Fprintln(p.errOut, p.paint(colorRed, "❌ Error: "+err.Error()))
+}
+
+// Bold highlights a label when color is enabled.
+func (p *Printer) Bold(s string) string {
+	return p.paint(colorBold, s)
+}
+
+func (p *Printer) message(prefix, color, format string, args ...any) {
+	if p.quiet {
+		return
+	}
+	w := p.out
+	if p.Structured() {
+		w = p.errOut
+	}
+	fmt.Fprintln(w, p.paint(color, prefix+fmt.Sprintf(format, args...)))
+}
+
+func (p *Printer) paint(color, s string) string {
+	if !p.color || color == "" {
+		return s
+	}
+	return color + s + colorReset
+}
+
+// printLines writes one compact JSON document per slice element.
+func (p *Printer) printLines(v any) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		rv = reflect.ValueOf([]any{v})
+	}
+
+	encoder := json.NewEncoder(p.out)
+	for i := 0; i < rv.Len(); i++ {
+		if err := encoder.Encode(rv.Index(i).Interface()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// printTable writes a Tabular result, a slice of structs as columns or a struct as labelled lines.
+func (p *Printer) printTable(v any) error {
+	w := tabwriter.NewWriter(p.out, 0, 0, 2, ' ', 0)
+
+	if t, ok := v.(Tabular); ok {
+		p.writeRows(w, t.Header(), t.Rows())
+		return w.Flush()
+	}
+
+	rv := reflect.Indirect(reflect.ValueOf(v))
+	switch {
+	case rv.Kind() == reflect.Slice && structType(rv.Type().Elem()) != nil:
+		t := structType(rv.Type().Elem())
+		var header []string
+		for _, f := range fields(t) {
+			header = append(header, strings.ToUpper(label(f.Name)))
+		}
+		var rows [][]string
+		for i := 0; i < rv.Len(); i++ {
+			item := reflect.Indirect(rv.Index(i))
+			var row []string
+			for _, f := range fields(t) {
+				row = append(row, cell(item.FieldByIndex(f.Index)))
+			}
+			rows = append(rows, row)
+		}
+		p.writeRows(w, header, rows)
+	case rv.Kind() == reflect.Struct:
+		for _, f := range fields(rv.Type()) {
+			fmt.Fprintf(w, "   %s:\t%s\n", label(f.Name), cell(rv.FieldByIndex(f.Index)))
+		}
+	default:
+		fmt.Fprintln(w, cell(rv))
+	}
+	return w.Flush()
+}
+
+func (p *Printer) writeRows(w io.Writer, header []string, rows [][]string) {
+	if len(header) > 0 {
+		// Painting each cell keeps the same escape overhead in every column
+		cells := make([]string, len(header))
+		for i, h := range header {
+			cells[i] = p.Bold(h)
+		}
+		fmt.Fprintln(w, strings.Join(cells, "\t"))
+	}
+	for _, row := range rows {
+		fmt.Fprintln(w, strings.Join(row, "\t"))
+	}
+}
+
+// structType returns the struct type behind t, or nil.
+func structType(t reflect.Type) reflect.Type {
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct || t == reflect.TypeOf(time.Time{}) {
+		return nil
+	}
+	return t
+}
+
+// fields returns the exported fields that are part of the JSON output.
+func fields(t reflect.Type) []reflect.StructField {
+	var out []reflect.StructField
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() || f.Tag.Get("json") == "-" {
+			continue
+		}
+		out = append(out, f)
+	}
+	return out
+}
+
+// label turns a field name into a label, CreatedAt becomes "Created At".
+func label(name string) string {
+	var b strings.Builder
+	runes := []rune(name)
+	for i, r := range runes {
+		if i > 0 && unicode.IsUpper(r) && (unicode.IsLower(runes[i-1]) || (i+1 < len(runes) && unicode.IsLower(runes[i+1]))) {
+			b.WriteByte(' ')
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// cell formats a value for the table output.
+func cell(v reflect.Value) string {
+	if !v.IsValid() {
+		return ""
+	}
+	if v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return ""
+		}
+		v = v.Elem()
+	}
+
+	switch value := v.Interface().(type) {
+	case time.Time:
+		return value.Format(timeLayout)
+	case time.Duration:
+		return value.String()
+	case fmt.Stringer:
+		return value.String()
+	}
+
+	switch v.Kind() {
+	case reflect.Slice, reflect.Array:
+		items := make([]string, v.Len())
+		for i := range items {
+			items[i] = cell(v.Index(i))
+		}
+		return strings.Join(items, ", ")
+	default:
+		return fmt.Sprint(v.Interface())
+	}
+}
+
+// toGeneric converts a value to maps and slices keyed by the JSON field names.
+func toGeneric(v any) (any, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var generic any
+	if err := json.Unmarshal(data, &generic); err != nil {
+		return nil, err
+	}
+	return generic, nil
+}
+
+// toYAML converts a value to a yaml node keyed by the JSON field names, keeping the field order.
+func toYAML(v any) (*yaml.Node, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	return yamlValue(decoder)
+}
+
+func yamlValue(decoder *json.Decoder) (*yaml.Node, error) {
+	token, err := decoder.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	switch t := token.(type) {
+	case json.Delim:
+		node := &yaml.Node{Kind: yaml.SequenceNode}
+		if t == '{' {
+			node.Kind = yaml.MappingNode
+		}
+		for decoder.More() {
+			if node.Kind == yaml.MappingNode {
+				key, err := decoder.Token()
+				if err != nil {
+					return nil, err
+				}
+				node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: key.(string)})
+			}
+			child, err := yamlValue(decoder)
+			if err != nil {
+				return nil, err
+			}
+			node.Content = append(node.Content, child)
+		}
+		// Consume the closing delimiter
+		if _, err := decoder.Token(); err != nil {
+			return nil, err
+		}
+		return node, nil
+	case json.Number:
+		return &yaml.Node{Kind: yaml.ScalarNode, Value: t.String()}, nil
+	case nil:
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!null", Value: "null"}, nil
+	default:
+		node := &yaml.Node{}
+		if err := node.Encode(t); err != nil {
+			return nil, err
+		}
+		return node, nil
+	}
+}
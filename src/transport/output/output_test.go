@@ -0,0 +1,127 @@
+package output_test
+
+import (
+	"bytes"
+	"errors"
+	"live-semantic/src/transport/output"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type task struct {
+	ID        string    `json:"id"`
+	Title     string    `json:"title"`
+	CreatedAt time.Time `json:"created_at"`
+	Internal  string    `json:"-"`
+}
+
+var createdAt = time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+
+func render(t *testing.T, spec string, v any, options ...output.Option) (string, string) {
+	t.Helper()
+
+	var out, errOut bytes.Buffer
+	printer, err := output.New(&out, &errOut, spec, options...)
+	if !assert.NoError(t, err) {
+		return "", ""
+	}
+	printer.Success("done")
+	assert.NoError(t, printer.Print(v))
+	return out.String(), errOut.String()
+}
+
+func TestPrinter_Print(t *testing.T) {
+	item := task{ID: "1", Title: "first", CreatedAt: createdAt, Internal: "hidden"}
+
+	t.Run("should render a struct as labelled lines", func(t *testing.T) {
+		// When
+		out, errOut := render(t, output.FormatTable, item)
+
+		// Then
+		assert.Equal(t, "✅ done\n   ID:          1\n   Title:       first\n   Created At:  2025-01-02 03:04:05\n", out)
+		assert.Empty(t, errOut)
+	})
+
+	t.Run("should render a slice as columns", func(t *testing.T) {
+		// When
+		out, _ := render(t, output.FormatTable, []task{item, {ID: "2", Title: "second", CreatedAt: createdAt}}, output.WithQuiet(true))
+
+		// Then
+		assert.Equal(t, "ID  TITLE   CREATED AT\n1   first   2025-01-02 03:04:05\n2   second  2025-01-02 03:04:05\n", out)
+	})
+
+	t.Run("should keep messages out of structured output", func(t *testing.T) {
+		// When
+		out, errOut := render(t, output.FormatJSON, item)
+
+		// Then
+		assert.JSONEq(t, `{"id":"1","title":"first","created_at":"2025-01-02T03:04:05Z"}`, out)
+		assert.Equal(t, "✅ done\n", errOut)
+	})
+
+	t.Run("should render yaml in field order", func(t *testing.T) {
+		// When
+		out, _ := render(t, output.FormatYAML, map[string]any{"count": 2, "ratio": 0.5, "items": []task{item}})
+
+		// Then
+		assert.Equal(t, "count: 2\nitems:\n  - id: \"1\"\n    title: first\n    created_at: \"2025-01-02T03:04:05Z\"\nratio: 0.5\n", out)
+	})
+
+	t.Run("should render one json document per line", func(t *testing.T) {
+		// When
+		out, _ := render(t, output.FormatJSONL, []task{item, item})
+
+		// Then
+		line := `{"id":"1","title":"first","created_at":"2025-01-02T03:04:05Z"}` + "\n"
+		assert.Equal(t, line+line, out)
+	})
+
+	t.Run("should execute a go template on the json fields", func(t *testing.T) {
+		// When
+		out, _ := render(t, "go-template={{.id}}: {{.title}}", item)
+
+		// Then
+		assert.Equal(t, "1: first\n", out)
+	})
+
+	t.Run("should reject unknown formats and invalid templates", func(t *testing.T) {
+		// When
+		_, formatErr := output.New(nil, nil, "xml")
+		_, templateErr := output.New(nil, nil, "go-template={{.id")
+
+		// Then
+		assert.Error(t, formatErr)
+		assert.Error(t, templateErr)
+	})
+}
+
+func TestPrinter_Messages(t *testing.T) {
+	t.Run("should drop messages when quiet but keep errors", func(t *testing.T) {
+		// Given
+		var out, errOut bytes.Buffer
+		printer, _ := output.New(&out, &errOut, output.FormatTable, output.WithQuiet(true))
+
+		// When
+		printer.Success("done")
+		printer.Warn("careful")
+		printer.Error(errors.New("failed"))
+
+		// Then
+		assert.Empty(t, out.String())
+		assert.Equal(t, "❌ Error: failed\n", errOut.String())
+	})
+
+	t.Run("should color messages only when enabled", func(t *testing.T) {
+		// Given
+		var out bytes.Buffer
+		printer, _ := output.New(&out, &out, output.FormatTable, output.WithColor(true))
+
+		// When
+		printer.Success("done")
+
+		// Then
+		assert.Equal(t, "\x1b[32m✅ done\x1b[0m\n", out.String())
+	})
+}
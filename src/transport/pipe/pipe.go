@@ -0,0 +1,315 @@
+// Package pipe pilote les cas d'usage en JSON Lines sur l'entrée et la sortie standard
+package pipe
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"live-semantic/src/domain/uc"
+	"live-semantic/src/metrics"
+	"live-semantic/src/tracing"
+	"live-semantic/src/transport"
+	"sync"
+
+	"github.com/deadelus/go-clean-app/src/logger"
+)
+
+// DefaultConcurrency nombre de requêtes traitées en parallèle par défaut
+const DefaultConcurrency = 4
+
+// DefaultMaxLineSize taille maximale d'une ligne de requête par défaut
+const DefaultMaxLineSize = 1 << 20
+
+// source identifie le transport dans les réponses
+const source = "pipe"
+
+// Types de messages acceptés : la création de tâche du WebSocket et les listes des cas d'usage
+const (
+	TypeTask        = "Task"
+	TypeListTasks   = "ListTasks"
+	TypeListFilters = "ListFilters"
+	TypeListSources = "ListSources"
+)
+
+// Message représente une ligne de requête
+// L'enveloppe reprend celle du WebSocket, avec un identifiant renvoyé tel quel dans la réponse
+type Message struct {
+	ID   json.RawMessage `json:"id,omitempty"`
+	Type string          `json:"type"`
+	Data json.RawMessage `json:"data"`
+}
+
+// errLineTooLong signale une ligne de requête dépassant la taille maximale
+var errLineTooLong = errors.New("line too long")
+
+// line ligne de requête lue, numérotée à partir de 1
+type line struct {
+	number  int
+	data    []byte
+	tooLong bool
+}
+
+// Response représente une ligne de réponse
+type Response[T any] struct {
+	ID json.RawMessage `json:"id,omitempty"`
+	transport.TransportResponse[T]
+}
+
+// Pipe traite les requêtes d'un flux JSON Lines
+type Pipe struct {
+	useCases    uc.UseCases
+	logger      logger.Logger
+	concurrency int
+	maxLineSize int
+	metrics     metrics.Collector
+	tracer      *tracing.Tracer
+	writeMu     sync.Mutex
+}
+
+// Option configure le pipe
+type Option func(*Pipe)
+
+// WithConcurrency borne le nombre de requêtes traitées en parallèle
+func WithConcurrency(concurrency int) Option {
+	return func(p *Pipe) {
+		if concurrency > 0 {
+			p.concurrency = concurrency
+		}
+	}
+}
+
+// WithMaxLineSize borne la taille d'une ligne de requête
+func WithMaxLineSize(size int) Option {
+	return func(p *Pipe) {
+		if size > 0 {
+			p.maxLineSize = size
+		}
+	}
+}
+
+// WithMetrics mesure les appels de cas d'usage des requêtes
+func WithMetrics(collector metrics.Collector) Option {
+	return func(p *Pipe) {
+		p.metrics = collector
+	}
+}
+
+// WithTracer démarre une trace pour chaque requête
+func WithTracer(tracer *tracing.Tracer) Option {
+	return func(p *Pipe) {
+		p.tracer = tracer
+	}
+}
+
+// New crée un pipe
+func New(useCases uc.UseCases, logger logger.Logger, options ...Option) *Pipe {
+	p := &Pipe{
+		useCases:    useCases,
+		logger:      logger,
+		concurrency: DefaultConcurrency,
+		maxLineSize: DefaultMaxLineSize,
+		metrics:     metrics.Discard,
+	}
+	for _, option := range options {
+		option(p)
+	}
+	return p
+}
+
+// Run lit les requêtes de in et écrit une réponse par requête sur out
+// Les réponses sont écrites dans l'ordre de fin de traitement, l'identifiant permet de les associer.
+// Une ligne dépassant la taille maximale est ignorée et reçoit une réponse d'erreur sans identifiant.
+// Run retourne nil à la fin de in, après la dernière réponse, et l'erreur du contexte s'il est annulé.
+func (p *Pipe) Run(ctx context.Context, in io.Reader, out io.Writer) error {
+	lines := make(chan line)
+	readErr := make(chan error, 1)
+
+	go func() {
+		defer close(lines)
+
+		reader := bufio.NewReaderSize(in, min(64*1024, p.maxLineSize))
+		for number := 1; ; number++ {
+			data, err := readLine(reader, p.maxLineSize)
+			if errors.Is(err, io.EOF) {
+				readErr <- nil
+				return
+			}
+			if err != nil && !errors.Is(err, errLineTooLong) {
+				readErr <- err
+				return
+			}
+
+			select {
+			case lines <- line{number: number, data: data, tooLong: err != nil}:
+			case <-ctx.Done():
+				readErr <- nil
+				return
+			}
+		}
+	}()
+
+	var (
+		wg       sync.WaitGroup
+		writeErr error
+		errOnce  sync.Once
+	)
+	slots := make(chan struct{}, p.concurrency)
+
+	write := func(response any) {
+		if err := p.write(out, response); err != nil {
+			errOnce.Do(func() { writeErr = err })
+		}
+	}
+
+loop:
+	for {
+		select {
+		case line, ok := <-lines:
+			if !ok {
+				break loop
+			}
+			if line.tooLong {
+				write(failure(nil, fmt.Sprintf("Line %d exceeds the maximum size of %d bytes", line.number, p.maxLineSize)))
+				continue
+			}
+			if len(line.data) == 0 {
+				continue
+			}
+
+			select {
+			case slots <- struct{}{}:
+			case <-ctx.Done():
+				break loop
+			}
+
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				defer func() { <-slots }()
+				write(p.handle(ctx, line.data))
+			}()
+		case <-ctx.Done():
+			break loop
+		}
+	}
+
+	wg.Wait()
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if err := <-readErr; err != nil {
+		return fmt.Errorf("reading requests: %w", err)
+	}
+	return writeErr
+}
+
+// readLine lit une ligne sans son saut de ligne, io.EOF à la fin de r
+// Au-delà de maxSize octets, le reste de la ligne est lu sans être gardé et readLine retourne errLineTooLong.
+func readLine(r *bufio.Reader, maxSize int) ([]byte, error) {
+	var data []byte
+	tooLong := false
+	for {
+		chunk, err := r.ReadSlice('\n')
+		if !tooLong {
+			data = append(data, chunk...)
+			tooLong = len(bytes.TrimSuffix(data, []byte("\n"))) > maxSize
+		}
+		if errors.Is(err, bufio.ErrBufferFull) {
+			continue
+		}
+		if err != nil && !errors.Is(err, io.EOF) {
+			return nil, err
+		}
+		if err != nil && len(data) == 0 {
+			return nil, io.EOF
+		}
+		if tooLong {
+			return nil, errLineTooLong
+		}
+		// Comme bufio.ScanLines, le retour chariot précédant le saut de ligne est retiré
+		return bytes.TrimSuffix(bytes.TrimSuffix(data, []byte("\n")), []byte("\r")), nil
+	}
+}
+
+// handle décode une ligne et la transmet au handler de base
+func (p *Pipe) handle(ctx context.Context, line []byte) any {
+	var msg Message
+	if err := json.Unmarshal(line, &msg); err != nil {
+		return failure(nil, "Invalid JSON: "+err.Error())
+	}
+
+	baseHandler := transport.NewBaseHandler(p.useCases, p.logger, transport.WithMetrics(p.metrics), transport.WithTracer(p.tracer))
+
+	switch msg.Type {
+	case TypeTask:
+		return dispatch(ctx, msg, baseHandler.HandleTask)
+	case TypeListTasks:
+		return dispatch(ctx, msg, baseHandler.HandleListTasks)
+	case TypeListFilters:
+		return dispatch(ctx, msg, baseHandler.HandleListFilters)
+	case TypeListSources:
+		return dispatch(ctx, msg, baseHandler.HandleListSources)
+	default:
+		return failure(msg.ID, "Unknown message type: "+msg.Type)
+	}
+}
+
+// dispatch décode les données du message et exécute le handler
+func dispatch[Req, Resp any](
+	ctx context.Context,
+	msg Message,
+	handle func(transport.TransportRequest[Req]) transport.TransportResponse[Resp],
+) any {
+	var req Req
+	if len(msg.Data) > 0 && string(msg.Data) != "null" {
+		if err := json.Unmarshal(msg.Data, &req); err != nil {
+			return failure(msg.ID, "Invalid data format: "+err.Error())
+		}
+	}
+
+	return Response[Resp]{
+		ID: msg.ID,
+		TransportResponse: handle(transport.TransportRequest[Req]{
+			Data:    req,
+			Context: ctx,
+			Source:  source,
+		}),
+	}
+}
+
+// failure construit une réponse d'erreur
+func failure(id json.RawMessage, message string) Response[struct{}] {
+	return Response[struct{}]{
+		ID: id,
+		TransportResponse: transport.TransportResponse[struct{}]{
+			Success: false,
+			Error:   message,
+			Source:  source,
+		},
+	}
+}
+
+// write écrit une réponse sur une ligne, les écritures concurrentes ne s'entrelacent pas
+func (p *Pipe) write(out io.Writer, response any) error {
+	data, err := json.Marshal(response)
+	if err != nil {
+		p.logger.Error("Failed to encode pipe response", map[string]interface{}{
+			"error": err.Error(),
+		})
+		return err
+	}
+	data = append(data, '\n')
+
+	p.writeMu.Lock()
+	defer p.writeMu.Unlock()
+
+	if _, err := out.Write(data); err != nil {
+		return fmt.Errorf("writing response: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,108 @@
+package pipe_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"live-semantic/src/domain/uc"
+	"live-semantic/src/logging"
+	"live-semantic/src/transport/pipe"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type response struct {
+	ID      json.RawMessage `json:"id"`
+	Success bool            `json:"success"`
+	Error   string          `json:"error"`
+	Source  string          `json:"source"`
+}
+
+func newPipe(t *testing.T, options ...pipe.Option) *pipe.Pipe {
+	t.Helper()
+
+	useCases, err := uc.NewUseCase(logging.Discard)
+	assert.NoError(t, err)
+	return pipe.New(useCases, logging.Discard, options...)
+}
+
+// decode lit les réponses indexées par identifiant
+func decode(t *testing.T, out string) map[string]response {
+	t.Helper()
+
+	responses := map[string]response{}
+	for _, line := range strings.Split(strings.TrimSuffix(out, "\n"), "\n") {
+		var r response
+		if assert.NoError(t, json.Unmarshal([]byte(line), &r), line) {
+			responses[string(r.ID)] = r
+		}
+	}
+	return responses
+}
+
+func TestPipe_Run(t *testing.T) {
+	t.Run("should answer each request with its id", func(t *testing.T) {
+		// Given
+		in := strings.NewReader(`{"id":1,"type":"Task","data":{"title":"title"}}` + "\n\n" +
+			`{"id":"two","type":"ListFilters","data":{"prefix":"fight"}}` + "\n" +
+			`{"id":3,"type":"Unknown"}` + "\n" +
+			`not json` + "\n")
+		var out bytes.Buffer
+
+		// When
+		err := newPipe(t).Run(context.Background(), in, &out)
+
+		// Then
+		assert.NoError(t, err)
+		responses := decode(t, out.String())
+		assert.Len(t, responses, 4)
+		assert.True(t, responses["1"].Success)
+		assert.True(t, responses[`"two"`].Success)
+		assert.Equal(t, "Unknown message type: Unknown", responses["3"].Error)
+		assert.Contains(t, responses[""].Error, "Invalid JSON")
+		assert.Equal(t, "pipe", responses["1"].Source)
+	})
+
+	t.Run("should answer a line longer than the limit with an error and go on", func(t *testing.T) {
+		// Given
+		in := strings.NewReader(`{"id":1,"type":"ListTasks"}` + "\n" +
+			`{"id":2,"type":"Task","data":{"title":"` + strings.Repeat("x", 200) + `"}}` + "\n" +
+			`{"id":3,"type":"ListTasks"}` + "\r\n")
+		var out bytes.Buffer
+
+		// When
+		err := newPipe(t, pipe.WithMaxLineSize(64)).Run(context.Background(), in, &out)
+
+		// Then
+		assert.NoError(t, err)
+		responses := decode(t, out.String())
+		assert.Len(t, responses, 3)
+		assert.True(t, responses["1"].Success)
+		assert.True(t, responses["3"].Success)
+		assert.Equal(t, "Line 2 exceeds the maximum size of 64 bytes", responses[""].Error)
+	})
+
+	t.Run("should stop reading when the context is cancelled", func(t *testing.T) {
+		// Given
+		reader, writer := io.Pipe()
+		defer writer.Close()
+		ctx, cancel := context.WithCancel(context.Background())
+		done := make(chan error, 1)
+
+		// When
+		go func() { done <- newPipe(t).Run(ctx, reader, io.Discard) }()
+		cancel()
+
+		// Then
+		select {
+		case err := <-done:
+			assert.ErrorIs(t, err, context.Canceled)
+		case <-time.After(time.Second):
+			t.Fatal("pipe still running after cancellation")
+		}
+	})
+}
@@ -0,0 +1,97 @@
+package ratelimit
+
+import (
+	"live-semantic/src/auth"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ClientKey identifie le client d'une requête : le sujet du principal authentifié, sinon l'adresse IP
+// Les limites par client suivent l'authentification, seule IPMiddleware la précède.
+// L'adresse IP n'est lue dans X-Forwarded-For que si le moteur gin fait confiance au proxy émetteur.
+func ClientKey(c *gin.Context) string {
+	if principal, ok := auth.PrincipalFrom(c.Request.Context()); ok && principal.Subject != "" {
+		return "principal:" + principal.Subject
+	}
+	return "ip:" + c.ClientIP()
+}
+
+// RouteKey clé de la route d'une requête REST, telle qu'attendue par les surcharges
+func RouteKey(c *gin.Context) string {
+	return c.Request.Method + " " + c.FullPath()
+}
+
+// RetryAfter délai avant un nouvel essai en secondes entières, arrondi au supérieur comme l'en-tête Retry-After
+func RetryAfter(wait time.Duration) int {
+	return max(1, int(math.Ceil(wait.Seconds())))
+}
+
+// IPMiddleware applique la limite grossière de l'adresse IP du client, à placer avant l'authentification
+// pour compter aussi les requêtes refusées en 401. Une requête refusée reçoit 429 avec l'en-tête Retry-After.
+func (l *Limiter) IPMiddleware(source string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if l == nil {
+			c.Next()
+			return
+		}
+
+		if ok, wait := l.AllowIP(c.ClientIP()); !ok {
+			c.Header("Retry-After", strconv.Itoa(RetryAfter(wait)))
+			c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{"success": false, "error": "rate limit exceeded", "source": source})
+			return
+		}
+		c.Next()
+	}
+}
+
+// Middleware limite le débit de la route et les appels simultanés du client, à placer après l'authentification
+// pour que chaque principal ait ses propres seaux. Une requête refusée reçoit 429 avec l'en-tête Retry-After.
+// Un limiteur nil laisse tout passer.
+func (l *Limiter) Middleware(source string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if l == nil {
+			c.Next()
+			return
+		}
+
+		client := ClientKey(c)
+		if ok, wait := l.Allow(client, RouteKey(c)); !ok {
+			c.Header("Retry-After", strconv.Itoa(RetryAfter(wait)))
+			c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{"success": false, "error": "rate limit exceeded", "source": source})
+			return
+		}
+
+		release, ok := l.Acquire(client)
+		if !ok {
+			c.Header("Retry-After", "1")
+			c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{"success": false, "error": "too many concurrent requests", "source": source})
+			return
+		}
+		defer release()
+
+		c.Next()
+	}
+}
+
+// StreamMiddleware limite le débit des ouvertures de flux sans les compter parmi les appels simultanés
+// Un flux d'événements ou une session WebSocket reste ouvert bien au-delà d'un appel de cas d'usage.
+// Comme Middleware, il suit l'authentification.
+func (l *Limiter) StreamMiddleware(source string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if l == nil {
+			c.Next()
+			return
+		}
+
+		if ok, wait := l.Allow(ClientKey(c), RouteKey(c)); !ok {
+			c.Header("Retry-After", strconv.Itoa(RetryAfter(wait)))
+			c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{"success": false, "error": "rate limit exceeded", "source": source})
+			return
+		}
+		c.Next()
+	}
+}
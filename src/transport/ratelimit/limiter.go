@@ -0,0 +1,299 @@
+// Package ratelimit limite le débit et le nombre d'appels simultanés de chaque client des transports réseau
+package ratelimit
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Valeurs par défaut des limites
+const (
+	DefaultRate        = 50.0
+	DefaultBurst       = 100
+	DefaultMaxInFlight = 32
+	// DefaultIPRate et DefaultIPBurst limite grossière d'une adresse IP, partagée par ses principaux
+	DefaultIPRate  = 200.0
+	DefaultIPBurst = 400
+)
+
+// ipBucket clé du seau de la limite par adresse IP, distincte des routes et des types de message
+const ipBucket = "ip"
+
+// idleTimeout délai après lequel un client inactif est oublié
+const idleTimeout = 10 * time.Minute
+
+// sweepInterval intervalle minimal entre deux purges des clients inactifs
+const sweepInterval = time.Minute
+
+// Limit débit d'un seau à jetons : Rate jetons par seconde, jusqu'à Burst jetons
+// Un débit nul ou négatif désactive la limite
+type Limit struct {
+	Rate  float64
+	Burst int
+}
+
+// Unlimited indique une limite désactivée
+func (l Limit) Unlimited() bool {
+	return l.Rate <= 0
+}
+
+// ParseOverride lit une surcharge KEY=RATE:BURST
+// KEY est une route REST ("POST /api/v1/createTask") ou un type de message ("ws:Task", "rpc:ws").
+func ParseOverride(override string) (string, Limit, error) {
+	i := strings.LastIndex(override, "=")
+	if i <= 0 {
+		return "", Limit{}, fmt.Errorf("%q must be KEY=RATE:BURST", override)
+	}
+
+	key, value := strings.TrimSpace(override[:i]), override[i+1:]
+	rate, burst, ok := strings.Cut(value, ":")
+	if !ok {
+		return "", Limit{}, fmt.Errorf("%q must be KEY=RATE:BURST", override)
+	}
+	r, err := strconv.ParseFloat(rate, 64)
+	if err != nil || math.IsNaN(r) || math.IsInf(r, 0) {
+		return "", Limit{}, fmt.Errorf("%q: invalid rate %q", override, rate)
+	}
+	b, err := strconv.Atoi(burst)
+	if err != nil || b < 0 || (r > 0 && b < 1) {
+		return "", Limit{}, fmt.Errorf("%q: invalid burst %q", override, burst)
+	}
+	return key, Limit{Rate: r, Burst: b}, nil
+}
+
+// Stats état courant du limiteur
+type Stats struct {
+	Clients   int    `json:"clients"`
+	InFlight  int    `json:"in_flight"`
+	Allowed   uint64 `json:"allowed"`
+	Throttled uint64 `json:"throttled"`
+	Rejected  uint64 `json:"rejected"`
+}
+
+// bucket seau à jetons d'un client pour une clé
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// client état d'un client
+type client struct {
+	buckets  map[string]*bucket
+	inFlight int
+	lastSeen time.Time
+}
+
+// Limiter applique les limites de débit et d'appels simultanés par client
+// Un *Limiter nil laisse tout passer.
+type Limiter struct {
+	mu          sync.Mutex
+	limit       Limit
+	ipLimit     Limit
+	overrides   map[string]Limit
+	maxInFlight int
+	clients     map[string]*client
+	lastSweep   time.Time
+	now         func() time.Time
+	allowed     uint64
+	throttled   uint64
+	rejected    uint64
+}
+
+// Option configure le limiteur
+type Option func(*Limiter)
+
+// WithLimit définit la limite de débit par défaut
+func WithLimit(limit Limit) Option {
+	return func(l *Limiter) {
+		l.limit = limit
+	}
+}
+
+// WithIPLimit définit la limite de débit d'une adresse IP, appliquée avant l'authentification
+func WithIPLimit(limit Limit) Option {
+	return func(l *Limiter) {
+		l.ipLimit = limit
+	}
+}
+
+// WithOverride remplace la limite de débit d'une route ou d'un type de message
+func WithOverride(key string, limit Limit) Option {
+	return func(l *Limiter) {
+		l.overrides[key] = limit
+	}
+}
+
+// WithMaxInFlight borne les appels simultanés d'un client, 0 pour ne pas les borner
+func WithMaxInFlight(max int) Option {
+	return func(l *Limiter) {
+		l.maxInFlight = max
+	}
+}
+
+// WithClock remplace l'horloge, utile aux tests
+func WithClock(now func() time.Time) Option {
+	return func(l *Limiter) {
+		l.now = now
+	}
+}
+
+// New crée un limiteur
+func New(options ...Option) *Limiter {
+	l := &Limiter{
+		limit:       Limit{Rate: DefaultRate, Burst: DefaultBurst},
+		ipLimit:     Limit{Rate: DefaultIPRate, Burst: DefaultIPBurst},
+		overrides:   map[string]Limit{},
+		maxInFlight: DefaultMaxInFlight,
+		clients:     map[string]*client{},
+		now:         time.Now,
+	}
+	for _, option := range options {
+		option(l)
+	}
+	return l
+}
+
+// Reconfigure remplace les limites par celles des options, y compris pendant l'exécution
+// Les limites absentes des options reprennent leur valeur par défaut, l'état des clients est conservé
+func (l *Limiter) Reconfigure(options ...Option) {
+	if l == nil {
+		return
+	}
+	next := New(options...)
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.limit = next.limit
+	l.ipLimit = next.ipLimit
+	l.overrides = next.overrides
+	l.maxInFlight = next.maxInFlight
+}
+
+// Allow consomme un jeton du client pour la clé, une route ou un type de message
+// Lorsque le seau est vide, il retourne false et le délai avant le prochain jeton.
+func (l *Limiter) Allow(clientKey, key string) (bool, time.Duration) {
+	if l == nil {
+		return true, 0
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	// Les clés sans surcharge partagent le seau par défaut du client
+	limit, ok := l.overrides[key]
+	if !ok {
+		key, limit = "", l.limit
+	}
+	return l.take(clientKey, key, limit)
+}
+
+// AllowIP consomme un jeton de la limite grossière de l'adresse IP
+// Elle précède l'authentification : les requêtes refusées en 401 y sont comptées, et les principaux
+// d'une même adresse, derrière un NAT par exemple, la partagent en plus de leur propre limite.
+func (l *Limiter) AllowIP(ip string) (bool, time.Duration) {
+	if l == nil {
+		return true, 0
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.take("ip:"+ip, ipBucket, l.ipLimit)
+}
+
+// take consomme un jeton du seau key du client, l.mu doit être verrouillé
+func (l *Limiter) take(clientKey, key string, limit Limit) (bool, time.Duration) {
+	if limit.Unlimited() {
+		return true, 0
+	}
+
+	now := l.now()
+	c := l.client(clientKey, now)
+	b, ok := c.buckets[key]
+	if !ok {
+		b = &bucket{tokens: float64(limit.Burst), last: now}
+		c.buckets[key] = b
+	}
+
+	b.tokens = math.Min(float64(limit.Burst), b.tokens+now.Sub(b.last).Seconds()*limit.Rate)
+	b.last = now
+	if b.tokens >= 1 {
+		b.tokens--
+		l.allowed++
+		return true, 0
+	}
+
+	l.throttled++
+	wait := time.Duration((1 - b.tokens) / limit.Rate * float64(time.Second))
+	return false, wait
+}
+
+// Acquire réserve un appel simultané du client
+// Il retourne false lorsque le client a atteint sa limite, sinon la fonction libérant l'appel.
+func (l *Limiter) Acquire(clientKey string) (func(), bool) {
+	if l == nil {
+		return func() {}, true
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.maxInFlight <= 0 {
+		return func() {}, true
+	}
+	c := l.client(clientKey, l.now())
+	if c.inFlight >= l.maxInFlight {
+		l.rejected++
+		return nil, false
+	}
+	c.inFlight++
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			l.mu.Lock()
+			defer l.mu.Unlock()
+			c.inFlight--
+			c.lastSeen = l.now()
+		})
+	}, true
+}
+
+// Stats retourne l'état courant du limiteur
+func (l *Limiter) Stats() Stats {
+	if l == nil {
+		return Stats{}
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	stats := Stats{Clients: len(l.clients), Allowed: l.allowed, Throttled: l.throttled, Rejected: l.rejected}
+	for _, c := range l.clients {
+		stats.InFlight += c.inFlight
+	}
+	return stats
+}
+
+// client retourne l'état du client et purge les clients inactifs, l.mu doit être verrouillé
+func (l *Limiter) client(key string, now time.Time) *client {
+	if now.Sub(l.lastSweep) >= sweepInterval {
+		for k, c := range l.clients {
+			if c.inFlight == 0 && now.Sub(c.lastSeen) >= idleTimeout {
+				delete(l.clients, k)
+			}
+		}
+		l.lastSweep = now
+	}
+
+	c, ok := l.clients[key]
+	if !ok {
+		c = &client{buckets: map[string]*bucket{}}
+		l.clients[key] = c
+	}
+	c.lastSeen = now
+	return c
+}
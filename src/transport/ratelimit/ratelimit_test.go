@@ -0,0 +1,245 @@
+package ratelimit_test
+
+import (
+	"context"
+	"live-semantic/src/auth"
+	"live-semantic/src/transport/ratelimit"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+// clock horloge manuelle des tests
+type clock struct {
+	now time.Time
+}
+
+func (c *clock) Now() time.Time {
+	return c.now
+}
+
+func TestLimiter_Allow(t *testing.T) {
+	t.Run("should throttle a client once its burst is spent and refill over time", func(t *testing.T) {
+		// Given
+		c := &clock{now: time.Unix(0, 0)}
+		limiter := ratelimit.New(ratelimit.WithLimit(ratelimit.Limit{Rate: 2, Burst: 2}), ratelimit.WithClock(c.Now))
+
+		// When
+		first, _ := limiter.Allow("ip:10.0.0.1", "GET /api/v1/tasks")
+		second, _ := limiter.Allow("ip:10.0.0.1", "GET /api/v1/filters")
+		third, wait := limiter.Allow("ip:10.0.0.1", "GET /api/v1/tasks")
+		other, _ := limiter.Allow("ip:10.0.0.2", "GET /api/v1/tasks")
+		c.now = c.now.Add(500 * time.Millisecond)
+		refilled, _ := limiter.Allow("ip:10.0.0.1", "GET /api/v1/tasks")
+
+		// Then
+		assert.True(t, first)
+		assert.True(t, second)
+		assert.False(t, third)
+		assert.Equal(t, 500*time.Millisecond, wait)
+		assert.True(t, other)
+		assert.True(t, refilled)
+		assert.Equal(t, ratelimit.Stats{Clients: 2, Allowed: 4, Throttled: 1}, limiter.Stats())
+	})
+
+	t.Run("should give an overridden route its own bucket", func(t *testing.T) {
+		// Given
+		c := &clock{now: time.Unix(0, 0)}
+		limiter := ratelimit.New(
+			ratelimit.WithLimit(ratelimit.Limit{Rate: 10, Burst: 10}),
+			ratelimit.WithOverride("ws:Task", ratelimit.Limit{Rate: 1, Burst: 1}),
+			ratelimit.WithClock(c.Now),
+		)
+
+		// When
+		first, _ := limiter.Allow("principal:ci", "ws:Task")
+		second, wait := limiter.Allow("principal:ci", "ws:Task")
+		other, _ := limiter.Allow("principal:ci", "ws:Ping")
+
+		// Then
+		assert.True(t, first)
+		assert.False(t, second)
+		assert.Equal(t, time.Second, wait)
+		assert.True(t, other)
+	})
+
+	t.Run("should let every call through when the rate or the limiter is disabled", func(t *testing.T) {
+		// Given
+		var disabled *ratelimit.Limiter
+		unlimited := ratelimit.New(ratelimit.WithOverride("POST /rpc", ratelimit.Limit{}), ratelimit.WithLimit(ratelimit.Limit{Rate: 1, Burst: 1}))
+
+		// When
+		nilOK, _ := disabled.Allow("ip:10.0.0.1", "GET /api/v1/tasks")
+		first, _ := unlimited.Allow("ip:10.0.0.1", "POST /rpc")
+		second, _ := unlimited.Allow("ip:10.0.0.1", "POST /rpc")
+
+		// Then
+		assert.True(t, nilOK)
+		assert.True(t, first)
+		assert.True(t, second)
+	})
+}
+
+func TestLimiter_AllowIP(t *testing.T) {
+	t.Run("should keep the address limit apart from the bucket of the client with the same address", func(t *testing.T) {
+		// Given
+		c := &clock{now: time.Unix(0, 0)}
+		limiter := ratelimit.New(
+			ratelimit.WithLimit(ratelimit.Limit{Rate: 1, Burst: 1}),
+			ratelimit.WithIPLimit(ratelimit.Limit{Rate: 1, Burst: 2}),
+			ratelimit.WithClock(c.Now),
+		)
+
+		// When
+		client, _ := limiter.Allow("ip:10.0.0.1", "GET /api/v1/tasks")
+		first, _ := limiter.AllowIP("10.0.0.1")
+		second, _ := limiter.AllowIP("10.0.0.1")
+		third, wait := limiter.AllowIP("10.0.0.1")
+
+		// Then
+		assert.True(t, client)
+		assert.True(t, first)
+		assert.True(t, second)
+		assert.False(t, third)
+		assert.Equal(t, time.Second, wait)
+	})
+}
+
+func TestLimiter_Acquire(t *testing.T) {
+	t.Run("should cap the concurrent calls of a client", func(t *testing.T) {
+		// Given
+		limiter := ratelimit.New(ratelimit.WithMaxInFlight(1))
+
+		// When
+		release, first := limiter.Acquire("ip:10.0.0.1")
+		_, second := limiter.Acquire("ip:10.0.0.1")
+		_, other := limiter.Acquire("ip:10.0.0.2")
+		inFlight := limiter.Stats().InFlight
+		release()
+		release()
+		_, third := limiter.Acquire("ip:10.0.0.1")
+
+		// Then
+		assert.True(t, first)
+		assert.False(t, second)
+		assert.True(t, other)
+		assert.Equal(t, 2, inFlight)
+		assert.True(t, third)
+		assert.Equal(t, uint64(1), limiter.Stats().Rejected)
+	})
+}
+
+func TestLimiter_Reconfigure(t *testing.T) {
+	t.Run("should apply the new limits to the clients already tracked", func(t *testing.T) {
+		// Given
+		c := &clock{now: time.Unix(0, 0)}
+		limiter := ratelimit.New(ratelimit.WithLimit(ratelimit.Limit{Rate: 1, Burst: 1}), ratelimit.WithClock(c.Now))
+		first, _ := limiter.Allow("ip:10.0.0.1", "GET /api/v1/tasks")
+		throttled, _ := limiter.Allow("ip:10.0.0.1", "GET /api/v1/tasks")
+
+		// When
+		limiter.Reconfigure(
+			ratelimit.WithLimit(ratelimit.Limit{Rate: 1, Burst: 1}),
+			ratelimit.WithOverride("GET /api/v1/tasks", ratelimit.Limit{}),
+			ratelimit.WithMaxInFlight(1),
+		)
+		unlimited, _ := limiter.Allow("ip:10.0.0.1", "GET /api/v1/tasks")
+		stillThrottled, _ := limiter.Allow("ip:10.0.0.1", "GET /api/v1/filters")
+		_, acquired := limiter.Acquire("ip:10.0.0.1")
+		_, capped := limiter.Acquire("ip:10.0.0.1")
+
+		// Then
+		assert.True(t, first)
+		assert.False(t, throttled)
+		assert.True(t, unlimited)
+		assert.False(t, stillThrottled)
+		assert.True(t, acquired)
+		assert.False(t, capped)
+	})
+}
+
+func TestParseOverride(t *testing.T) {
+	t.Run("should parse a route override whose key contains spaces", func(t *testing.T) {
+		// When
+		key, limit, err := ratelimit.ParseOverride("POST /api/v1/createTask=0.5:3")
+
+		// Then
+		assert.NoError(t, err)
+		assert.Equal(t, "POST /api/v1/createTask", key)
+		assert.Equal(t, ratelimit.Limit{Rate: 0.5, Burst: 3}, limit)
+	})
+
+	t.Run("should reject a malformed override", func(t *testing.T) {
+		for _, override := range []string{"ws:Task", "=1:1", "ws:Task=1", "ws:Task=fast:1", "ws:Task=1:0", "ws:Task=1:-1"} {
+			// When
+			_, _, err := ratelimit.ParseOverride(override)
+
+			// Then
+			assert.Error(t, err, override)
+		}
+	})
+}
+
+func TestLimiter_Middleware(t *testing.T) {
+	serve := func(limiter *ratelimit.Limiter, subject string) *httptest.ResponseRecorder {
+		gin.SetMode(gin.TestMode)
+		router := gin.New()
+		router.GET("/tasks", func(c *gin.Context) {
+			if subject != "" {
+				c.Request = c.Request.WithContext(auth.WithPrincipal(context.Background(), auth.Principal{Subject: subject}))
+			}
+		}, limiter.Middleware("web"), func(c *gin.Context) {
+			c.Status(http.StatusOK)
+		})
+
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/tasks", nil))
+		return rec
+	}
+
+	t.Run("should answer 429 with Retry-After once the bucket is empty", func(t *testing.T) {
+		// Given
+		limiter := ratelimit.New(ratelimit.WithLimit(ratelimit.Limit{Rate: 0.5, Burst: 1}))
+
+		// When
+		first := serve(limiter, "")
+		second := serve(limiter, "")
+
+		// Then
+		assert.Equal(t, http.StatusOK, first.Code)
+		assert.Equal(t, http.StatusTooManyRequests, second.Code)
+		assert.Equal(t, "2", second.Header().Get("Retry-After"))
+		assert.Contains(t, second.Body.String(), "rate limit exceeded")
+	})
+
+	t.Run("should key an authenticated client by its principal", func(t *testing.T) {
+		// Given
+		limiter := ratelimit.New(ratelimit.WithLimit(ratelimit.Limit{Rate: 0.5, Burst: 1}))
+
+		// When
+		anonymous := serve(limiter, "")
+		ci := serve(limiter, "ci")
+		ops := serve(limiter, "ops")
+
+		// Then
+		assert.Equal(t, http.StatusOK, anonymous.Code)
+		assert.Equal(t, http.StatusOK, ci.Code)
+		assert.Equal(t, http.StatusOK, ops.Code)
+		assert.Equal(t, 3, limiter.Stats().Clients)
+	})
+
+	t.Run("should let every request through when rate limiting is disabled", func(t *testing.T) {
+		// Given
+		var disabled *ratelimit.Limiter
+
+		// When
+		rec := serve(disabled, "")
+
+		// Then
+		assert.Equal(t, http.StatusOK, rec.Code)
+	})
+}
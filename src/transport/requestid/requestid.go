@@ -0,0 +1,54 @@
+// Package requestid identifie chaque requête des transports réseau, pour corréler les
+// enregistrements d'audit, les logs et les réponses
+package requestid
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"live-semantic/src/domain/uc"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Header en-tête portant l'identifiant de la requête, repris du client et renvoyé dans la réponse
+const Header = "X-Request-ID"
+
+// maxLength longueur maximale d'un identifiant fourni par le client
+const maxLength = 64
+
+// New génère un identifiant de requête aléatoire
+func New() string {
+	var id [8]byte
+	_, _ = rand.Read(id[:])
+	return hex.EncodeToString(id[:])
+}
+
+// Middleware place l'identifiant de la requête dans son contexte, à destination des cas d'usage
+// L'identifiant de l'en-tête X-Request-ID est conservé s'il est valide, sinon un nouveau est généré
+func Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.GetHeader(Header)
+		if !valid(id) {
+			id = New()
+		}
+
+		c.Header(Header, id)
+		c.Request = c.Request.WithContext(uc.WithRequest(c.Request.Context(), uc.Request{ID: id}))
+		c.Next()
+	}
+}
+
+// valid n'accepte que des identifiants courts, sans caractère susceptible de corrompre un log
+func valid(id string) bool {
+	if id == "" || len(id) > maxLength {
+		return false
+	}
+	for _, r := range id {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '_', r == '.', r == ':':
+		default:
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,142 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	"io"
+	"live-semantic/src/auth"
+	"live-semantic/src/tracing"
+	"live-semantic/src/transport/ratelimit"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+)
+
+// wsLimitKey clé de limite des messages de /rpc/ws, surchargeable avec rpc:ws
+const wsLimitKey = "rpc:ws"
+
+// handleHTTP traite un appel ou un lot reçu en POST /rpc
+// Les erreurs JSON-RPC sont retournées avec un statut 200, un lot de notifications avec un statut 204
+func (s *Server) handleHTTP(c *gin.Context) {
+	if s.draining.Load() {
+		c.JSON(http.StatusServiceUnavailable, failure(nil, CodeInternalError, "server is shutting down"))
+		return
+	}
+
+	payload, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, s.maxMessageSize))
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			c.JSON(http.StatusRequestEntityTooLarge, failure(nil, CodeInvalidRequest, "Invalid Request: message too large"))
+			return
+		}
+		c.JSON(http.StatusBadRequest, failure(nil, CodeParseError, "Parse error: "+err.Error()))
+		return
+	}
+
+	reply := s.dispatcher.Handle(c.Request.Context(), payload)
+	if reply == nil {
+		c.Status(http.StatusNoContent)
+		return
+	}
+	c.Data(http.StatusOK, "application/json", reply)
+}
+
+// handleWebSocket traite les appels reçus sur /rpc/ws
+// Chaque message texte contient un appel ou un lot, les appels d'une session s'exécutent
+// en parallèle et leurs réponses sont associées par id.
+func (s *Server) handleWebSocket(c *gin.Context) {
+	// Refuser les nouvelles connexions pendant le drainage
+	if s.draining.Load() {
+		c.JSON(http.StatusServiceUnavailable, failure(nil, CodeInternalError, "server is shutting down"))
+		return
+	}
+
+	conn, err := s.upgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		s.logger.Error("Failed to upgrade to JSON-RPC WebSocket", map[string]interface{}{
+			"error": err.Error(),
+		})
+		return
+	}
+	defer conn.Close()
+
+	subject := ""
+	if principal, ok := auth.PrincipalFrom(c.Request.Context()); ok {
+		subject = principal.Subject
+	}
+	if !s.trackConn(conn, subject) {
+		closeMessage := websocket.FormatCloseMessage(websocket.CloseGoingAway, "server shutting down")
+		_ = conn.WriteControl(websocket.CloseMessage, closeMessage, time.Now().Add(closeWriteTimeout))
+		return
+	}
+	defer s.untrackConn(conn)
+
+	s.logger.Info("New JSON-RPC WebSocket connection established")
+	conn.SetReadLimit(s.maxMessageSize)
+
+	var (
+		calls   sync.WaitGroup
+		writeMu sync.Mutex
+	)
+	// Les appels en cours répondent avant la fermeture de la session
+	defer calls.Wait()
+
+	ctx := c.Request.Context()
+	client := ratelimit.ClientKey(c)
+	for {
+		_, payload, err := conn.ReadMessage()
+		if err != nil {
+			if websocket.IsCloseError(err, websocket.CloseNormalClosure, websocket.CloseGoingAway) {
+				s.logger.Info("JSON-RPC WebSocket connection closed")
+				return
+			}
+			s.logger.Error("Failed to read JSON-RPC WebSocket message", map[string]interface{}{
+				"error": err.Error(),
+			})
+			return
+		}
+
+		calls.Add(1)
+		go func() {
+			defer calls.Done()
+
+			reply := s.call(ctx, client, payload)
+			if reply == nil {
+				return
+			}
+
+			writeMu.Lock()
+			defer writeMu.Unlock()
+			if err := conn.WriteMessage(websocket.TextMessage, reply); err != nil {
+				s.logger.Error("Failed to send JSON-RPC WebSocket response", map[string]interface{}{
+					"error": err.Error(),
+				})
+			}
+		}()
+	}
+}
+
+// call exécute un message de /rpc/ws dans les limites du client
+// Chaque message est une trace, distincte de celle de l'ouverture de la session
+func (s *Server) call(ctx context.Context, client string, payload []byte) []byte {
+	ctx, span := s.tracer.Start(ctx, "rpc.ws message", tracing.NewRoot(), tracing.WithKind(tracing.KindServer))
+	defer span.End()
+
+	if ok, wait := s.limiter.Allow(client, wsLimitKey); !ok {
+		span.Fail("rate limit exceeded")
+		return reject(payload, &Error{Code: CodeRateLimited, Message: "rate limit exceeded", Data: RateLimited{RetryAfter: ratelimit.RetryAfter(wait)}})
+	}
+
+	release, ok := s.limiter.Acquire(client)
+	if !ok {
+		span.Fail("too many concurrent calls")
+		return reject(payload, &Error{Code: CodeRateLimited, Message: "too many concurrent calls", Data: RateLimited{RetryAfter: 1}})
+	}
+	defer release()
+
+	return s.dispatcher.Handle(ctx, payload)
+}
@@ -0,0 +1,329 @@
+// Package rpc expose les cas d'usage en JSON-RPC 2.0 sur HTTP et WebSocket
+package rpc
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"live-semantic/src/auth"
+	"live-semantic/src/domain/uc"
+	"live-semantic/src/metrics"
+	"live-semantic/src/tracing"
+	"live-semantic/src/transport"
+	"live-semantic/src/transport/authn"
+	"sort"
+
+	"github.com/deadelus/go-clean-app/src/logger"
+)
+
+// Version version du protocole
+const Version = "2.0"
+
+// Codes d'erreur JSON-RPC
+const (
+	CodeParseError     = -32700
+	CodeInvalidRequest = -32600
+	CodeMethodNotFound = -32601
+	CodeInvalidParams  = -32602
+	CodeInternalError  = -32603
+	// CodeDomainError erreur retournée par un cas d'usage, le détail est dans data
+	CodeDomainError = -32000
+	// CodeForbidden le principal de l'appel ne porte pas le scope de la méthode
+	CodeForbidden = -32001
+	// CodeRateLimited le client a dépassé sa limite de débit ou d'appels simultanés, le délai est dans data
+	CodeRateLimited = -32005
+)
+
+// Méthodes exposées
+const (
+	MethodTaskCreate = "task.create"
+	MethodTaskList   = "task.list"
+	MethodFilterList = "filter.list"
+	MethodSourceList = "source.list"
+)
+
+// source identifie le transport dans les requêtes transmises au handler de base
+const source = "rpc"
+
+// Request représente un appel
+// Un appel sans id est une notification, il ne reçoit pas de réponse
+type Request struct {
+	JSONRPC string          `json:"jsonrpc"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+	ID      json.RawMessage `json:"id,omitempty"`
+}
+
+// Response représente la réponse à un appel
+type Response struct {
+	JSONRPC string          `json:"jsonrpc"`
+	Result  any             `json:"result,omitempty"`
+	Error   *Error          `json:"error,omitempty"`
+	ID      json.RawMessage `json:"id"`
+}
+
+// Error représente une erreur JSON-RPC
+type Error struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+	Data    any    `json:"data,omitempty"`
+}
+
+// DomainError données d'une erreur retournée par un cas d'usage
+type DomainError struct {
+	Error  string `json:"error"`
+	Source string `json:"source"`
+}
+
+// RateLimited données d'une erreur de limite, délai en secondes avant un nouvel essai
+type RateLimited struct {
+	RetryAfter int `json:"retry_after"`
+}
+
+// method exécute une méthode avec ses paramètres bruts
+type method func(ctx context.Context, params json.RawMessage) (any, *Error)
+
+// scopes scope exigé par chaque méthode lorsque l'authentification est activée
+var scopes = map[string]string{
+	MethodTaskCreate: auth.ScopeTasksWrite,
+	MethodTaskList:   auth.ScopeTasksRead,
+	MethodFilterList: auth.ScopeTasksRead,
+	MethodSourceList: auth.ScopeTasksRead,
+}
+
+// Dispatcher associe les méthodes aux opérations du handler de base
+type Dispatcher struct {
+	methods   map[string]method
+	authorize bool
+	auditor   uc.Auditor
+	metrics   metrics.Collector
+}
+
+// DispatcherOption configure le dispatcher
+type DispatcherOption func(*Dispatcher)
+
+// WithScopes exige du principal porté par le contexte le scope de chaque méthode appelée
+func WithScopes() DispatcherOption {
+	return func(d *Dispatcher) {
+		d.authorize = true
+	}
+}
+
+// WithAuditTrail enregistre les appels refusés faute de scope dans la piste d'audit
+func WithAuditTrail(auditor uc.Auditor) DispatcherOption {
+	return func(d *Dispatcher) {
+		d.auditor = auditor
+	}
+}
+
+// WithCollector mesure les appels de cas d'usage exécutés par les méthodes
+func WithCollector(collector metrics.Collector) DispatcherOption {
+	return func(d *Dispatcher) {
+		d.metrics = collector
+	}
+}
+
+// NewDispatcher crée un dispatcher pour les cas d'usage
+func NewDispatcher(useCases uc.UseCases, logger logger.Logger, options ...DispatcherOption) *Dispatcher {
+	d := &Dispatcher{metrics: metrics.Discard}
+	for _, option := range options {
+		option(d)
+	}
+
+	baseHandler := transport.NewBaseHandler(useCases, logger, transport.WithMetrics(d.metrics))
+	d.methods = map[string]method{
+		MethodTaskCreate: bind(baseHandler.HandleTask),
+		MethodTaskList:   bind(baseHandler.HandleListTasks),
+		MethodFilterList: bind(baseHandler.HandleListFilters),
+		MethodSourceList: bind(baseHandler.HandleListSources),
+	}
+	return d
+}
+
+// Methods retourne les noms des méthodes exposées
+func (d *Dispatcher) Methods() []string {
+	names := make([]string, 0, len(d.methods))
+	for name := range d.methods {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Handle traite un message contenant un appel ou un lot d'appels
+// Il retourne nil lorsque le message ne contient que des notifications
+func (d *Dispatcher) Handle(ctx context.Context, payload []byte) []byte {
+	payload = bytes.TrimSpace(payload)
+
+	var reply any
+	if len(payload) > 0 && payload[0] == '[' {
+		var batch []json.RawMessage
+		if err := json.Unmarshal(payload, &batch); err != nil {
+			reply = failure(nil, CodeParseError, "Parse error: "+err.Error())
+		} else if len(batch) == 0 {
+			reply = failure(nil, CodeInvalidRequest, "Invalid Request: empty batch")
+		} else {
+			var responses []Response
+			for _, raw := range batch {
+				if response, ok := d.call(ctx, raw); ok {
+					responses = append(responses, response)
+				}
+			}
+			if len(responses) == 0 {
+				return nil
+			}
+			reply = responses
+		}
+	} else {
+		response, ok := d.call(ctx, payload)
+		if !ok {
+			return nil
+		}
+		reply = response
+	}
+
+	data, err := json.Marshal(reply)
+	if err != nil {
+		data, _ = json.Marshal(failure(nil, CodeInternalError, "Internal error: "+err.Error()))
+	}
+	return data
+}
+
+// call exécute un appel, ok est faux pour une notification
+func (d *Dispatcher) call(ctx context.Context, raw json.RawMessage) (response Response, ok bool) {
+	if !json.Valid(raw) {
+		return failure(nil, CodeParseError, "Parse error: invalid JSON"), true
+	}
+
+	var req Request
+	if err := json.Unmarshal(raw, &req); err != nil {
+		return failure(nil, CodeInvalidRequest, "Invalid Request: "+err.Error()), true
+	}
+	if req.JSONRPC != Version || req.Method == "" || !validID(req.ID) {
+		return failure(nil, CodeInvalidRequest, "Invalid Request"), true
+	}
+
+	notification := len(req.ID) == 0
+
+	ctx, span := tracing.Start(ctx, "rpc "+req.Method, tracing.WithAttributes(tracing.Attr("rpc.method", req.Method)))
+	defer span.End()
+
+	m, found := d.methods[req.Method]
+	if !found {
+		span.Fail("method not found")
+		if notification {
+			return Response{}, false
+		}
+		return failure(req.ID, CodeMethodNotFound, "Method not found: "+req.Method), true
+	}
+
+	if d.authorize {
+		if err := auth.Check(ctx, scopes[req.Method]); err != nil {
+			span.SetError(err)
+			authn.Deny(ctx, d.auditor, source, req.Method, err)
+			if notification {
+				return Response{}, false
+			}
+			return failure(req.ID, CodeForbidden, err.Error()), true
+		}
+	}
+
+	result, rpcErr := m(ctx, req.Params)
+	if rpcErr != nil {
+		span.SetAttributes(tracing.Attr("rpc.jsonrpc.error_code", rpcErr.Code))
+		span.Fail(rpcErr.Message)
+	}
+	if notification {
+		return Response{}, false
+	}
+	if rpcErr != nil {
+		return Response{JSONRPC: Version, Error: rpcErr, ID: req.ID}, true
+	}
+	return Response{JSONRPC: Version, Result: result, ID: req.ID}, true
+}
+
+// reject répond à chaque appel du message par l'erreur, sans les exécuter
+// Il retourne nil lorsque le message ne contient que des notifications
+func reject(payload []byte, rpcErr *Error) []byte {
+	payload = bytes.TrimSpace(payload)
+
+	raws := []json.RawMessage{payload}
+	var batch []json.RawMessage
+	if len(payload) > 0 && payload[0] == '[' && json.Unmarshal(payload, &batch) == nil && len(batch) > 0 {
+		raws = batch
+	}
+
+	var responses []Response
+	for _, raw := range raws {
+		var req Request
+		if err := json.Unmarshal(raw, &req); err == nil && len(req.ID) == 0 {
+			continue
+		}
+		if !validID(req.ID) {
+			req.ID = nil
+		}
+		response := failure(req.ID, rpcErr.Code, rpcErr.Message)
+		response.Error.Data = rpcErr.Data
+		responses = append(responses, response)
+	}
+	if len(responses) == 0 {
+		return nil
+	}
+
+	var reply any = responses[0]
+	if len(batch) > 0 {
+		reply = responses
+	}
+	data, _ := json.Marshal(reply)
+	return data
+}
+
+// bind décode les paramètres nommés et exécute le handler
+func bind[Req, Resp any](handle func(transport.TransportRequest[Req]) transport.TransportResponse[Resp]) method {
+	return func(ctx context.Context, params json.RawMessage) (any, *Error) {
+		var req Req
+		if len(params) > 0 && string(params) != "null" {
+			if params[0] != '{' {
+				return nil, &Error{Code: CodeInvalidParams, Message: "Invalid params: params must be an object"}
+			}
+			if err := json.Unmarshal(params, &req); err != nil {
+				return nil, &Error{Code: CodeInvalidParams, Message: "Invalid params: " + err.Error()}
+			}
+		}
+
+		response := handle(transport.TransportRequest[Req]{
+			Data:    req,
+			Context: ctx,
+			Source:  source,
+		})
+		if !response.Success {
+			return nil, &Error{
+				Code:    CodeDomainError,
+				Message: "Domain error",
+				Data:    DomainError{Error: response.Error, Source: response.Source},
+			}
+		}
+		return response.Data, nil
+	}
+}
+
+// validID vérifie qu'un id est absent, une chaîne, un nombre ou null
+func validID(id json.RawMessage) bool {
+	if len(id) == 0 {
+		return true
+	}
+	switch id[0] {
+	case '"', 'n', '-', '0', '1', '2', '3', '4', '5', '6', '7', '8', '9':
+		return true
+	default:
+		return false
+	}
+}
+
+// failure construit une réponse d'erreur
+func failure(id json.RawMessage, code int, message string) Response {
+	if len(id) == 0 {
+		id = json.RawMessage("null")
+	}
+	return Response{JSONRPC: Version, Error: &Error{Code: code, Message: message}, ID: id}
+}
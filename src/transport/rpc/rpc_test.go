@@ -0,0 +1,248 @@
+package rpc_test
+
+import (
+	"context"
+	"encoding/json"
+	"live-semantic/src/auth"
+	"live-semantic/src/domain/dto"
+	"live-semantic/src/domain/uc"
+	"live-semantic/src/logging"
+	"live-semantic/src/transport/ratelimit"
+	"live-semantic/src/transport/rpc"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+)
+
+type response struct {
+	Result json.RawMessage `json:"result"`
+	Error  *struct {
+		Code    int             `json:"code"`
+		Message string          `json:"message"`
+		Data    json.RawMessage `json:"data"`
+	} `json:"error"`
+	ID json.RawMessage `json:"id"`
+}
+
+// rejectingUseCases refuse la création des tâches
+type rejectingUseCases struct {
+	uc.UseCases
+}
+
+func (rejectingUseCases) CreateTask(context.Context, dto.TaskRequest) (dto.Result[dto.TaskResponse], error) {
+	return dto.Failure[dto.TaskResponse]("title is required"), nil
+}
+
+// recorder garde les enregistrements d'audit reçus
+type recorder struct {
+	records []uc.AuditRecord
+}
+
+func (r *recorder) Record(ctx context.Context, record uc.AuditRecord) {
+	r.records = append(r.records, record)
+}
+
+func newDispatcher(t *testing.T) *rpc.Dispatcher {
+	t.Helper()
+
+	useCases, err := uc.NewUseCase(logging.Discard)
+	assert.NoError(t, err)
+	return rpc.NewDispatcher(useCases, logging.Discard)
+}
+
+func handle(t *testing.T, d *rpc.Dispatcher, payload string) []byte {
+	t.Helper()
+	return d.Handle(context.Background(), []byte(payload))
+}
+
+func TestDispatcher_Handle(t *testing.T) {
+	t.Run("should return the result of a call with its id", func(t *testing.T) {
+		// When
+		reply := handle(t, newDispatcher(t), `{"jsonrpc":"2.0","method":"task.create","params":{"title":"title"},"id":"a"}`)
+
+		// Then
+		var r response
+		assert.NoError(t, json.Unmarshal(reply, &r))
+		assert.Nil(t, r.Error)
+		assert.JSONEq(t, `"a"`, string(r.ID))
+		assert.Contains(t, string(r.Result), `"id":"12345"`)
+	})
+
+	t.Run("should answer a batch without the notifications", func(t *testing.T) {
+		// When
+		reply := handle(t, newDispatcher(t), `[
+			{"jsonrpc":"2.0","method":"filter.list","params":{"prefix":"fight"},"id":1},
+			{"jsonrpc":"2.0","method":"task.create","params":{"title":"title"}},
+			{"jsonrpc":"2.0","method":"unknown","id":2},
+			{"jsonrpc":"2.0","method":"task.list","params":[1],"id":3},
+			{"jsonrpc":"1.0","method":"task.list","id":4}
+		]`)
+
+		// Then
+		var batch []response
+		assert.NoError(t, json.Unmarshal(reply, &batch))
+		if assert.Len(t, batch, 4) {
+			assert.JSONEq(t, `[{"name":"fight","category":"incidents"}]`, string(batch[0].Result))
+			assert.Equal(t, rpc.CodeMethodNotFound, batch[1].Error.Code)
+			assert.Equal(t, rpc.CodeInvalidParams, batch[2].Error.Code)
+			assert.Equal(t, rpc.CodeInvalidRequest, batch[3].Error.Code)
+			assert.JSONEq(t, `null`, string(batch[3].ID))
+		}
+	})
+
+	t.Run("should not answer notifications", func(t *testing.T) {
+		// When
+		single := handle(t, newDispatcher(t), `{"jsonrpc":"2.0","method":"task.list"}`)
+		batch := handle(t, newDispatcher(t), `[{"jsonrpc":"2.0","method":"task.list"},{"jsonrpc":"2.0","method":"unknown"}]`)
+
+		// Then
+		assert.Nil(t, single)
+		assert.Nil(t, batch)
+	})
+
+	t.Run("should reject invalid JSON and empty batches", func(t *testing.T) {
+		// Given
+		d := newDispatcher(t)
+
+		// When
+		var parse, empty response
+		assert.NoError(t, json.Unmarshal(handle(t, d, `{"jsonrpc"`), &parse))
+		assert.NoError(t, json.Unmarshal(handle(t, d, `[]`), &empty))
+
+		// Then
+		assert.Equal(t, rpc.CodeParseError, parse.Error.Code)
+		assert.Equal(t, rpc.CodeInvalidRequest, empty.Error.Code)
+	})
+
+	t.Run("should carry the domain error in the error data", func(t *testing.T) {
+		// Given
+		d := rpc.NewDispatcher(rejectingUseCases{}, logging.Discard)
+
+		// When
+		var r response
+		assert.NoError(t, json.Unmarshal(handle(t, d, `{"jsonrpc":"2.0","method":"task.create","params":{},"id":1}`), &r))
+
+		// Then
+		if assert.NotNil(t, r.Error) {
+			assert.Equal(t, rpc.CodeDomainError, r.Error.Code)
+			assert.JSONEq(t, `{"error":"title is required","source":"rpc"}`, string(r.Error.Data))
+		}
+	})
+
+	t.Run("should require the scope of the method from the principal", func(t *testing.T) {
+		// Given
+		useCases, err := uc.NewUseCase(logging.Discard)
+		assert.NoError(t, err)
+		d := rpc.NewDispatcher(useCases, logging.Discard, rpc.WithScopes())
+		ctx := auth.WithPrincipal(context.Background(), auth.Principal{Subject: "viewer", Scopes: []string{auth.ScopeTasksRead}})
+
+		// When
+		var created, listed response
+		assert.NoError(t, json.Unmarshal(d.Handle(ctx, []byte(`{"jsonrpc":"2.0","method":"task.create","params":{"title":"title"},"id":1}`)), &created))
+		assert.NoError(t, json.Unmarshal(d.Handle(ctx, []byte(`{"jsonrpc":"2.0","method":"task.list","id":2}`)), &listed))
+
+		// Then
+		if assert.NotNil(t, created.Error) {
+			assert.Equal(t, rpc.CodeForbidden, created.Error.Code)
+			assert.Contains(t, created.Error.Message, auth.ScopeTasksWrite)
+		}
+		assert.Nil(t, listed.Error)
+	})
+
+	t.Run("should audit a call denied for a missing scope", func(t *testing.T) {
+		// Given
+		useCases, err := uc.NewUseCase(logging.Discard)
+		assert.NoError(t, err)
+		auditor := &recorder{}
+		d := rpc.NewDispatcher(useCases, logging.Discard, rpc.WithScopes(), rpc.WithAuditTrail(auditor))
+		ctx := auth.WithPrincipal(context.Background(), auth.Principal{Subject: "viewer", Scopes: []string{auth.ScopeTasksRead}})
+
+		// When
+		d.Handle(ctx, []byte(`{"jsonrpc":"2.0","method":"task.list","id":1}`))
+		d.Handle(ctx, []byte(`{"jsonrpc":"2.0","method":"task.create","params":{"title":"title"},"id":2}`))
+
+		// Then
+		if assert.Len(t, auditor.records, 1) {
+			assert.Equal(t, "viewer", auditor.records[0].Principal)
+			assert.Equal(t, "rpc", auditor.records[0].Source)
+			assert.Equal(t, "task.create", auditor.records[0].Action)
+			assert.Equal(t, uc.OutcomeDenied, auditor.records[0].Outcome)
+		}
+	})
+}
+
+func TestServer(t *testing.T) {
+	useCases, err := uc.NewUseCase(logging.Discard)
+	assert.NoError(t, err)
+	server := httptest.NewServer(rpc.NewServer(useCases, logging.Discard, 0).Handler())
+	defer server.Close()
+
+	t.Run("should serve calls over HTTP", func(t *testing.T) {
+		// When
+		call, callErr := http.Post(server.URL+"/rpc", "application/json", strings.NewReader(`{"jsonrpc":"2.0","method":"source.list","id":1}`))
+		notify, notifyErr := http.Post(server.URL+"/rpc", "application/json", strings.NewReader(`{"jsonrpc":"2.0","method":"source.list"}`))
+
+		// Then
+		if assert.NoError(t, callErr) {
+			call.Body.Close()
+			assert.Equal(t, http.StatusOK, call.StatusCode)
+		}
+		if assert.NoError(t, notifyErr) {
+			notify.Body.Close()
+			assert.Equal(t, http.StatusNoContent, notify.StatusCode)
+		}
+	})
+
+	t.Run("should serve calls over WebSocket", func(t *testing.T) {
+		// Given
+		conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http")+"/rpc/ws", nil)
+		if !assert.NoError(t, err) {
+			return
+		}
+		defer conn.Close()
+
+		// When
+		assert.NoError(t, conn.WriteMessage(websocket.TextMessage, []byte(`{"jsonrpc":"2.0","method":"task.list"}`)))
+		assert.NoError(t, conn.WriteMessage(websocket.TextMessage, []byte(`{"jsonrpc":"2.0","method":"filter.list","params":{"limit":1},"id":7}`)))
+
+		// Then
+		_ = conn.SetReadDeadline(time.Now().Add(time.Second))
+		var r response
+		assert.NoError(t, conn.ReadJSON(&r))
+		assert.JSONEq(t, `7`, string(r.ID))
+		assert.JSONEq(t, `[{"name":"person walking","category":"security"}]`, string(r.Result))
+	})
+	t.Run("should answer a throttled WebSocket call with a rate limit error", func(t *testing.T) {
+		// Given
+		limiter := ratelimit.New(ratelimit.WithOverride("rpc:ws", ratelimit.Limit{Rate: 0.5, Burst: 1}))
+		limited := httptest.NewServer(rpc.NewServer(useCases, logging.Discard, 0, rpc.WithRateLimiter(limiter)).Handler())
+		defer limited.Close()
+		conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(limited.URL, "http")+"/rpc/ws", nil)
+		if !assert.NoError(t, err) {
+			return
+		}
+		defer conn.Close()
+
+		// When
+		assert.NoError(t, conn.WriteMessage(websocket.TextMessage, []byte(`{"jsonrpc":"2.0","method":"source.list","id":1}`)))
+		_ = conn.SetReadDeadline(time.Now().Add(time.Second))
+		var first response
+		assert.NoError(t, conn.ReadJSON(&first))
+		assert.NoError(t, conn.WriteMessage(websocket.TextMessage, []byte(`[{"jsonrpc":"2.0","method":"source.list","id":2},{"jsonrpc":"2.0","method":"task.list"}]`)))
+		var second []response
+		assert.NoError(t, conn.ReadJSON(&second))
+
+		// Then
+		assert.Nil(t, first.Error)
+		if assert.Len(t, second, 1) && assert.NotNil(t, second[0].Error) {
+			assert.JSONEq(t, `2`, string(second[0].ID))
+			assert.Equal(t, rpc.CodeRateLimited, second[0].Error.Code)
+			assert.JSONEq(t, `{"retry_after":2}`, string(second[0].Error.Data))
+		}
+	})
+}
@@ -0,0 +1,434 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"live-semantic/src/auth"
+	"live-semantic/src/domain/uc"
+	"live-semantic/src/health"
+	"live-semantic/src/lifecycle"
+	"live-semantic/src/metrics"
+	"live-semantic/src/tracing"
+	"live-semantic/src/transport/authn"
+	"live-semantic/src/transport/cors"
+	"live-semantic/src/transport/ratelimit"
+	"live-semantic/src/transport/requestid"
+	"live-semantic/src/transport/traces"
+	"net"
+	"net/http"
+	"slices"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/deadelus/go-clean-app/src/logger"
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+)
+
+// DefaultShutdownTimeout délai accordé aux appels et sessions lors de l'arrêt
+const DefaultShutdownTimeout = 15 * time.Second
+
+// DefaultMaxMessageSize taille maximale d'un appel ou d'un lot
+const DefaultMaxMessageSize = 1 << 20
+
+// closeWriteTimeout délai d'écriture de la trame de fermeture
+const closeWriteTimeout = time.Second
+
+// WebSocketSessions jauge des sessions WebSocket ouvertes
+const WebSocketSessions = "websocket_sessions"
+
+// sessionsLabel distingue les sessions de ce serveur dans la jauge partagée
+var sessionsLabel = metrics.Label{Name: "server", Value: "rpc"}
+
+// Server représente le serveur JSON-RPC
+type Server struct {
+	dispatcher      *Dispatcher
+	logger          logger.Logger
+	host            string
+	trustedProxies  []string
+	port            int
+	router          *gin.Engine
+	httpServer      *http.Server
+	health          *health.Registry
+	authenticator   *auth.Authenticator
+	auditor         uc.Auditor
+	limiter         *ratelimit.Limiter
+	cors            *cors.Policy
+	metrics         metrics.Collector
+	tracer          *tracing.Tracer
+	upgrader        websocket.Upgrader
+	maxMessageSize  int64
+	shutdownTimeout atomic.Int64
+	draining        atomic.Bool
+	conns           map[*websocket.Conn]*lifecycle.Session
+	connsMu         sync.Mutex
+	connsWg         sync.WaitGroup
+	stopped         chan struct{}
+	stopOnce        sync.Once
+}
+
+// Option configure le serveur JSON-RPC
+type Option func(*Server)
+
+// WithHost définit l'interface d'écoute, vide pour toutes les interfaces
+func WithHost(host string) Option {
+	return func(s *Server) {
+		s.host = host
+	}
+}
+
+// WithTrustedProxies définit les proxies, adresses IP ou CIDR, dont X-Forwarded-For est cru
+// Sans proxy de confiance, le client d'une requête est l'adresse de sa connexion
+func WithTrustedProxies(proxies []string) Option {
+	return func(s *Server) {
+		s.trustedProxies = proxies
+	}
+}
+
+// WithShutdownTimeout définit le délai de drainage des appels et sessions
+func WithShutdownTimeout(timeout time.Duration) Option {
+	return func(s *Server) {
+		s.shutdownTimeout.Store(int64(timeout))
+	}
+}
+
+// WithHealth définit le registre des sondes agrégées par /health et /readyz
+func WithHealth(registry *health.Registry) Option {
+	return func(s *Server) {
+		s.health = registry
+	}
+}
+
+// WithMaxMessageSize borne la taille d'un appel ou d'un lot
+func WithMaxMessageSize(size int64) Option {
+	return func(s *Server) {
+		if size > 0 {
+			s.maxMessageSize = size
+		}
+	}
+}
+
+// WithAuthenticator exige une clé d'API ou un jeton sur /rpc et /rpc/ws
+// et le scope de chaque méthode appelée
+func WithAuthenticator(authenticator *auth.Authenticator) Option {
+	return func(s *Server) {
+		s.authenticator = authenticator
+	}
+}
+
+// WithAuditor enregistre les refus de scope dans la piste d'audit
+func WithAuditor(auditor uc.Auditor) Option {
+	return func(s *Server) {
+		s.auditor = auditor
+	}
+}
+
+// WithRateLimiter limite le débit et les appels simultanés de chaque client
+// Un message de /rpc/ws refusé reçoit une erreur CodeRateLimited
+func WithRateLimiter(limiter *ratelimit.Limiter) Option {
+	return func(s *Server) {
+		s.limiter = limiter
+	}
+}
+
+// WithCORS définit la politique d'origines des navigateurs
+// Elle s'applique aussi à l'origine des ouvertures de WebSocket, la même origine reste toujours acceptée
+func WithCORS(policy *cors.Policy) Option {
+	return func(s *Server) {
+		s.cors = policy
+	}
+}
+
+// WithMetrics enregistre les métriques des requêtes, des sessions et des appels de cas d'usage et les expose sur /metrics
+// lorsque le collecteur sait les exposer
+func WithMetrics(collector metrics.Collector) Option {
+	return func(s *Server) {
+		if collector != nil {
+			s.metrics = collector
+		}
+	}
+}
+
+// WithTracer démarre un span serveur pour chaque requête et une trace pour chaque message de /rpc/ws
+func WithTracer(tracer *tracing.Tracer) Option {
+	return func(s *Server) {
+		s.tracer = tracer
+	}
+}
+
+// NewServer crée un nouveau serveur JSON-RPC
+func NewServer(useCases uc.UseCases, logger logger.Logger, port int, options ...Option) *Server {
+	gin.SetMode(gin.ReleaseMode)
+	router := gin.New()
+	router.Use(gin.Recovery())
+
+	server := &Server{
+		logger:         logger,
+		port:           port,
+		router:         router,
+		cors:           cors.New(),
+		metrics:        metrics.Discard,
+		health:         health.NewRegistry(),
+		maxMessageSize: DefaultMaxMessageSize,
+		conns:          make(map[*websocket.Conn]*lifecycle.Session),
+		stopped:        make(chan struct{}),
+	}
+
+	server.shutdownTimeout.Store(int64(DefaultShutdownTimeout))
+	for _, option := range options {
+		option(server)
+	}
+	if err := router.SetTrustedProxies(server.trustedProxies); err != nil {
+		logger.Warn("Invalid trusted proxies, forwarded headers ignored", map[string]interface{}{"error": err.Error()})
+		_ = router.SetTrustedProxies(nil)
+	}
+	router.Use(requestid.Middleware(), traces.Middleware(server.tracer), metrics.Middleware(server.metrics), server.cors.Middleware())
+	server.upgrader = websocket.Upgrader{CheckOrigin: server.cors.CheckOrigin}
+
+	dispatcherOptions := []DispatcherOption{WithCollector(server.metrics)}
+	if server.authenticator != nil {
+		dispatcherOptions = append(dispatcherOptions, WithScopes(), WithAuditTrail(server.auditor))
+	}
+	server.dispatcher = NewDispatcher(useCases, logger, dispatcherOptions...)
+
+	server.httpServer = &http.Server{
+		Addr:    net.JoinHostPort(server.host, strconv.Itoa(port)),
+		Handler: router,
+	}
+
+	server.setupRoutes()
+	return server
+}
+
+// Handler retourne le routeur du serveur, utile pour l'héberger ou le tester sans listener
+func (s *Server) Handler() http.Handler {
+	return s.router
+}
+
+// Start démarre le serveur JSON-RPC
+// Il bloque jusqu'à la fin du drainage lorsque Stop est appelé
+func (s *Server) Start() error {
+	s.logger.Info("Starting JSON-RPC server", map[string]interface{}{
+		"port": s.port,
+	})
+
+	err := s.httpServer.ListenAndServe()
+	if !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+
+	<-s.stopped
+	return nil
+}
+
+// Stop arrête le serveur en laissant aux appels et sessions le délai de drainage
+// Elle est destinée à être enregistrée auprès de engine.Gracefull()
+func (s *Server) Stop() error {
+	ctx, cancel := context.WithTimeout(context.Background(), s.ShutdownTimeout())
+	defer cancel()
+
+	return s.Shutdown(ctx)
+}
+
+// ShutdownTimeout retourne le délai de drainage courant
+func (s *Server) ShutdownTimeout() time.Duration {
+	return time.Duration(s.shutdownTimeout.Load())
+}
+
+// SetShutdownTimeout met à jour le délai de drainage, y compris pendant l'exécution
+func (s *Server) SetShutdownTimeout(timeout time.Duration) {
+	s.shutdownTimeout.Store(int64(timeout))
+}
+
+// Shutdown arrête l'écoute puis draine les sessions ouvertes
+func (s *Server) Shutdown(ctx context.Context) error {
+	defer s.stopOnce.Do(func() { close(s.stopped) })
+
+	s.draining.Store(true)
+
+	s.logger.Info("Draining JSON-RPC server", map[string]interface{}{
+		"timeout":  s.ShutdownTimeout().String(),
+		"sessions": s.sessionCount(),
+	})
+
+	// Les connexions détournées (hijacked) ne sont pas suivies par http.Server
+	if err := s.httpServer.Shutdown(ctx); err != nil {
+		_ = s.httpServer.Close()
+	}
+
+	if err := s.Drain(ctx); err != nil {
+		return err
+	}
+
+	s.logger.Info("JSON-RPC server stopped")
+	return nil
+}
+
+// Drain refuse les nouvelles sessions, envoie une trame "going away"
+// à chaque client puis attend leur déconnexion ou l'expiration du contexte
+func (s *Server) Drain(ctx context.Context) error {
+	s.draining.Store(true)
+
+	s.closeSessions(websocket.CloseGoingAway, "server shutting down")
+
+	done := make(chan struct{})
+	go func() {
+		s.connsWg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		s.forceCloseSessions()
+		return fmt.Errorf("json-rpc server drain: %w", ctx.Err())
+	}
+}
+
+// setupRoutes configure les routes JSON-RPC
+func (s *Server) setupRoutes() {
+	s.RegisterRoutes(s.router)
+	s.healthEndpoints().Register(s.router)
+	// Métriques au format Prometheus, publiques comme la santé
+	if handler := metrics.Handler(s.metrics); handler != nil {
+		s.router.GET("/metrics", gin.WrapH(handler))
+	}
+}
+
+// RegisterRoutes monte les routes JSON-RPC sur le routeur fourni
+// Elle permet d'héberger le JSON-RPC sur un routeur partagé
+func (s *Server) RegisterRoutes(router gin.IRouter) {
+	router.POST("/rpc", s.limiter.IPMiddleware(source), authn.Middleware(s.authenticator, s.auditor, source), s.limiter.Middleware(source), s.handleHTTP)
+	router.GET("/rpc/ws", s.limiter.IPMiddleware(source), authn.Middleware(s.authenticator, s.auditor, source), s.limiter.StreamMiddleware(source), s.handleWebSocket)
+}
+
+// healthEndpoints endpoints de santé agrégeant les sondes du registre
+// Le serveur n'est plus prêt dès que le drainage commence
+func (s *Server) healthEndpoints() health.Endpoints {
+	return health.Endpoints{
+		Registry: s.health,
+		Service:  "live-semantic-rpc",
+		Version:  "1.0.0",
+		Draining: s.draining.Load,
+		Extra: func() gin.H {
+			return gin.H{"sessions": s.sessionCount(), "methods": s.dispatcher.Methods()}
+		},
+	}
+}
+
+// trackConn enregistre une session active ouverte par principal
+// Elle retourne false si le serveur est en cours d'arrêt
+func (s *Server) trackConn(conn *websocket.Conn, principal string) bool {
+	s.connsMu.Lock()
+	defer s.connsMu.Unlock()
+	if s.draining.Load() {
+		return false
+	}
+	s.conns[conn] = &lifecycle.Session{
+		ID:        requestid.New(),
+		Principal: principal,
+		Remote:    conn.RemoteAddr().String(),
+		OpenedAt:  time.Now().UTC(),
+	}
+	s.connsWg.Add(1)
+	s.metrics.RecordGauge(WebSocketSessions, float64(len(s.conns)), sessionsLabel)
+	return true
+}
+
+// untrackConn retire une session active
+func (s *Server) untrackConn(conn *websocket.Conn) {
+	s.connsMu.Lock()
+	defer s.connsMu.Unlock()
+	if _, ok := s.conns[conn]; ok {
+		delete(s.conns, conn)
+		s.connsWg.Done()
+		s.metrics.RecordGauge(WebSocketSessions, float64(len(s.conns)), sessionsLabel)
+	}
+}
+
+// State implémente lifecycle.Component
+func (s *Server) State() lifecycle.State {
+	select {
+	case <-s.stopped:
+		return lifecycle.StateStopped
+	default:
+	}
+	if s.draining.Load() {
+		return lifecycle.StateDraining
+	}
+	return lifecycle.StateRunning
+}
+
+// Sessions implémente lifecycle.SessionProvider, les sessions actives de la plus ancienne à la plus récente
+func (s *Server) Sessions() []lifecycle.Session {
+	s.connsMu.Lock()
+	defer s.connsMu.Unlock()
+
+	sessions := make([]lifecycle.Session, 0, len(s.conns))
+	for _, session := range s.conns {
+		sessions = append(sessions, *session)
+	}
+	slices.SortFunc(sessions, func(a, b lifecycle.Session) int {
+		return a.OpenedAt.Compare(b.OpenedAt)
+	})
+	return sessions
+}
+
+// Disconnect implémente lifecycle.SessionProvider : la session reçoit une trame de fermeture puis est fermée
+func (s *Server) Disconnect(id string) bool {
+	s.connsMu.Lock()
+	defer s.connsMu.Unlock()
+
+	for conn, session := range s.conns {
+		if session.ID != id {
+			continue
+		}
+		message := websocket.FormatCloseMessage(websocket.ClosePolicyViolation, "disconnected by an administrator")
+		_ = conn.WriteControl(websocket.CloseMessage, message, time.Now().Add(closeWriteTimeout))
+		_ = conn.Close()
+		s.logger.Info("JSON-RPC session disconnected", map[string]interface{}{
+			"session": id,
+			"remote":  session.Remote,
+		})
+		return true
+	}
+	return false
+}
+
+// sessionCount retourne le nombre de sessions actives
+func (s *Server) sessionCount() int {
+	s.connsMu.Lock()
+	defer s.connsMu.Unlock()
+	return len(s.conns)
+}
+
+// closeSessions envoie une trame de fermeture à chaque session active
+func (s *Server) closeSessions(code int, reason string) {
+	s.connsMu.Lock()
+	defer s.connsMu.Unlock()
+
+	message := websocket.FormatCloseMessage(code, reason)
+	for conn := range s.conns {
+		deadline := time.Now().Add(closeWriteTimeout)
+		if err := conn.WriteControl(websocket.CloseMessage, message, deadline); err != nil {
+			s.logger.Warn("Failed to send JSON-RPC close frame", map[string]interface{}{
+				"error":  err.Error(),
+				"remote": conn.RemoteAddr().String(),
+			})
+		}
+	}
+}
+
+// forceCloseSessions ferme brutalement les sessions qui n'ont pas répondu
+func (s *Server) forceCloseSessions() {
+	s.connsMu.Lock()
+	defer s.connsMu.Unlock()
+
+	for conn := range s.conns {
+		_ = conn.Close()
+	}
+}
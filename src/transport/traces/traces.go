@@ -0,0 +1,43 @@
+// Package traces démarre un span serveur pour chaque requête HTTP des transports réseau
+package traces
+
+import (
+	"live-semantic/src/tracing"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Middleware démarre un span serveur pour chaque requête, nommé d'après la méthode et le motif
+// de la route, par exemple GET /api/v1/tasks, qui continue la trace de l'en-tête traceparent
+// Le span est porté par le contexte de la requête jusqu'aux handlers. Un traceur nil appelle seulement les handlers suivants
+func Middleware(tracer *tracing.Tracer) gin.HandlerFunc {
+	if tracer == nil {
+		return func(c *gin.Context) { c.Next() }
+	}
+
+	return func(c *gin.Context) {
+		name := c.Request.Method
+		attributes := []tracing.Attribute{
+			tracing.Attr("http.request.method", c.Request.Method),
+			tracing.Attr("url.path", c.Request.URL.Path),
+			tracing.Attr("client.address", c.ClientIP()),
+		}
+		if route := c.FullPath(); route != "" {
+			name += " " + route
+			attributes = append(attributes, tracing.Attr("http.route", route))
+		}
+
+		ctx, span := tracer.Start(tracing.Extract(c.Request.Context(), c.Request.Header), name, tracing.WithKind(tracing.KindServer), tracing.WithAttributes(attributes...))
+		c.Request = c.Request.WithContext(ctx)
+		defer span.End()
+
+		c.Next()
+
+		status := c.Writer.Status()
+		span.SetAttributes(tracing.Attr("http.response.status_code", status))
+		if status >= http.StatusInternalServerError {
+			span.Fail(http.StatusText(status))
+		}
+	}
+}
@@ -0,0 +1,57 @@
+package traces_test
+
+import (
+	"live-semantic/src/tracing"
+	"live-semantic/src/transport/traces"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+// recorder garde les lots exportés
+type recorder struct {
+	batches [][]tracing.SpanData
+}
+
+func (r *recorder) Export(spans []tracing.SpanData) error {
+	r.batches = append(r.batches, spans)
+	return nil
+}
+
+func (r *recorder) Shutdown() error { return nil }
+
+func TestMiddleware(t *testing.T) {
+	t.Run("should start a server span named after the route and continue the remote trace", func(t *testing.T) {
+		// Given
+		gin.SetMode(gin.TestMode)
+		exporter := &recorder{}
+		tracer := tracing.New(tracing.WithExporter(exporter))
+		router := gin.New()
+		router.Use(traces.Middleware(tracer))
+		router.GET("/tasks/:id", func(c *gin.Context) {
+			_, span := tracing.Start(c.Request.Context(), "handler")
+			span.End()
+			c.Status(http.StatusBadGateway)
+		})
+		req := httptest.NewRequest(http.MethodGet, "/tasks/42", nil)
+		req.Header.Set(tracing.TraceparentHeader, "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+
+		// When
+		router.ServeHTTP(httptest.NewRecorder(), req)
+
+		// Then
+		if assert.Len(t, exporter.batches, 1) && assert.Len(t, exporter.batches[0], 2) {
+			handler, server := exporter.batches[0][0], exporter.batches[0][1]
+			assert.Equal(t, "GET /tasks/:id", server.Name)
+			assert.Equal(t, tracing.KindServer, server.Kind)
+			assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", server.TraceID.String())
+			assert.Equal(t, server.SpanID, handler.ParentSpanID)
+			assert.Contains(t, server.Attributes, tracing.Attr("http.route", "/tasks/:id"))
+			assert.Contains(t, server.Attributes, tracing.Attr("http.response.status_code", http.StatusBadGateway))
+			assert.Equal(t, tracing.StatusError, server.Status)
+		}
+	})
+}
@@ -3,22 +3,74 @@ package websocket
 import (
 	"context"
 	"encoding/json"
+	"live-semantic/src/auth"
 	"live-semantic/src/domain/dto"
+	"live-semantic/src/domain/uc"
+	"live-semantic/src/metrics"
+	"live-semantic/src/tracing"
 	"live-semantic/src/transport"
+	"live-semantic/src/transport/authn"
+	"live-semantic/src/transport/ratelimit"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/gorilla/websocket"
 )
 
+// WebSocketMessages compteur des messages reçus par type
+const WebSocketMessages = "websocket_messages_total"
+
+// EventSubscriptionClosed type du message signalant la fin d'un abonnement aux événements
+// que le client n'a pas demandée, lorsqu'il ne suit plus leur rythme : il se réabonne alors
+// avec le dernier identifiant reçu
+const EventSubscriptionClosed = "subscription.closed"
+
 // WSMessage représente un message WebSocket
 type WSMessage struct {
 	Type string                 `json:"type"`
 	Data map[string]interface{} `json:"data"`
 }
 
+// WSEvent message poussé par le serveur, typé contrairement aux réponses
+type WSEvent struct {
+	Type string `json:"type"`
+	Data any    `json:"data,omitempty"`
+}
+
+// session connexion d'un client et son abonnement aux événements
+// Les réponses et les événements étant écrits par des goroutines différentes, les écritures sont sérialisées
+type session struct {
+	conn    *websocket.Conn
+	writeMu sync.Mutex
+	// ctx se termine avec la connexion
+	ctx context.Context
+	// unsubscribe met fin à l'abonnement en cours
+	unsubscribe context.CancelFunc
+}
+
+// WriteJSON écrit un message sur la connexion
+func (s *session) WriteJSON(v any) error {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+	return s.conn.WriteJSON(v)
+}
+
 // handleWebSocket gère les connexions WebSocket
 func (s *Server) handleWebSocket(c *gin.Context) {
-	conn, err := upgrader.Upgrade(c.Writer, c.Request, nil)
+	// Refuser les nouvelles connexions pendant le drainage
+	if s.draining.Load() {
+		c.JSON(http.StatusServiceUnavailable, gin.H{
+			"success": false,
+			"error":   "server is shutting down",
+			"source":  "websocket",
+		})
+		return
+	}
+
+	conn, err := s.upgrader.Upgrade(c.Writer, c.Request, nil)
 	if err != nil {
 		s.logger.Error("Failed to upgrade to WebSocket", map[string]interface{}{
 			"error": err.Error(),
@@ -27,30 +79,91 @@ func (s *Server) handleWebSocket(c *gin.Context) {
 	}
 	defer conn.Close()
 
+	// Le principal authentifié à l'ouverture accompagne chaque message de la session
+	ctx := context.Background()
+	subject := ""
+	if principal, ok := auth.PrincipalFrom(c.Request.Context()); ok {
+		ctx = auth.WithPrincipal(ctx, principal)
+		subject = principal.Subject
+	}
+
+	if !s.trackConn(conn, subject) {
+		closeMessage := websocket.FormatCloseMessage(websocket.CloseGoingAway, "server shutting down")
+		_ = conn.WriteControl(websocket.CloseMessage, closeMessage, time.Now().Add(closeWriteTimeout))
+		return
+	}
+	defer s.untrackConn(conn)
+
 	s.logger.Info("New WebSocket connection established")
+	client := ratelimit.ClientKey(c)
+	sessionCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	sess := &session{conn: conn, ctx: sessionCtx}
 
 	for {
 		var msg WSMessage
 		err := conn.ReadJSON(&msg)
 		if err != nil {
+			if websocket.IsCloseError(err, websocket.CloseNormalClosure, websocket.CloseGoingAway) {
+				s.logger.Info("WebSocket connection closed")
+				break
+			}
 			s.logger.Error("Failed to read WebSocket message", map[string]interface{}{
 				"error": err.Error(),
 			})
 			break
 		}
 
-		// Traiter le message selon son type
-		switch msg.Type {
-		case "Task":
-			s.handleTaskMessage(conn, msg.Data)
-		default:
-			s.sendError(conn, "Unknown message type: "+msg.Type)
+		s.metrics.RecordCounter(WebSocketMessages, 1, metrics.Label{Name: "type", Value: messageType(msg.Type)})
+		s.handleMessage(ctx, sess, client, msg)
+	}
+}
+
+// handleMessage traite un message reçu dans sa propre trace, une session pouvant durer des heures
+func (s *Server) handleMessage(ctx context.Context, conn *session, client string, msg WSMessage) {
+	ctx, span := s.tracer.Start(ctx, "ws "+messageType(msg.Type), tracing.NewRoot(), tracing.WithKind(tracing.KindServer), tracing.WithAttributes(
+		tracing.Attr("ws.message.type", msg.Type),
+	))
+	defer span.End()
+
+	// Le débit est limité par type de message, surchargeable avec ws:<type>
+	if ok, wait := s.limiter.Allow(client, "ws:"+msg.Type); !ok {
+		span.Fail("rate limit exceeded")
+		s.sendError(conn, "rate limit exceeded, retry after "+strconv.Itoa(ratelimit.RetryAfter(wait))+"s")
+		return
+	}
+
+	// Traiter le message selon son type
+	switch msg.Type {
+	case "Task":
+		if err := s.authorize(ctx, uc.ActionTaskCreate, auth.ScopeTasksWrite); err != nil {
+			span.SetError(err)
+			s.sendError(conn, err.Error())
+			return
+		}
+		release, ok := s.limiter.Acquire(client)
+		if !ok {
+			span.Fail("too many concurrent requests")
+			s.sendError(conn, "too many concurrent requests")
+			return
+		}
+		defer release()
+		s.handleTaskMessage(ctx, conn, msg.Data)
+	case "Subscribe":
+		if err := s.authorize(ctx, uc.ActionEventSubscribe, auth.ScopeTasksRead); err != nil {
+			span.SetError(err)
+			s.sendError(conn, err.Error())
+			return
 		}
+		s.handleSubscribeMessage(ctx, conn, msg.Data)
+	default:
+		span.Fail("unknown message type")
+		s.sendError(conn, "Unknown message type: "+msg.Type)
 	}
 }
 
 // handleTaskMessage traite les messages d'exemple
-func (s *Server) handleTaskMessage(conn *websocket.Conn, data map[string]interface{}) {
+func (s *Server) handleTaskMessage(ctx context.Context, conn *session, data map[string]interface{}) {
 	// Convertir les données en TaskRequest
 	jsonData, _ := json.Marshal(data)
 	var req dto.TaskRequest
@@ -60,12 +173,12 @@ func (s *Server) handleTaskMessage(conn *websocket.Conn, data map[string]interfa
 	}
 
 	// Créer le handler de base
-	baseHandler := transport.NewBaseHandler(s.useCases, s.logger)
+	baseHandler := transport.NewBaseHandler(s.useCases, s.logger, transport.WithMetrics(s.metrics))
 
 	// Créer la requête transport
 	transportReq := transport.TransportRequest[dto.TaskRequest]{
 		Data:    req,
-		Context: context.Background(),
+		Context: ctx,
 		Source:  "websocket",
 	}
 
@@ -81,8 +194,71 @@ func (s *Server) handleTaskMessage(conn *websocket.Conn, data map[string]interfa
 	}
 }
 
+// handleSubscribeMessage abonne la session aux événements du domaine, en remplaçant l'abonnement en cours
+// La réponse précède les événements, poussés ensuite sous leur type jusqu'à la fin de la connexion
+func (s *Server) handleSubscribeMessage(ctx context.Context, conn *session, data map[string]interface{}) {
+	jsonData, _ := json.Marshal(data)
+	var req dto.EventRequest
+	if err := json.Unmarshal(jsonData, &req); err != nil {
+		s.sendError(conn, "Invalid data format")
+		return
+	}
+
+	if conn.unsubscribe != nil {
+		conn.unsubscribe()
+	}
+	// L'abonnement dure autant que la connexion et garde le principal de la session
+	subCtx, unsubscribe := context.WithCancel(conn.ctx)
+	conn.unsubscribe = unsubscribe
+
+	baseHandler := transport.NewBaseHandler(s.useCases, s.logger, transport.WithMetrics(s.metrics))
+	response := baseHandler.HandleSubscribeEvents(transport.TransportRequest[dto.EventRequest]{
+		Data:    req,
+		Context: subCtx,
+		Source:  "websocket",
+	})
+	if err := conn.WriteJSON(response); err != nil {
+		unsubscribe()
+		s.logger.Error("Failed to send WebSocket response", map[string]interface{}{
+			"error": err.Error(),
+		})
+		return
+	}
+	if !response.Success {
+		unsubscribe()
+		return
+	}
+
+	go func() {
+		for event := range response.Data.Events {
+			if err := conn.WriteJSON(WSEvent{Type: event.Type, Data: event}); err != nil {
+				unsubscribe()
+				return
+			}
+		}
+		// Le canal est fermé sans que l'abonnement ait pris fin : le client ne suit plus le rythme
+		if subCtx.Err() == nil {
+			unsubscribe()
+			_ = conn.WriteJSON(WSEvent{Type: EventSubscriptionClosed})
+		}
+	}()
+}
+
+// authorize vérifie le scope de l'action pour le principal de la session lorsque l'authentification est activée
+// Un refus est audité, le message n'atteignant pas le cas d'usage
+func (s *Server) authorize(ctx context.Context, action, scope string) error {
+	if s.authenticator == nil {
+		return nil
+	}
+	err := auth.Check(ctx, scope)
+	if err != nil {
+		authn.Deny(ctx, s.auditor, "websocket", action, err)
+	}
+	return err
+}
+
 // sendError envoie un message d'erreur
-func (s *Server) sendError(conn *websocket.Conn, message string) {
+func (s *Server) sendError(conn *session, message string) {
 	response := transport.TransportResponse[dto.TaskResponse]{
 		Success: false,
 		Error:   message,
@@ -94,3 +270,12 @@ func (s *Server) sendError(conn *websocket.Conn, message string) {
 		})
 	}
 }
+
+// messageType libellé du type d'un message dans les métriques
+// Les types inconnus sont regroupés pour que le client ne puisse pas créer de séries à volonté
+func messageType(t string) string {
+	if t == "Task" || t == "Subscribe" {
+		return t
+	}
+	return "unknown"
+}
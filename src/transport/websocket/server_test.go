@@ -0,0 +1,164 @@
+package websocket_test
+
+import (
+	"context"
+	"live-semantic/src/auth"
+	"live-semantic/src/domain/uc"
+	"live-semantic/src/logging"
+	ws "live-semantic/src/transport/websocket"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+)
+
+// newServer démarre le serveur WebSocket derrière un listener de test
+func newServer(t *testing.T, options ...ws.Option) (*ws.Server, *httptest.Server) {
+	t.Helper()
+
+	useCases, err := uc.NewUseCase(logging.Discard)
+	assert.NoError(t, err)
+	server := ws.NewServer(useCases, logging.Discard, 0, options...)
+	ts := httptest.NewServer(server.Handler())
+	t.Cleanup(ts.Close)
+	return server, ts
+}
+
+// dial ouvre une session et attend qu'elle soit suivie par le serveur
+func dial(t *testing.T, server *ws.Server, ts *httptest.Server) *websocket.Conn {
+	t.Helper()
+
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(ts.URL, "http")+"/ws", nil)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	t.Cleanup(func() { conn.Close() })
+	assert.Eventually(t, func() bool { return len(server.Sessions()) == 1 }, time.Second, 10*time.Millisecond)
+	return conn
+}
+
+func TestServer_Drain(t *testing.T) {
+	t.Run("should send a going away frame and wait for the client to leave", func(t *testing.T) {
+		// Given
+		server, ts := newServer(t)
+		conn := dial(t, server, ts)
+		// Le client lit la trame de fermeture et y répond, comme un client conforme
+		closed := make(chan error, 1)
+		go func() {
+			_, _, err := conn.ReadMessage()
+			closed <- err
+		}()
+
+		// When
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		defer cancel()
+		err := server.Drain(ctx)
+
+		// Then
+		assert.NoError(t, err)
+		assert.True(t, websocket.IsCloseError(<-closed, websocket.CloseGoingAway))
+		assert.Empty(t, server.Sessions())
+	})
+
+	t.Run("should close the sessions left open when the timeout expires", func(t *testing.T) {
+		// Given
+		server, ts := newServer(t)
+		dial(t, server, ts)
+
+		// When
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		defer cancel()
+		err := server.Drain(ctx)
+
+		// Then
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.Eventually(t, func() bool { return len(server.Sessions()) == 0 }, time.Second, 10*time.Millisecond)
+	})
+
+	t.Run("should refuse new sessions and report not ready", func(t *testing.T) {
+		// Given
+		server, ts := newServer(t)
+		assert.NoError(t, server.Drain(context.Background()))
+
+		// When
+		_, resp, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(ts.URL, "http")+"/ws", nil)
+		ready, readyErr := http.Get(ts.URL + "/readyz")
+
+		// Then
+		assert.ErrorIs(t, err, websocket.ErrBadHandshake)
+		if assert.NotNil(t, resp) {
+			assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+		}
+		if assert.NoError(t, readyErr) {
+			ready.Body.Close()
+			assert.Equal(t, http.StatusServiceUnavailable, ready.StatusCode)
+		}
+	})
+}
+
+func TestServer_Disconnect(t *testing.T) {
+	t.Run("should close the session with a policy violation frame", func(t *testing.T) {
+		// Given
+		server, ts := newServer(t)
+		conn := dial(t, server, ts)
+		id := server.Sessions()[0].ID
+
+		// When
+		disconnected := server.Disconnect(id)
+
+		// Then
+		assert.True(t, disconnected)
+		_ = conn.SetReadDeadline(time.Now().Add(time.Second))
+		_, _, err := conn.ReadMessage()
+		assert.True(t, websocket.IsCloseError(err, websocket.ClosePolicyViolation))
+		assert.False(t, server.Disconnect("unknown"))
+	})
+}
+
+// recorder garde les enregistrements d'audit reçus
+type recorder struct {
+	records []uc.AuditRecord
+}
+
+func (r *recorder) Record(ctx context.Context, record uc.AuditRecord) {
+	r.records = append(r.records, record)
+}
+
+func TestServer_Authorize(t *testing.T) {
+	t.Run("should refuse and audit a message without the scope of its action", func(t *testing.T) {
+		// Given
+		key, plain, err := auth.NewKey("viewer", []string{auth.ScopeTasksRead}, nil)
+		assert.NoError(t, err)
+		auditor := &recorder{}
+		server, ts := newServer(t, ws.WithAuthenticator(auth.New(auth.WithKeys(key))), ws.WithAuditor(auditor))
+		conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(ts.URL, "http")+"/ws", http.Header{auth.KeyHeader: {plain}})
+		if !assert.NoError(t, err) {
+			return
+		}
+		defer conn.Close()
+
+		// When
+		assert.NoError(t, conn.WriteJSON(ws.WSMessage{Type: "Task", Data: map[string]interface{}{"title": "title"}}))
+		_ = conn.SetReadDeadline(time.Now().Add(time.Second))
+		var response struct {
+			Success bool   `json:"success"`
+			Error   string `json:"error"`
+		}
+		assert.NoError(t, conn.ReadJSON(&response))
+
+		// Then
+		assert.False(t, response.Success)
+		assert.Contains(t, response.Error, auth.ScopeTasksWrite)
+		if assert.Len(t, auditor.records, 1) {
+			assert.Equal(t, "viewer", auditor.records[0].Principal)
+			assert.Equal(t, "websocket", auditor.records[0].Source)
+			assert.Equal(t, uc.ActionTaskCreate, auditor.records[0].Action)
+			assert.Equal(t, uc.OutcomeDenied, auditor.records[0].Outcome)
+		}
+		assert.Len(t, server.Sessions(), 1)
+	})
+}